@@ -1,6 +1,8 @@
 CREATE DATABASE reservations;
 CREATE DATABASE libraries;
 CREATE DATABASE ratings;
+CREATE DATABASE notifications;
+CREATE DATABASE webhooks;
 
 \c libraries
 
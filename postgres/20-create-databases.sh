@@ -5,7 +5,5 @@ set -e
 export VARIANT="v4"
 export SCRIPT_PATH=/docker-entrypoint-initdb.d/
 export PGPASSWORD=postgres
+# Схемы баз накатывают сами сервисы при старте (cmd/app migrate)
 psql -f "$SCRIPT_PATH/scripts/db-$VARIANT.sql"
-psql -f "$SCRIPT_PATH/scripts/02-library.sql"
-psql -f "$SCRIPT_PATH/scripts/03-reservation.sql"
-psql -f "$SCRIPT_PATH/scripts/04-rating.sql"
\ No newline at end of file
@@ -5,4 +5,10 @@ CREATE DATABASE libraries;
 GRANT ALL PRIVILEGES ON DATABASE libraries TO program;
 
 CREATE DATABASE ratings;
-GRANT ALL PRIVILEGES ON DATABASE ratings TO program;
\ No newline at end of file
+GRANT ALL PRIVILEGES ON DATABASE ratings TO program;
+
+CREATE DATABASE notifications;
+GRANT ALL PRIVILEGES ON DATABASE notifications TO program;
+
+CREATE DATABASE webhooks;
+GRANT ALL PRIVILEGES ON DATABASE webhooks TO program;
\ No newline at end of file
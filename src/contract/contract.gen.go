@@ -0,0 +1,1569 @@
+// Package contract provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package contract
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for BookReservationResponseStatus.
+const (
+	BookReservationResponseStatusEXPIRED  BookReservationResponseStatus = "EXPIRED"
+	BookReservationResponseStatusRENTED   BookReservationResponseStatus = "RENTED"
+	BookReservationResponseStatusRETURNED BookReservationResponseStatus = "RETURNED"
+)
+
+// Defines values for LibraryBookResponseCondition.
+const (
+	LibraryBookResponseConditionBAD       LibraryBookResponseCondition = "BAD"
+	LibraryBookResponseConditionEXCELLENT LibraryBookResponseCondition = "EXCELLENT"
+	LibraryBookResponseConditionGOOD      LibraryBookResponseCondition = "GOOD"
+)
+
+// Defines values for ReturnBookRequestCondition.
+const (
+	ReturnBookRequestConditionBAD       ReturnBookRequestCondition = "BAD"
+	ReturnBookRequestConditionEXCELLENT ReturnBookRequestCondition = "EXCELLENT"
+	ReturnBookRequestConditionGOOD      ReturnBookRequestCondition = "GOOD"
+)
+
+// Defines values for TakeBookResponseStatus.
+const (
+	TakeBookResponseStatusEXPIRED  TakeBookResponseStatus = "EXPIRED"
+	TakeBookResponseStatusLOST     TakeBookResponseStatus = "LOST"
+	TakeBookResponseStatusRENTED   TakeBookResponseStatus = "RENTED"
+	TakeBookResponseStatusRETURNED TakeBookResponseStatus = "RETURNED"
+)
+
+// BookInfo defines model for BookInfo.
+type BookInfo struct {
+	// Author Автор
+	Author string `json:"author,omitempty,omitzero"`
+
+	// BookUid UUID книги
+	BookUid openapi_types.UUID `json:"bookUid"`
+
+	// Genre Жанр
+	Genre string `json:"genre,omitempty,omitzero"`
+
+	// Name Название книги
+	Name string `json:"name"`
+}
+
+// BookReservationResponse defines model for BookReservationResponse.
+type BookReservationResponse struct {
+	Book    BookInfo        `json:"book"`
+	Library LibraryResponse `json:"library"`
+
+	// ReservationUid UUID бронирования
+	ReservationUid openapi_types.UUID `json:"reservationUid"`
+
+	// StartDate Дата начала бронирования
+	StartDate string `json:"startDate"`
+
+	// Status Статус бронирования книги
+	Status BookReservationResponseStatus `json:"status"`
+
+	// TillDate Дата окончания бронирования
+	TillDate string `json:"tillDate"`
+}
+
+// BookReservationResponseStatus Статус бронирования книги
+type BookReservationResponseStatus string
+
+// ErrorDescription defines model for ErrorDescription.
+type ErrorDescription struct {
+	Error string `json:"error,omitempty,omitzero"`
+	Field string `json:"field,omitempty,omitzero"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// Message Информация об ошибке
+	Message string `json:"message,omitempty,omitzero"`
+}
+
+// LibraryBookPaginationResponse defines model for LibraryBookPaginationResponse.
+type LibraryBookPaginationResponse struct {
+	Items []LibraryBookResponse `json:"items"`
+
+	// Page Номер страницы
+	Page int `json:"page"`
+
+	// PageSize Количество элементов на странице
+	PageSize int `json:"pageSize"`
+
+	// TotalElements Общее количество элементов
+	TotalElements int `json:"totalElements"`
+}
+
+// LibraryBookResponse defines model for LibraryBookResponse.
+type LibraryBookResponse struct {
+	// Author Автор
+	Author string `json:"author,omitempty,omitzero"`
+
+	// AvailableCount Количество книг, доступных для аренды в библиотеке
+	AvailableCount int `json:"availableCount"`
+
+	// BookUid UUID книги
+	BookUid openapi_types.UUID `json:"bookUid"`
+
+	// Condition Состояние книги
+	Condition LibraryBookResponseCondition `json:"condition"`
+
+	// Genre Жанр
+	Genre string `json:"genre,omitempty,omitzero"`
+
+	// Name Название книги
+	Name string `json:"name"`
+}
+
+// LibraryBookResponseCondition Состояние книги
+type LibraryBookResponseCondition string
+
+// LibraryPaginationResponse defines model for LibraryPaginationResponse.
+type LibraryPaginationResponse struct {
+	Items []LibraryResponse `json:"items"`
+
+	// Page Номер страницы
+	Page int `json:"page"`
+
+	// PageSize Количество элементов на странице
+	PageSize int `json:"pageSize"`
+
+	// TotalElements Общее количество элементов
+	TotalElements int `json:"totalElements"`
+}
+
+// LibraryResponse defines model for LibraryResponse.
+type LibraryResponse struct {
+	// Address Адрес библиотеки
+	Address string `json:"address"`
+
+	// City Город, в котором находится библиотека
+	City string `json:"city"`
+
+	// LibraryUid UUID библиотеки
+	LibraryUid openapi_types.UUID `json:"libraryUid"`
+
+	// Name Название библиотеки
+	Name string `json:"name"`
+}
+
+// ReturnBookRequest defines model for ReturnBookRequest.
+type ReturnBookRequest struct {
+	// Condition Состояние книги
+	Condition ReturnBookRequestCondition `binding:"required" json:"condition"`
+
+	// Date Дата возврата
+	Date string `binding:"required,datetime=2006-01-02" json:"date"`
+}
+
+// ReturnBookRequestCondition Состояние книги
+type ReturnBookRequestCondition string
+
+// TakeBookRequest defines model for TakeBookRequest.
+type TakeBookRequest struct {
+	// BookUid UUID книги
+	BookUid openapi_types.UUID `binding:"required" json:"bookUid"`
+
+	// LibraryUid UUID библиотеки
+	LibraryUid openapi_types.UUID `binding:"required" json:"libraryUid"`
+
+	// TillDate Дата окончания бронирования
+	TillDate string `binding:"required,datetime=2006-01-02" json:"tillDate"`
+}
+
+// TakeBookResponse defines model for TakeBookResponse.
+type TakeBookResponse struct {
+	Book    BookInfo           `json:"book"`
+	Library LibraryResponse    `json:"library"`
+	Rating  UserRatingResponse `json:"rating"`
+
+	// ReservationUid UUID бронирования
+	ReservationUid openapi_types.UUID `json:"reservationUid"`
+
+	// StartDate Дата начала бронирования
+	StartDate string `json:"startDate"`
+
+	// Status Статус бронирования книги
+	Status TakeBookResponseStatus `json:"status"`
+
+	// TillDate Дата окончания бронирования
+	TillDate string `json:"tillDate"`
+}
+
+// TakeBookResponseStatus Статус бронирования книги
+type TakeBookResponseStatus string
+
+// UserRatingResponse defines model for UserRatingResponse.
+type UserRatingResponse struct {
+	// Stars Количество здесь у пользователя
+	Stars int `json:"stars"`
+}
+
+// ValidationErrorResponse defines model for ValidationErrorResponse.
+type ValidationErrorResponse struct {
+	// Errors Массив полей с описанием ошибки
+	Errors []ErrorDescription `json:"errors,omitempty,omitzero"`
+
+	// Message Информация об ошибке
+	Message string `json:"message,omitempty,omitzero"`
+}
+
+// GetApiV1LibrariesParams defines parameters for GetApiV1Libraries.
+type GetApiV1LibrariesParams struct {
+	Page int `form:"page,omitempty" json:"page,omitempty,omitzero"`
+	Size int `form:"size,omitempty" json:"size,omitempty,omitzero"`
+
+	// City Город
+	City string `form:"city" json:"city"`
+}
+
+// GetApiV1LibrariesLibraryUidBooksParams defines parameters for GetApiV1LibrariesLibraryUidBooks.
+type GetApiV1LibrariesLibraryUidBooksParams struct {
+	Page    int  `form:"page,omitempty" json:"page,omitempty,omitzero"`
+	Size    int  `form:"size,omitempty" json:"size,omitempty,omitzero"`
+	ShowAll bool `form:"showAll,omitempty" json:"showAll,omitempty,omitzero"`
+}
+
+// PostApiV1ReservationsJSONRequestBody defines body for PostApiV1Reservations for application/json ContentType.
+type PostApiV1ReservationsJSONRequestBody = TakeBookRequest
+
+// PostApiV1ReservationsReservationUidReturnJSONRequestBody defines body for PostApiV1ReservationsReservationUidReturn for application/json ContentType.
+type PostApiV1ReservationsReservationUidReturnJSONRequestBody = ReturnBookRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// GetApiV1Libraries request
+	GetApiV1Libraries(ctx context.Context, params *GetApiV1LibrariesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetApiV1LibrariesLibraryUidBooks request
+	GetApiV1LibrariesLibraryUidBooks(ctx context.Context, libraryUid openapi_types.UUID, params *GetApiV1LibrariesLibraryUidBooksParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetApiV1Rating request
+	GetApiV1Rating(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetApiV1Reservations request
+	GetApiV1Reservations(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostApiV1ReservationsWithBody request with any body
+	PostApiV1ReservationsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostApiV1Reservations(ctx context.Context, body PostApiV1ReservationsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostApiV1ReservationsReservationUidReturnWithBody request with any body
+	PostApiV1ReservationsReservationUidReturnWithBody(ctx context.Context, reservationUid openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostApiV1ReservationsReservationUidReturn(ctx context.Context, reservationUid openapi_types.UUID, body PostApiV1ReservationsReservationUidReturnJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetApiV1Libraries(ctx context.Context, params *GetApiV1LibrariesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1LibrariesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetApiV1LibrariesLibraryUidBooks(ctx context.Context, libraryUid openapi_types.UUID, params *GetApiV1LibrariesLibraryUidBooksParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1LibrariesLibraryUidBooksRequest(c.Server, libraryUid, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetApiV1Rating(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1RatingRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetApiV1Reservations(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1ReservationsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostApiV1ReservationsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostApiV1ReservationsRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostApiV1Reservations(ctx context.Context, body PostApiV1ReservationsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostApiV1ReservationsRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostApiV1ReservationsReservationUidReturnWithBody(ctx context.Context, reservationUid openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostApiV1ReservationsReservationUidReturnRequestWithBody(c.Server, reservationUid, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostApiV1ReservationsReservationUidReturn(ctx context.Context, reservationUid openapi_types.UUID, body PostApiV1ReservationsReservationUidReturnJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostApiV1ReservationsReservationUidReturnRequest(c.Server, reservationUid, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetApiV1LibrariesRequest generates requests for GetApiV1Libraries
+func NewGetApiV1LibrariesRequest(server string, params *GetApiV1LibrariesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/libraries")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, params.Page); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "size", runtime.ParamLocationQuery, params.Size); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "city", runtime.ParamLocationQuery, params.City); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetApiV1LibrariesLibraryUidBooksRequest generates requests for GetApiV1LibrariesLibraryUidBooks
+func NewGetApiV1LibrariesLibraryUidBooksRequest(server string, libraryUid openapi_types.UUID, params *GetApiV1LibrariesLibraryUidBooksParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "libraryUid", runtime.ParamLocationPath, libraryUid)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/libraries/%s/books", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, params.Page); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "size", runtime.ParamLocationQuery, params.Size); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "showAll", runtime.ParamLocationQuery, params.ShowAll); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetApiV1RatingRequest generates requests for GetApiV1Rating
+func NewGetApiV1RatingRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/rating")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetApiV1ReservationsRequest generates requests for GetApiV1Reservations
+func NewGetApiV1ReservationsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/reservations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostApiV1ReservationsRequest calls the generic PostApiV1Reservations builder with application/json body
+func NewPostApiV1ReservationsRequest(server string, body PostApiV1ReservationsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostApiV1ReservationsRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostApiV1ReservationsRequestWithBody generates requests for PostApiV1Reservations with any type of body
+func NewPostApiV1ReservationsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/reservations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostApiV1ReservationsReservationUidReturnRequest calls the generic PostApiV1ReservationsReservationUidReturn builder with application/json body
+func NewPostApiV1ReservationsReservationUidReturnRequest(server string, reservationUid openapi_types.UUID, body PostApiV1ReservationsReservationUidReturnJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostApiV1ReservationsReservationUidReturnRequestWithBody(server, reservationUid, "application/json", bodyReader)
+}
+
+// NewPostApiV1ReservationsReservationUidReturnRequestWithBody generates requests for PostApiV1ReservationsReservationUidReturn with any type of body
+func NewPostApiV1ReservationsReservationUidReturnRequestWithBody(server string, reservationUid openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "reservationUid", runtime.ParamLocationPath, reservationUid)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/reservations/%s/return", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetApiV1LibrariesWithResponse request
+	GetApiV1LibrariesWithResponse(ctx context.Context, params *GetApiV1LibrariesParams, reqEditors ...RequestEditorFn) (*GetApiV1LibrariesResponse, error)
+
+	// GetApiV1LibrariesLibraryUidBooksWithResponse request
+	GetApiV1LibrariesLibraryUidBooksWithResponse(ctx context.Context, libraryUid openapi_types.UUID, params *GetApiV1LibrariesLibraryUidBooksParams, reqEditors ...RequestEditorFn) (*GetApiV1LibrariesLibraryUidBooksResponse, error)
+
+	// GetApiV1RatingWithResponse request
+	GetApiV1RatingWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetApiV1RatingResponse, error)
+
+	// GetApiV1ReservationsWithResponse request
+	GetApiV1ReservationsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetApiV1ReservationsResponse, error)
+
+	// PostApiV1ReservationsWithBodyWithResponse request with any body
+	PostApiV1ReservationsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsResponse, error)
+
+	PostApiV1ReservationsWithResponse(ctx context.Context, body PostApiV1ReservationsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsResponse, error)
+
+	// PostApiV1ReservationsReservationUidReturnWithBodyWithResponse request with any body
+	PostApiV1ReservationsReservationUidReturnWithBodyWithResponse(ctx context.Context, reservationUid openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsReservationUidReturnResponse, error)
+
+	PostApiV1ReservationsReservationUidReturnWithResponse(ctx context.Context, reservationUid openapi_types.UUID, body PostApiV1ReservationsReservationUidReturnJSONRequestBody, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsReservationUidReturnResponse, error)
+}
+
+type GetApiV1LibrariesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *LibraryPaginationResponse
+	JSON401      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1LibrariesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1LibrariesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetApiV1LibrariesLibraryUidBooksResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *LibraryBookPaginationResponse
+	JSON401      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1LibrariesLibraryUidBooksResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1LibrariesLibraryUidBooksResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetApiV1RatingResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserRatingResponse
+	JSON401      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1RatingResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1RatingResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetApiV1ReservationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]BookReservationResponse
+	JSON401      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1ReservationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1ReservationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostApiV1ReservationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TakeBookResponse
+	JSON400      *ValidationErrorResponse
+	JSON401      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostApiV1ReservationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostApiV1ReservationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostApiV1ReservationsReservationUidReturnResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *ErrorResponse
+	JSON404      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostApiV1ReservationsReservationUidReturnResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostApiV1ReservationsReservationUidReturnResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetApiV1LibrariesWithResponse request returning *GetApiV1LibrariesResponse
+func (c *ClientWithResponses) GetApiV1LibrariesWithResponse(ctx context.Context, params *GetApiV1LibrariesParams, reqEditors ...RequestEditorFn) (*GetApiV1LibrariesResponse, error) {
+	rsp, err := c.GetApiV1Libraries(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1LibrariesResponse(rsp)
+}
+
+// GetApiV1LibrariesLibraryUidBooksWithResponse request returning *GetApiV1LibrariesLibraryUidBooksResponse
+func (c *ClientWithResponses) GetApiV1LibrariesLibraryUidBooksWithResponse(ctx context.Context, libraryUid openapi_types.UUID, params *GetApiV1LibrariesLibraryUidBooksParams, reqEditors ...RequestEditorFn) (*GetApiV1LibrariesLibraryUidBooksResponse, error) {
+	rsp, err := c.GetApiV1LibrariesLibraryUidBooks(ctx, libraryUid, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1LibrariesLibraryUidBooksResponse(rsp)
+}
+
+// GetApiV1RatingWithResponse request returning *GetApiV1RatingResponse
+func (c *ClientWithResponses) GetApiV1RatingWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetApiV1RatingResponse, error) {
+	rsp, err := c.GetApiV1Rating(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1RatingResponse(rsp)
+}
+
+// GetApiV1ReservationsWithResponse request returning *GetApiV1ReservationsResponse
+func (c *ClientWithResponses) GetApiV1ReservationsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetApiV1ReservationsResponse, error) {
+	rsp, err := c.GetApiV1Reservations(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1ReservationsResponse(rsp)
+}
+
+// PostApiV1ReservationsWithBodyWithResponse request with arbitrary body returning *PostApiV1ReservationsResponse
+func (c *ClientWithResponses) PostApiV1ReservationsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsResponse, error) {
+	rsp, err := c.PostApiV1ReservationsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostApiV1ReservationsResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostApiV1ReservationsWithResponse(ctx context.Context, body PostApiV1ReservationsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsResponse, error) {
+	rsp, err := c.PostApiV1Reservations(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostApiV1ReservationsResponse(rsp)
+}
+
+// PostApiV1ReservationsReservationUidReturnWithBodyWithResponse request with arbitrary body returning *PostApiV1ReservationsReservationUidReturnResponse
+func (c *ClientWithResponses) PostApiV1ReservationsReservationUidReturnWithBodyWithResponse(ctx context.Context, reservationUid openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsReservationUidReturnResponse, error) {
+	rsp, err := c.PostApiV1ReservationsReservationUidReturnWithBody(ctx, reservationUid, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostApiV1ReservationsReservationUidReturnResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostApiV1ReservationsReservationUidReturnWithResponse(ctx context.Context, reservationUid openapi_types.UUID, body PostApiV1ReservationsReservationUidReturnJSONRequestBody, reqEditors ...RequestEditorFn) (*PostApiV1ReservationsReservationUidReturnResponse, error) {
+	rsp, err := c.PostApiV1ReservationsReservationUidReturn(ctx, reservationUid, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostApiV1ReservationsReservationUidReturnResponse(rsp)
+}
+
+// ParseGetApiV1LibrariesResponse parses an HTTP response from a GetApiV1LibrariesWithResponse call
+func ParseGetApiV1LibrariesResponse(rsp *http.Response) (*GetApiV1LibrariesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1LibrariesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest LibraryPaginationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetApiV1LibrariesLibraryUidBooksResponse parses an HTTP response from a GetApiV1LibrariesLibraryUidBooksWithResponse call
+func ParseGetApiV1LibrariesLibraryUidBooksResponse(rsp *http.Response) (*GetApiV1LibrariesLibraryUidBooksResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1LibrariesLibraryUidBooksResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest LibraryBookPaginationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetApiV1RatingResponse parses an HTTP response from a GetApiV1RatingWithResponse call
+func ParseGetApiV1RatingResponse(rsp *http.Response) (*GetApiV1RatingResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1RatingResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest UserRatingResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetApiV1ReservationsResponse parses an HTTP response from a GetApiV1ReservationsWithResponse call
+func ParseGetApiV1ReservationsResponse(rsp *http.Response) (*GetApiV1ReservationsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1ReservationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []BookReservationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostApiV1ReservationsResponse parses an HTTP response from a PostApiV1ReservationsWithResponse call
+func ParsePostApiV1ReservationsResponse(rsp *http.Response) (*PostApiV1ReservationsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostApiV1ReservationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest TakeBookResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ValidationErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostApiV1ReservationsReservationUidReturnResponse parses an HTTP response from a PostApiV1ReservationsReservationUidReturnWithResponse call
+func ParsePostApiV1ReservationsReservationUidReturnResponse(rsp *http.Response) (*PostApiV1ReservationsReservationUidReturnResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostApiV1ReservationsReservationUidReturnResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Получить список библиотек в городе
+	// (GET /api/v1/libraries)
+	GetApiV1Libraries(c *gin.Context, params GetApiV1LibrariesParams)
+	// Получить список книг в выбранной библиотеке
+	// (GET /api/v1/libraries/{libraryUid}/books)
+	GetApiV1LibrariesLibraryUidBooks(c *gin.Context, libraryUid openapi_types.UUID, params GetApiV1LibrariesLibraryUidBooksParams)
+	// Получить рейтинг пользователя
+	// (GET /api/v1/rating)
+	GetApiV1Rating(c *gin.Context)
+	// Получить информацию по всем взятым в прокат книгам пользователя
+	// (GET /api/v1/reservations)
+	GetApiV1Reservations(c *gin.Context)
+	// Взять книгу в библиотеке
+	// (POST /api/v1/reservations)
+	PostApiV1Reservations(c *gin.Context)
+	// Вернуть книгу
+	// (POST /api/v1/reservations/{reservationUid}/return)
+	PostApiV1ReservationsReservationUidReturn(c *gin.Context, reservationUid openapi_types.UUID)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandler       func(*gin.Context, error, int)
+}
+
+type MiddlewareFunc func(c *gin.Context)
+
+// GetApiV1Libraries operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1Libraries(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1LibrariesParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", c.Request.URL.Query(), &params.Size)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter size: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "city" -------------
+
+	if paramValue := c.Query("city"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument city is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "city", c.Request.URL.Query(), &params.City)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter city: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1Libraries(c, params)
+}
+
+// GetApiV1LibrariesLibraryUidBooks operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1LibrariesLibraryUidBooks(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "libraryUid" -------------
+	var libraryUid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "libraryUid", c.Param("libraryUid"), &libraryUid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter libraryUid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1LibrariesLibraryUidBooksParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", c.Request.URL.Query(), &params.Size)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter size: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "showAll" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "showAll", c.Request.URL.Query(), &params.ShowAll)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter showAll: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1LibrariesLibraryUidBooks(c, libraryUid, params)
+}
+
+// GetApiV1Rating operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1Rating(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1Rating(c)
+}
+
+// GetApiV1Reservations operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1Reservations(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1Reservations(c)
+}
+
+// PostApiV1Reservations operation middleware
+func (siw *ServerInterfaceWrapper) PostApiV1Reservations(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostApiV1Reservations(c)
+}
+
+// PostApiV1ReservationsReservationUidReturn operation middleware
+func (siw *ServerInterfaceWrapper) PostApiV1ReservationsReservationUidReturn(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "reservationUid" -------------
+	var reservationUid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "reservationUid", c.Param("reservationUid"), &reservationUid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter reservationUid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostApiV1ReservationsReservationUidReturn(c, reservationUid)
+}
+
+// GinServerOptions provides options for the Gin server.
+type GinServerOptions struct {
+	BaseURL      string
+	Middlewares  []MiddlewareFunc
+	ErrorHandler func(*gin.Context, error, int)
+}
+
+// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
+func RegisterHandlers(router gin.IRouter, si ServerInterface) {
+	RegisterHandlersWithOptions(router, si, GinServerOptions{})
+}
+
+// RegisterHandlersWithOptions creates http.Handler with additional options
+func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
+	errorHandler := options.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(c *gin.Context, err error, statusCode int) {
+			c.JSON(statusCode, gin.H{"msg": err.Error()})
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandler:       errorHandler,
+	}
+
+	router.GET(options.BaseURL+"/api/v1/libraries", wrapper.GetApiV1Libraries)
+	router.GET(options.BaseURL+"/api/v1/libraries/:libraryUid/books", wrapper.GetApiV1LibrariesLibraryUidBooks)
+	router.GET(options.BaseURL+"/api/v1/rating", wrapper.GetApiV1Rating)
+	router.GET(options.BaseURL+"/api/v1/reservations", wrapper.GetApiV1Reservations)
+	router.POST(options.BaseURL+"/api/v1/reservations", wrapper.PostApiV1Reservations)
+	router.POST(options.BaseURL+"/api/v1/reservations/:reservationUid/return", wrapper.PostApiV1ReservationsReservationUidReturn)
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xa227bVtZ+FWL//13JiJQPsgTMhRNrAg+MJJDtToHAFxS1ZbORSIWk3LiGAB+apoE9",
+	"9WRmMBgUTWf6BrJiwYoP8ius/UaDtUlJFLllU47jadzcJJRF7nX6vnWiNolhV2u2RS3PJblN4hprtKrz",
+	"y/u2/WzeKtt4TV/o1VqF4qVe99Zsh+QIvGH70GZbcC7Br2yHbUGT7bJtvGK7cEFkUrTtZ8tmieRIOWOU",
+	"jKmZspI29LIyWdSmlGwmnVHKM9mSYUyns8V0hshklVoOxaPf8rNewTk02YHEvoMmnLMdaOLx0IETaBKZ",
+	"WHqV3/wTF70DJ9CB9xKcsF22xbalB198IUFLykhsB7pwBk32kjRkUnPsGnU8k7phazZJibqGY9Y807bw",
+	"0L9CC59jW0Qm3kYNBbmeY1qrpBEyLPrY8vL8nAQncA4deAcdIpOy7VR1j+RIvW6WRGcFNscU+Ce3WSje",
+	"Nzz2xFtowjG08DnoQHtYj8ghDZk49HnddGiJ5J72DQrOXunfbxe/poaHQhEOBepSZ11HgQXq1mzLpRF0",
+	"4EF3ASUVs+jozgY3pVRyqOuSHEkr7ECCNygV74Rztg0nvuxdOL0nS3B0b0qWfOvuaUQmhultoPCfocvv",
+	"bXGVgsN9o2cmpjJTVEsrGYOqyuQMpUo2O6EpU1pWy5bLE0aW0pAZb6ADh3AKHeiyHWijfAk6cAZtDLaU",
+	"keAttOGC7bMf2Wvo+NY4g7gFnp6cnjT0CV0pG+WMMkknysrMdFlVMtNGpljUs9PlTJrIxPV0x5vTPZSc",
+	"VtOaoqmKmvW/8OrokkL+0VJ+DvFlViqROzUtzrcePv7foWWSI/+XGqSfVJB7Uv3EMxyHyx5Z8G/rg1Jg",
+	"s5iqh2wLuug5/n/AHXaQhLgh58So+A8OtabEofkKmnAKzUTC5hcfSzPTqjZCIHd6TNqvHPQ7yKuRQoaz",
+	"AbXqVeR9P3yF/NJy4RG/zH/1ZL6QnyMrAhUGUR5tchdOUAH2aiD6gwyPpKpIXPtuCQckpKifXwakE+a2",
+	"vOPYzlzYns0IbinegRcxl5RNWikJvmmMkhPOnMNCqtR19VWRc/8F5+w7rEY8Q33ve7ULhxJ02Q88I5xA",
+	"m8hJdAiogiR7oq+a1mXZ3PRo1SW5p2Plc31dNyt6sUIf2HXLIzntGinesK2SGRif/+pBfmEh/2jpVlL/",
+	"ikxqPAaaf7Fofut/8GxPr+QrtOq3SlostQXOCl0kyFhBVe1nrSBcuuPoG6ThqyAs9ahwm20FxSZg1Pds",
+	"bwACq14tUofI5IWyaivBH03LI42wZbGjf4Iulhf2CtrcmS3oSuwvcAptv8hwZ7V4ZosIh3Yy4RFPxjT4",
+	"BQ7Za2j7HUwSZZKIjWQR7teQH6JayUEUVy5n0Aje/G7YcjPtdNQHiTDZq2fYdWF3hfUPLuCc7bGX+KdT",
+	"TJFNtsUboyO2h8rDYax7SojZm+z4Q+GKl3LfFOiyA1EX36vb4Sg/fPwYy/b9WXHJ/i3OF2EfxMJ/CeXG",
+	"KVifetd+y4XocxH6JIvQqAL06c+skcLSM0hQWY4wyfPRJyanI8y/3MDYQX/n/XUXjmReKk74If6fzvxB",
+	"7iV+Cx22w7bZgUhcUyQu7LkRQ6hQ7yvrSNI0ncgtEWiGlO7n7F4MAg+KUFmgXt2x/M7oeZ26XgSXozqV",
+	"0tWbg1usmshcW6+ZimGX6Cq1FPrCc3TF01f9HYZplfC23MBjjUbPhtFzcQu6GJegu2omGn7H1kRGLTyz",
+	"Sv+QVtVpRdUUNU0a0eiGqy9XWxTLJf0ZHR3JsZvU6ySQMZZKN9KcXS/wH4fg19Plf7Cg+UgYHbSMQ7mo",
+	"b+DlkP28nP50ltO6x/GxyRd4Lsllpn6vK+u+Jy57cNmlToHf+XndfYPrbpksPF5cuqtb7z64RGlTgKjh",
+	"xBkm5jA5gm+SbWyO4Yh/3JfYrgQXeAvbh2PfOTxhnHL3VPUXZhUjpqmqTKqm5X9Sx5+wfPVENn+pV8wS",
+	"9+cVS3m++ReZ+DPP5dvQgVZgDLThvYSg7MIFdNh2rwWHs/CevtOf664geuythGA0v4VXBkhFatQd09tY",
+	"RM2CrEh1hzqzdW9t8OmPPVj/6c/YYnM78CT/24GsNc+r+QXfDH5f4JkeIq031UqLG65Hq0Qm69RxfYO0",
+	"eyoabNeopddMkiMT91Rexmq6t8ZVSuk1M7WupXzMB+FbpbxzxYjyaM9jLXlIvdma+aW20L8Tj3H0KvWo",
+	"4++PTJT5vE6djUF9C+Z1Pz546pjQFJ/qmt9GThXDX0soY9RYi7ATiOddQJg1nlOnYXWiAFnBm322cA+n",
+	"VTWYzzzqb2/1Wq1iGtzdqa9df2IbnJegKAr2fBwusbHPp1kXTgQ9Np/i3/WshzaiZ1LVbkzX4bwh0u+X",
+	"PtGaEjR7G3DowLHPSOgMsYvjLsyrpyvobLderfJ+gsC/ecrErhKXEPsS2x7PAzLxu/Kn5KHu0W/0DWn2",
+	"yTxZQSVi5EltDjrBRgrLyRh8Wug/ep8/eIfoJZSxZn8zW6kQAW2Ktl2huiWi5mWzIZeCmW0gZGgMGk3X",
+	"Kxq526DviHfLV1I4aNnEr2nuOnv7xrckaLE93j1i/3AOXXgvdkkCOg9GikuJ63d/5CNCQzS1CJz+H+yh",
+	"+IB8Du+CtkrQI95BMGwlszxJzAezwtUpuxC++QPjn6irHfU7wlhzK4iCsKe9wNGixbb9PrsFx+yA7bA9",
+	"/kGCC178cBGyM5gJm3B2ByEEnZh7fryue8YHoExqtisA2hPbHYE0vla+b5c2biwI0ZV1I9gqfqScFls3",
+	"JsZsd8R2ADo+Lm9Ox1Fz7pUIbfE1TgeOeujEH1XwgoS/sLgD9PlbQIX9PvDZ7mU/Ehkn7aY2hxc2jZTD",
+	"34zx9UJymhSGDvFfrsV76fEWfYLOMrZc+sDu8uaJHX+vKKT2pHATFSQ1CReGcAFt9gM2VcMv5F7zFXXz",
+	"twlr1GnyFnV6I0xNbVzv8n+a8B7HSfTi2Jzjb1vYboR3o/nFj3fWe1ivO5VggZRLpSq2oVfWbNfLzagz",
+	"KmmsNP47APr+UY3PMgAA",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,101 @@
+// Package contracttest checks what a service answers against the contract,
+// so that a handler drifting from the spec fails its tests.
+package contracttest
+
+import (
+	"bytes"
+	"context"
+	"contract"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// ProblemContentType marks errors written as RFC 7807 problem details. The
+// services answer errors this way rather than with the spec's
+// ErrorResponse, so such responses are not held against the spec.
+const ProblemContentType = "application/problem+json"
+
+var load = sync.OnceValues(func() (*openapi3.T, error) {
+	doc, err := contract.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	// Match operations by path alone, whatever host the test calls.
+	doc.Servers = nil
+	return doc, nil
+})
+
+var router = sync.OnceValues(func() (routers.Router, error) {
+	doc, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return legacy.NewRouter(doc)
+})
+
+// Check serves req with h and fails t unless the spec documents the
+// operation and the response: its status, content type and body.
+func Check(t testing.TB, h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	r, err := router()
+	if err != nil {
+		t.Fatalf("load contract: %v", err)
+	}
+	route, params, err := r.FindRoute(req)
+	if err != nil {
+		t.Fatalf("%s %s is not in the contract: %v", req.Method, req.URL.Path, err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Header().Get("Content-Type") == ProblemContentType {
+		return w
+	}
+
+	in := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      route,
+		},
+		Status:  w.Code,
+		Header:  w.Header(),
+		Body:    io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+		Options: &openapi3filter.Options{IncludeResponseStatus: true},
+	}
+	if err := openapi3filter.ValidateResponse(context.Background(), in); err != nil {
+		t.Errorf("%s %s answered against the contract: %v\n%s", req.Method, req.URL.Path, err, w.Body.String())
+	}
+	return w
+}
+
+// Schema fails t unless body is valid against the component schema name.
+// It is for services that serve a shape of the contract under paths of
+// their own.
+func Schema(t testing.TB, name string, body []byte) {
+	t.Helper()
+	doc, err := load()
+	if err != nil {
+		t.Fatalf("load contract: %v", err)
+	}
+	ref, ok := doc.Components.Schemas[name]
+	if !ok {
+		t.Fatalf("schema %s is not in the contract", name)
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	if err := ref.Value.VisitJSON(value); err != nil {
+		t.Errorf("body does not match %s: %v\n%s", name, err, body)
+	}
+}
@@ -0,0 +1,79 @@
+package contracttest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recorder stands in for the testing.T of a contract test to see whether it
+// failed.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(string, ...any) { r.failed = true }
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func answer(status int, contentType, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		fails   bool
+	}{
+		{"matches", answer(http.StatusOK, "application/json",
+			`{"page":1,"pageSize":1,"totalElements":1,"items":[{"libraryUid":"83575e12-7ce0-48ee-9931-51919ff3c9ee","name":"Lib","address":"Street","city":"Moscow"}]}`), false},
+		{"wrong type", answer(http.StatusOK, "application/json", `{"page":"first"}`), true},
+		{"wrong item", answer(http.StatusOK, "application/json", `{"items":[{"name":null}]}`), true},
+		{"undocumented status", answer(http.StatusTeapot, "application/json", `{}`), true},
+		{"problem", answer(http.StatusInternalServerError, ProblemContentType, `{"code":"INTERNAL_ERROR"}`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/libraries?city=Moscow", nil)
+			Check(rec, tt.handler, req)
+			if rec.failed != tt.fails {
+				t.Errorf("failed = %v, want %v", rec.failed, tt.fails)
+			}
+		})
+	}
+}
+
+func TestCheck_UnknownOperation(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("an operation missing from the contract passed")
+		}
+	}()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/libraries", nil)
+	Check(&recorder{TB: t}, answer(http.StatusNoContent, "", ""), req)
+}
+
+func TestSchema(t *testing.T) {
+	rec := &recorder{TB: t}
+	Schema(rec, "UserRatingResponse", []byte(`{"stars":75}`))
+	if rec.failed {
+		t.Error("a valid rating failed")
+	}
+
+	Schema(rec, "UserRatingResponse", []byte(`{"stars":150}`))
+	if !rec.failed {
+		t.Error("stars above the maximum passed")
+	}
+}
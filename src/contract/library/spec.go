@@ -0,0 +1,11 @@
+// Package library is the contract of library-system: its spec, which the
+// service serves as its docs, and the models and typed client generated
+// from it.
+package library
+
+import _ "embed"
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml openapi.yml
+
+//go:embed openapi.yml
+var Spec []byte
@@ -2,13 +2,27 @@ package main
 
 import (
 	"gateway-api/internal/client"
+	"gateway-api/internal/ratelimit"
 	"gateway-api/internal/server"
+	"gateway-api/internal/webhooks"
+	"platform/auth"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
 )
 
 type Config struct {
-	Server            server.Server      `envconfig:"SERVER"`
-	ReservationSystem client.Reservation `envconfig:"RESERVATION_SYSTEM"`
-	LibrarySystem     client.Library     `envconfig:"LIBRARY_SYSTEM"`
-	RatingSystem      client.Rating      `envconfig:"RATING_SYSTEM"`
-	RabbitMQ          string             `envconfig:"RABBITMQ"`
+	Server             server.Server       `envconfig:"SERVER"`
+	ReservationSystem  client.Reservation  `envconfig:"RESERVATION_SYSTEM"`
+	LibrarySystem      client.Library      `envconfig:"LIBRARY_SYSTEM"`
+	RatingSystem       client.Rating       `envconfig:"RATING_SYSTEM"`
+	NotificationSystem client.Notification `envconfig:"NOTIFICATION_SYSTEM"`
+	RabbitMQ           string              `envconfig:"RABBITMQ"`
+	ServiceAuth        auth.ServiceToken   `envconfig:"SERVICE_AUTH"`
+	Auth               auth.Config         `envconfig:"AUTH"`
+	Webhooks           webhooks.Config     `envconfig:"WEBHOOKS"`
+	Tracing            tracing.Config      `envconfig:"TRACING"`
+	Log                logging.Config      `envconfig:"LOG"`
+	RateLimit          ratelimit.Config    `envconfig:"RATE_LIMIT"`
+	Metrics            metrics.Config      `envconfig:"METRICS"`
 }
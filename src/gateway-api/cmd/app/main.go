@@ -3,54 +3,164 @@ package main
 import (
 	"context"
 	"gateway-api/internal/client"
+	"gateway-api/internal/migrations"
+	"gateway-api/internal/rabbitmq"
+	"gateway-api/internal/ratelimit"
+	"gateway-api/internal/repo"
 	"gateway-api/internal/server"
+	"gateway-api/internal/service"
+	"gateway-api/internal/webhooks"
+	"os"
+	"os/signal"
+	"platform/auth"
+	"platform/logging"
+	"platform/metrics"
+	"platform/migrate"
+	"platform/postgres"
+	"platform/tracing"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/streadway/amqp"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
-	_, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	var cfg Config
 	if err := envconfig.Process("", &cfg); err != nil {
 		panic(err)
 	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, "gateway-api", cfg.Tracing)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Warn("failed to flush traces")
+		}
+	}()
 
 	clientLibrary := client.NewLibrary(cfg.LibrarySystem.BaseURL)
 	clientRating := client.NewRating(cfg.RatingSystem.BaseURL)
 	clientReservation := client.NewReservation(cfg.ReservationSystem.BaseURL)
+	clientNotification := client.NewNotification(cfg.NotificationSystem.BaseURL)
 
-	conn, err := amqp.Dial(cfg.RabbitMQ)
-	if err != nil {
-		log.WithError(err).Errorf("Failed to connect to RabbitMQ")
+	rmq := rabbitmq.NewManager(cfg.RabbitMQ)
+
+	// Webhooks are the only state the gateway keeps, in a database of its
+	// own; without one the gateway stays stateless.
+	var webhookService *service.WebhookService
+	var webhookDB postgres.Client
+	if cfg.Webhooks.DB != "" {
+		db, err := postgres.Connect(ctx, postgres.Config{ConnStr: cfg.Webhooks.DB})
+		if err != nil {
+			log.WithError(err).Fatal("Failed to connect to webhooks database")
+		}
+		defer db.Close()
+		webhookDB = db
+		prometheus.MustRegister(db.Collector())
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := migrate.Command(ctx, db.Conn(), migrations.FS, os.Args[2:], os.Stdout); err != nil {
+				log.WithError(err).Fatal("migration failed")
+			}
+			return
+		}
+		if err := migrate.OnStart(ctx, db.Conn(), migrations.FS); err != nil {
+			log.WithError(err).Fatal("failed to apply migrations")
+		}
+
+		webhookRepo := repo.NewWebhookRepo(db)
+		dispatcher := webhooks.NewDispatcher(webhookRepo, rmq, cfg.Webhooks)
+		dispatcher.Attach(rmq)
+		webhookService = service.NewWebhookService(webhookRepo, dispatcher)
+	} else {
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			log.Fatal("WEBHOOKS_DB is not set, there is nothing to migrate")
+		}
+		log.Warn("WEBHOOKS_DB is not set, webhooks are off")
+	}
+
+	// Replicas share the quotas through Redis; a lone gateway can keep them
+	// to itself.
+	var limits ratelimit.Store = ratelimit.NewMemory()
+	var limitsDB *redis.Client
+	if cfg.RateLimit.Redis != "" {
+		opts, err := redis.ParseURL(cfg.RateLimit.Redis)
+		if err != nil {
+			log.WithError(err).Fatal("invalid RATE_LIMIT_REDIS")
+		}
+		limitsDB = redis.NewClient(opts)
+		defer limitsDB.Close()
+		limits = ratelimit.NewRedis(limitsDB)
+	}
+
+	// the calls that move stock and reservations can't be made without it
+	if err := cfg.ServiceAuth.Validate(); err != nil {
+		log.WithError(err).Fatal("SERVICE_AUTH_* must name the client credentials of the gateway")
 	}
-	defer conn.Close()
 
-	ch, err := conn.Channel()
+	verifier, err := auth.NewVerifier(cfg.Auth)
 	if err != nil {
-		log.WithError(err).Errorf("Failed to open a channel")
+		log.WithError(err).Fatal("failed to set up token verification")
 	}
-	defer ch.Close()
 
 	srv, err := server.New(
 		cfg.Server.Host,
 		cfg.Server.Port,
+		cfg.Server.Shutdown,
+		cfg.Server.TrustedProxies,
 		clientLibrary,
 		clientRating,
 		clientReservation,
-		ch)
+		clientNotification,
+		webhookService,
+		rmq,
+		&cfg.ServiceAuth,
+		verifier,
+		ratelimit.New(limits, cfg.RateLimit))
 	if err != nil {
-		log.WithError(err).Error("failed to initialize server")
+		log.WithError(err).Fatal("failed to initialize server")
 	}
 
-	err = srv.Run()
-	if err != nil {
-		log.WithError(err).Error("server shutdown")
-		return
+	prometheus.MustRegister(rmq.DepthCollector(srv.RetryQueues()...))
+	go func() {
+		if err := metrics.Serve(ctx, cfg.Metrics); err != nil {
+			log.WithError(err).Error("failed to serve metrics")
+		}
+	}()
+
+	srv.Health().Critical("rabbitmq", rmq.Check())
+	if webhookDB != nil {
+		srv.Health().Optional("webhooks-db", webhookDB.Ping)
+	}
+	if limitsDB != nil {
+		srv.Health().Optional("rate-limit-redis", func(ctx context.Context) error {
+			return limitsDB.Ping(ctx).Err()
+		})
 	}
 
+	// The broker connection outlives the signal: requests being drained may
+	// still publish, so it is closed only once Run is done with them.
+	rmqCtx, stopRmq := context.WithCancel(context.Background())
+	rmqDone := make(chan struct{})
+	go func() {
+		defer close(rmqDone)
+		rmq.Run(rmqCtx)
+	}()
+
+	if err := srv.Run(ctx); err != nil {
+		log.WithError(err).Error("server shutdown")
+	}
+	stopRmq()
+	<-rmqDone
 }
@@ -1,70 +0,0 @@
-package auth
-
-import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
-	"time"
-
-	"github.com/MicahParks/keyfunc"
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
-)
-
-var (
-	jwksURL = "https://sanchiko.eu.auth0.com/.well-known/jwks.json"
-	jwks    *keyfunc.JWKS
-)
-
-func init() {
-	var err error
-	jwks, err = keyfunc.Get(jwksURL, keyfunc.Options{
-		RefreshInterval: time.Hour,
-		RefreshErrorHandler: func(err error) {
-		},
-	})
-	if err != nil {
-		panic("Failed to get JWKS from Auth0: " + err.Error())
-	}
-}
-
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			return
-		}
-
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header format must be Bearer {token}"})
-			return
-		}
-
-		token, err := jwt.Parse(parts[1], jwks.Keyfunc)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token: " + err.Error()})
-			return
-		}
-
-		if !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
-
-		c.Set("token", authHeader)
-
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			pretty, _ := json.MarshalIndent(claims, "", "  ")
-			fmt.Println(string(pretty))
-			c.Set("claims", claims)
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
-			return
-		}
-
-		c.Next()
-	}
-}
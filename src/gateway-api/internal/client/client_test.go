@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"net/http/httptest"
+	"platform/problem"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// problemResponse is a problem answer as the generated clients hand it
+// over, with its body read already.
+func problemResponse(status int, code problem.Code) (*http.Response, []byte) {
+	resp := &http.Response{StatusCode: status, Header: http.Header{"Content-Type": {problem.ContentType}}}
+	return resp, []byte(`{"code":"` + string(code) + `","detail":"from the backend"}`)
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   problem.Code
+		want   error
+	}{
+		{"not found", http.StatusNotFound, problem.NotFound, ext.ReservationNotFoundError},
+		{"forbidden", http.StatusForbidden, problem.Forbidden, ext.ForbiddenError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, statusError(problemResponse(tt.status, tt.code)), tt.want)
+		})
+	}
+
+	t.Run("other conflicts pass through", func(t *testing.T) {
+		err := statusError(problemResponse(http.StatusConflict, problem.Conflict))
+		assert.NotErrorIs(t, err, ext.ReservationNotFoundError)
+		var p *problem.Problem
+		if assert.ErrorAs(t, err, &p) {
+			assert.Equal(t, problem.Conflict, p.Code)
+			assert.Equal(t, "from the backend", p.Detail)
+		}
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	p := &problem.Problem{Status: http.StatusNotFound, Code: problem.NotFound}
+	assert.ErrorIs(t, notFound(p, ext.BookNotFoundError), ext.BookNotFoundError)
+
+	// anything else the backend reports is kept as it is
+	p = &problem.Problem{Status: http.StatusBadRequest, Code: problem.BadRequest}
+	err := notFound(p, ext.BookNotFoundError)
+	assert.NotErrorIs(t, err, ext.BookNotFoundError)
+	assert.Same(t, p, err)
+}
+
+func TestLibrary_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", problem.ContentType)
+		if strings.HasPrefix(r.URL.Path, "/api/v1/libraries/") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":404,"code":"NOT_FOUND"}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":503,"code":"SERVICE_UNAVAILABLE"}`))
+	}))
+	defer srv.Close()
+	lib := NewLibrary(srv.URL)
+
+	_, err := lib.GetLibraryByUID(context.Background(), uuid.New(), "")
+	assert.ErrorIs(t, err, ext.LibraryNotFoundError)
+
+	_, err = lib.GetBookByUID(context.Background(), uuid.New(), uuid.New(), "")
+	assert.NotErrorIs(t, err, ext.BookNotFoundError)
+	var p *problem.Problem
+	if assert.ErrorAs(t, err, &p) {
+		assert.Equal(t, problem.ServiceUnavailable, p.Code)
+	}
+}
+
+func TestLibrary_GetBookByUID_CountsInLibrary(t *testing.T) {
+	bookUID, libraryUID := uuid.New(), uuid.New()
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"bookUid":"` + bookUID.String() + `","availableCount":2}`))
+	}))
+	defer srv.Close()
+
+	book, err := NewLibrary(srv.URL).GetBookByUID(context.Background(), bookUID, libraryUID, "")
+	require.NoError(t, err)
+	assert.Equal(t, "libraryUid="+libraryUID.String(), query)
+	assert.Equal(t, 2, book.AvailableCount)
+}
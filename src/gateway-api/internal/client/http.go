@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"platform/logging"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Transport sends the request id and the trace context along with every
+// request and records a client span for it.
+var Transport http.RoundTripper = otelhttp.NewTransport(requestIDTransport{next: http.DefaultTransport})
+
+var tracedClient = &http.Client{Transport: Transport}
+
+// requestIDTransport passes the id of the request being served on to the
+// service called.
+type requestIDTransport struct {
+	next http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := logging.RequestID(req.Context())
+	if id == "" || req.Header.Get(logging.RequestIDHeader) != "" {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(logging.RequestIDHeader, id)
+	return t.next.RoundTrip(req)
+}
+
+// authorize passes the Authorization header of the user, or of the gateway
+// itself, on to the service called.
+func authorize(token string) func(context.Context, *http.Request) error {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", token)
+		return nil
+	}
+}
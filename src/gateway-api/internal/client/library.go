@@ -1,30 +1,54 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"contract/library"
 	"fmt"
-	"gateway-api/internal/dto"
 	"gateway-api/pkg/circuit"
+	"gateway-api/pkg/ext"
 	"net/http"
-	"net/url"
+	"platform/problem"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Library struct {
 	BaseURL    string `envconfig:"BASE_URL"`
 	HTTPClient *http.Client
 	GetBreaker *circuit.Breaker
+	API        *library.ClientWithResponses
 }
 
 func NewLibrary(baseURL string) *Library {
+	// only the options can fail, and these don't
+	api, _ := library.NewClientWithResponses(baseURL, library.WithHTTPClient(tracedClient))
 	return &Library{
 		BaseURL:    baseURL,
-		HTTPClient: http.DefaultClient,
+		HTTPClient: tracedClient,
 		GetBreaker: circuit.NewBreaker(3, 5*time.Second, 60*time.Second, 3),
+		API:        api,
 	}
 }
 
+// bookError turns running out of copies into the error the gateway reports
+// to the user.
+func bookError(p *problem.Problem) error {
+	if p.Code == problem.BookNotAvailable {
+		return ext.BookNotAvailableError
+	}
+	return p
+}
+
+// notFound replaces a NOT_FOUND problem with the typed error for the
+// looked-up entity.
+func notFound(p *problem.Problem, err error) error {
+	if p.Code == problem.NotFound {
+		return err
+	}
+	return p
+}
+
 func (c *Library) isHealthy() bool {
 	resp, err := c.HTTPClient.Get(fmt.Sprintf("%s/manage/health", c.BaseURL))
 	if err != nil {
@@ -34,167 +58,146 @@ func (c *Library) isHealthy() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (c *Library) GetLibraries(city string, page, size int, token string) (*dto.LibraryPaginationResponse, error) {
-	action := func() (*dto.LibraryPaginationResponse, error) {
-		u, _ := url.Parse(fmt.Sprintf("%s/api/v1/libraries", c.BaseURL))
-		q := u.Query()
-		q.Set("city", city)
-		if page > 0 {
-			q.Set("page", fmt.Sprintf("%d", page))
-		}
-		if size > 0 {
-			q.Set("size", fmt.Sprintf("%d", size))
-		}
-		u.RawQuery = q.Encode()
-
-		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-		if err != nil {
-			return nil, err
-		}
-
-		req.Header.Set("Authorization", token)
-
-		resp, err := c.HTTPClient.Do(req)
+func (c *Library) GetLibraries(ctx context.Context, params library.ListLibrariesParams, token string) (*library.LibraryPaginationResponse, error) {
+	action := func() (*library.LibraryPaginationResponse, error) {
+		resp, err := c.API.ListLibrariesWithResponse(ctx, &params, authorize(token))
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
-
-		var result dto.LibraryPaginationResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
+		if resp.JSON200 == nil {
+			return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
 		}
-		return &result, nil
+		return resp.JSON200, nil
 	}
 
-	fallback := func() *dto.LibraryPaginationResponse {
-		return &dto.LibraryPaginationResponse{}
+	fallback := func() *library.LibraryPaginationResponse {
+		return &library.LibraryPaginationResponse{}
 	}
 
 	return circuit.WithCircuitBreaker(c.GetBreaker, action, fallback, c.isHealthy)
 }
 
-func (c *Library) GetLibraryBooks(libraryUid string, page, size int, showAll bool, token string) (*dto.LibraryBookPaginationResponse, error) {
-	action := func() (*dto.LibraryBookPaginationResponse, error) {
-		u, _ := url.Parse(fmt.Sprintf("%s/api/v1/libraries/%s/books", c.BaseURL, libraryUid))
-		q := u.Query()
-		if page > 0 {
-			q.Set("page", fmt.Sprintf("%d", page))
-		}
-		if size > 0 {
-			q.Set("size", fmt.Sprintf("%d", size))
-		}
-		if showAll {
-			q.Set("showAll", "true")
-		}
-		u.RawQuery = q.Encode()
-
-		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+func (c *Library) GetLibraryBooks(ctx context.Context, libraryUid uuid.UUID, params library.ListBooksParams, token string) (*library.BookPaginationResponse, error) {
+	action := func() (*library.BookPaginationResponse, error) {
+		resp, err := c.API.ListBooksWithResponse(ctx, libraryUid, &params, authorize(token))
 		if err != nil {
 			return nil, err
 		}
-
-		req.Header.Set("Authorization", token)
-
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return nil, err
+		if resp.JSON200 == nil {
+			return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
 		}
-		defer resp.Body.Close()
-
-		var result dto.LibraryBookPaginationResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
-		}
-		return &result, nil
+		return resp.JSON200, nil
 	}
 
-	fallback := func() *dto.LibraryBookPaginationResponse {
-		return &dto.LibraryBookPaginationResponse{}
+	fallback := func() *library.BookPaginationResponse {
+		return &library.BookPaginationResponse{}
 	}
 
 	return circuit.WithCircuitBreaker(c.GetBreaker, action, fallback, c.isHealthy)
-
 }
 
-func (c *Library) GetLibraryByUID(libraryUid string, token string) (*dto.LibraryResponse, error) {
-
-	req, _ := http.NewRequest(
-		http.MethodGet,
-		fmt.Sprintf("%s/api/v1/libraries/%s/", c.BaseURL, libraryUid),
-		nil,
-	)
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
+func (c *Library) GetLibraryByUID(ctx context.Context, libraryUid uuid.UUID, token string) (*library.LibraryResponse, error) {
+	resp, err := c.API.GetLibraryWithResponse(ctx, libraryUid, authorize(token))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var result dto.LibraryResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if resp.JSON200 == nil {
+		return nil, notFound(problem.DecodeBody(resp.HTTPResponse, resp.Body), ext.LibraryNotFoundError)
 	}
-	return &result, nil
+	return resp.JSON200, nil
 }
 
-func (c *Library) GetBookByUID(bookUid string, token string) (*dto.BookResponse, error) {
-
-	req, _ := http.NewRequest(
-		http.MethodGet,
-		fmt.Sprintf("%s/api/v1/books/%s/", c.BaseURL, bookUid),
-		nil,
-	)
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
+// GetBookByUID returns the book with the number of its copies available in
+// libraryUid.
+func (c *Library) GetBookByUID(ctx context.Context, bookUid, libraryUid uuid.UUID, token string) (*library.BookResponse, error) {
+	resp, err := c.API.GetBookWithResponse(ctx, bookUid, &library.GetBookParams{LibraryUid: libraryUid}, authorize(token))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	if resp.JSON200 == nil {
+		return nil, notFound(problem.DecodeBody(resp.HTTPResponse, resp.Body), ext.BookNotFoundError)
+	}
+	return resp.JSON200, nil
+}
 
-	var result dto.BookResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+// UpdateBookCount shelves delta new copies in condition, or retires -delta
+// available ones. An empty condition shelves them in that of the book.
+func (c *Library) UpdateBookCount(ctx context.Context, libraryUid, bookUid uuid.UUID, delta int, condition library.Condition, token string) error {
+	params := library.ChangeBookCountParams{Condition: condition}
+	resp, err := c.API.ChangeBookCountWithResponse(ctx, libraryUid, bookUid, delta, &params, authorize(token))
+	if err != nil {
+		return err
 	}
-	return &result, nil
+	if resp.StatusCode() != http.StatusOK {
+		return problem.DecodeBody(resp.HTTPResponse, resp.Body)
+	}
+	return nil
 }
 
-func (c *Library) UpdateBookCondition(bookUid string, condition string, token string) error {
-	reqBody, _ := json.Marshal(map[string]string{"condition": condition})
-	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/books/%s/condition", c.BaseURL, bookUid), bytes.NewBuffer(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", token)
+func (c *Library) ReserveCopy(ctx context.Context, libraryUid, bookUid uuid.UUID, token string) (*library.CopyResponse, error) {
+	resp, err := c.API.ReserveCopyWithResponse(ctx, libraryUid, bookUid, authorize(token))
+	if err != nil {
+		return nil, ext.ServiceUnavailableError
+	}
+	if resp.JSON200 == nil {
+		return nil, bookError(problem.DecodeBody(resp.HTTPResponse, resp.Body))
+	}
+	return resp.JSON200, nil
+}
 
-	resp, err := c.HTTPClient.Do(req)
+func (c *Library) GetCopy(ctx context.Context, copyUid uuid.UUID, token string) (*library.CopyResponse, error) {
+	resp, err := c.API.GetCopyWithResponse(ctx, copyUid, authorize(token))
 	if err != nil {
-		return err
+		return nil, ext.ServiceUnavailableError
 	}
-	defer resp.Body.Close()
+	if resp.JSON200 == nil {
+		return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update book condition, status: %d", resp.StatusCode)
+// ReleaseCopy puts a copy back on the shelf. An empty condition leaves the
+// one the copy had.
+func (c *Library) ReleaseCopy(ctx context.Context, copyUid uuid.UUID, condition library.Condition, token string) error {
+	resp, err := c.API.ReleaseCopyWithResponse(ctx, copyUid, library.ReleaseCopyRequest{Condition: condition}, authorize(token))
+	if err != nil {
+		return ext.ServiceUnavailableError
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return problem.DecodeBody(resp.HTTPResponse, resp.Body)
 	}
 	return nil
 }
 
-func (c *Library) UpdateBookCount(libraryUid, bookUid string, delta int, token string) error {
-	req, _ := http.NewRequest(
-		http.MethodPut,
-		fmt.Sprintf("%s/api/v1/library/%s/books/%s/count/%d/", c.BaseURL, libraryUid, bookUid, delta),
-		nil,
-	)
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
+func (c *Library) RequestTransfer(ctx context.Context, transfer library.TransferRequest, token string) (*library.TransferResponse, error) {
+	resp, err := c.API.RequestTransferWithResponse(ctx, transfer, authorize(token))
 	if err != nil {
-		return err
+		return nil, ext.ServiceUnavailableError
 	}
-	defer resp.Body.Close()
+	if resp.JSON201 == nil {
+		return nil, bookError(problem.DecodeBody(resp.HTTPResponse, resp.Body))
+	}
+	return resp.JSON201, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update book count, status: %d", resp.StatusCode)
+func (c *Library) CancelTransfer(ctx context.Context, transferUid uuid.UUID, token string) error {
+	resp, err := c.API.CancelTransferWithResponse(ctx, transferUid, authorize(token))
+	if err != nil {
+		return ext.ServiceUnavailableError
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return problem.DecodeBody(resp.HTTPResponse, resp.Body)
 	}
 	return nil
 }
+
+func (c *Library) ListTransfers(ctx context.Context, copyUid uuid.UUID, token string) ([]library.TransferResponse, error) {
+	resp, err := c.API.ListTransfersWithResponse(ctx, &library.ListTransfersParams{CopyUid: copyUid}, authorize(token))
+	if err != nil {
+		return nil, ext.ServiceUnavailableError
+	}
+	if resp.JSON200 == nil {
+		return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
+	}
+	return *resp.JSON200, nil
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"contract/notification"
+	"fmt"
+	"gateway-api/pkg/circuit"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"platform/problem"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	BaseURL    string `envconfig:"BASE_URL"`
+	HTTPClient *http.Client
+	GetBreaker *circuit.Breaker
+	API        *notification.ClientWithResponses
+}
+
+func NewNotification(baseURL string) *Notification {
+	// only the options can fail, and these don't
+	api, _ := notification.NewClientWithResponses(baseURL, notification.WithHTTPClient(tracedClient))
+	return &Notification{
+		BaseURL:    baseURL,
+		HTTPClient: tracedClient,
+		GetBreaker: circuit.NewBreaker(3, 5*time.Second, 60*time.Second, 3),
+		API:        api,
+	}
+}
+
+func (c *Notification) isHealthy() bool {
+	resp, err := c.HTTPClient.Get(fmt.Sprintf("%s/manage/health", c.BaseURL))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// unavailable reports a notification-system that can't be reached.
+func unavailable(err error) error {
+	return fmt.Errorf("%w: %v", ext.NotificationServiceUnavailableError, err)
+}
+
+// failed is the error for an answer of notification-system that isn't a
+// success.
+func failed(resp *http.Response, body []byte) error {
+	return notFound(problem.DecodeBody(resp, body), ext.NotificationNotFoundError)
+}
+
+// List returns the user's inbox, newest first. While notification-system
+// is down the inbox reads as empty.
+func (c *Notification) List(ctx context.Context, params notification.ListNotificationsParams, token string) ([]notification.NotificationResponse, error) {
+	action := func() ([]notification.NotificationResponse, error) {
+		resp, err := c.API.ListNotificationsWithResponse(ctx, &params, authorize(token))
+		if err != nil {
+			return nil, unavailable(err)
+		}
+		if resp.JSON200 == nil {
+			return nil, failed(resp.HTTPResponse, resp.Body)
+		}
+		return *resp.JSON200, nil
+	}
+
+	fallback := func() []notification.NotificationResponse {
+		return []notification.NotificationResponse{}
+	}
+
+	return circuit.WithCircuitBreaker(c.GetBreaker, action, fallback, c.isHealthy)
+}
+
+func (c *Notification) MarkRead(ctx context.Context, uid uuid.UUID, username string, token string) error {
+	resp, err := c.API.MarkNotificationReadWithResponse(ctx, uid, &notification.MarkNotificationReadParams{XUserName: username}, authorize(token))
+	if err != nil {
+		return unavailable(err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return failed(resp.HTTPResponse, resp.Body)
+	}
+	return nil
+}
+
+func (c *Notification) GetPreferences(ctx context.Context, username string, token string) (*notification.Preferences, error) {
+	resp, err := c.API.GetPreferencesWithResponse(ctx, &notification.GetPreferencesParams{XUserName: username}, authorize(token))
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	if resp.JSON200 == nil {
+		return nil, failed(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+func (c *Notification) UpdatePreferences(ctx context.Context, username string, token string, prefs notification.Preferences) (*notification.Preferences, error) {
+	resp, err := c.API.SetPreferencesWithResponse(ctx, &notification.SetPreferencesParams{XUserName: username}, prefs, authorize(token))
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	if resp.JSON200 == nil {
+		return nil, failed(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
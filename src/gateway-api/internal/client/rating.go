@@ -1,12 +1,13 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
+	"contract/rating"
 	"fmt"
-	"gateway-api/internal/dto"
 	"gateway-api/pkg/circuit"
 	"gateway-api/pkg/ext"
 	"net/http"
+	"platform/problem"
 	"time"
 )
 
@@ -14,13 +15,17 @@ type Rating struct {
 	BaseURL    string `envconfig:"BASE_URL"`
 	HTTPClient *http.Client
 	GetBreaker *circuit.Breaker
+	API        *rating.ClientWithResponses
 }
 
 func NewRating(baseURL string) *Rating {
+	// only the options can fail, and these don't
+	api, _ := rating.NewClientWithResponses(baseURL, rating.WithHTTPClient(tracedClient))
 	return &Rating{
 		BaseURL:    baseURL,
-		HTTPClient: http.DefaultClient,
+		HTTPClient: tracedClient,
 		GetBreaker: circuit.NewBreaker(3, 5*time.Second, 60*time.Second, 3),
+		API:        api,
 	}
 }
 
@@ -33,34 +38,20 @@ func (c *Rating) isHealthy() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (c *Rating) Get(username string, token string) (*dto.UserRatingResponse, error) {
-	action := func() (*dto.UserRatingResponse, error) {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/rating", c.BaseURL), nil)
+func (c *Rating) Get(ctx context.Context, username string, token string) (*rating.RatingResponse, error) {
+	action := func() (*rating.RatingResponse, error) {
+		resp, err := c.API.GetRatingWithResponse(ctx, &rating.GetRatingParams{XUserName: username}, authorize(token))
 		if err != nil {
 			return nil, err
 		}
-		req.Header.Set("X-User-Name", username)
-		req.Header.Set("Authorization", token)
-
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-		}
-
-		var result dto.UserRatingResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
+		if resp.JSON200 == nil {
+			return nil, notFound(problem.DecodeBody(resp.HTTPResponse, resp.Body), ext.RatingNotFoundError)
 		}
-		return &result, nil
+		return resp.JSON200, nil
 	}
 
-	fallback := func() *dto.UserRatingResponse {
-		return &dto.UserRatingResponse{
+	fallback := func() *rating.RatingResponse {
+		return &rating.RatingResponse{
 			Stars: 0,
 		}
 	}
@@ -68,27 +59,17 @@ func (c *Rating) Get(username string, token string) (*dto.UserRatingResponse, er
 	return circuit.WithCircuitBreaker(c.GetBreaker, action, fallback, c.isHealthy)
 }
 
-func (c *Rating) Update(username string, stars int, token string) error {
+func (c *Rating) Update(ctx context.Context, username string, stars int, token string) error {
 
 	if !c.isHealthy() {
 		return ext.ServiceUnavailableError
 	}
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v1/rating/stars/%d/", c.BaseURL, stars), nil)
+	resp, err := c.API.ChangeRatingWithResponse(ctx, stars, &rating.ChangeRatingParams{XUserName: username}, authorize(token))
 	if err != nil {
 		return err
 	}
-
-	req.Header.Set("X-User-Name", username)
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	if resp.StatusCode() != http.StatusOK {
+		return problem.DecodeBody(resp.HTTPResponse, resp.Body)
 	}
 	return nil
 }
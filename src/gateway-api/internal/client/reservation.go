@@ -1,26 +1,33 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"contract/reservation"
 	"fmt"
-	"gateway-api/internal/dto"
 	"gateway-api/pkg/circuit"
+	"gateway-api/pkg/ext"
 	"net/http"
+	"platform/problem"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Reservation struct {
 	BaseURL    string `envconfig:"BASE_URL"`
 	HTTPClient *http.Client
 	GetBreaker *circuit.Breaker
+	API        *reservation.ClientWithResponses
 }
 
 func NewReservation(baseURL string) *Reservation {
+	// only the options can fail, and these don't
+	api, _ := reservation.NewClientWithResponses(baseURL, reservation.WithHTTPClient(tracedClient))
 	return &Reservation{
 		BaseURL:    baseURL,
-		HTTPClient: http.DefaultClient,
+		HTTPClient: tracedClient,
 		GetBreaker: circuit.NewBreaker(3, 5*time.Second, 60*time.Second, 3),
+		API:        api,
 	}
 }
 
@@ -33,186 +40,93 @@ func (c *Reservation) isHealthy() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (c *Reservation) Get(username string, token string) ([]dto.ReservationResponse, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/reservation", c.BaseURL), nil)
+func (c *Reservation) Get(ctx context.Context, username string, token string) ([]reservation.ReservationResponse, error) {
+	resp, err := c.API.ListReservationsWithResponse(ctx, &reservation.ListReservationsParams{XUserName: username}, authorize(token))
 	if err != nil {
 		return nil, err
 	}
-
-	req.Header.Set("X-User-Name", username)
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+	if resp.JSON200 == nil {
+		return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-	fmt.Println(resp.Body)
-
-	var result []dto.ReservationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return *resp.JSON200, nil
 }
 
-func (c *Reservation) GetByUID(uid string, token string) (*dto.ReservationResponse, error) {
-	req, err := http.NewRequest("GET",
-		fmt.Sprintf("%s/api/v1/reservation/%s", c.BaseURL, uid), nil)
-	if err != nil {
-		return nil, err
+// statusError maps the problems reservation-system reports about a single
+// reservation to the errors the gateway passes on to the user.
+func statusError(resp *http.Response, body []byte) error {
+	p := problem.DecodeBody(resp, body)
+	switch p.Code {
+	case problem.NotFound:
+		return ext.ReservationNotFoundError
+	case problem.Forbidden:
+		return ext.ForbiddenError
+	default:
+		return p
 	}
+}
 
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
+func (c *Reservation) GetByUID(ctx context.Context, uid uuid.UUID, username string, token string) (*reservation.ReservationResponse, error) {
+	resp, err := c.API.GetReservationWithResponse(ctx, uid, &reservation.GetReservationParams{XUserName: username}, authorize(token))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
-	var result dto.ReservationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if resp.JSON200 == nil {
+		return nil, statusError(resp.HTTPResponse, resp.Body)
 	}
-
-	return &result, nil
+	return resp.JSON200, nil
 }
 
-func (c *Reservation) DeleteReservation(uid string, token string) error {
-	req, err := http.NewRequest("GET",
-		fmt.Sprintf("%s/api/v1/reservation/%s", c.BaseURL, uid), nil)
+func (c *Reservation) DeleteReservation(ctx context.Context, uid uuid.UUID, username string, token string) error {
+	resp, err := c.API.CancelReservationWithResponse(ctx, uid, &reservation.CancelReservationParams{XUserName: username}, authorize(token))
 	if err != nil {
 		return err
 	}
-
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
+	if resp.StatusCode() != http.StatusNoContent {
+		return statusError(resp.HTTPResponse, resp.Body)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
-func (c *Reservation) GetCurrentAmount(username string, token string) (int, error) {
-	req, err := http.NewRequest("GET",
-		fmt.Sprintf("%s/api/v1/reservation/amount", c.BaseURL), nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("X-User-Name", username)
-
-	resp, err := c.HTTPClient.Do(req)
+func (c *Reservation) GetCurrentAmount(ctx context.Context, username string, token string) (int, error) {
+	resp, err := c.API.CountReservationsWithResponse(ctx, &reservation.CountReservationsParams{XUserName: username}, authorize(token))
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
-	var payload struct {
-		Amount int `json:"amount"`
+	if resp.JSON200 == nil {
+		return 0, problem.DecodeBody(resp.HTTPResponse, resp.Body)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return 0, err
-	}
-
-	return payload.Amount, nil
+	return resp.JSON200.Amount, nil
 }
 
-func (c *Reservation) Create(username string, token string, req dto.CreateReservationRequest) (*dto.ReservationResponse, error) {
-	action := func() (*dto.ReservationResponse, error) {
-		body, err := json.Marshal(&req)
-		if err != nil {
-			return nil, err
-		}
-
-		httpReq, err := http.NewRequest(
-			http.MethodPost,
-			fmt.Sprintf("%s/api/v1/reservation", c.BaseURL),
-			bytes.NewReader(body),
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		httpReq.Header.Set("X-User-Name", username)
-		httpReq.Header.Set("Authorization", token)
-		httpReq.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.HTTPClient.Do(httpReq)
+func (c *Reservation) Create(ctx context.Context, username string, token string, req reservation.CreateReservationRequest) (*reservation.ReservationResponse, error) {
+	action := func() (*reservation.ReservationResponse, error) {
+		resp, err := c.API.CreateReservationWithResponse(ctx, &reservation.CreateReservationParams{XUserName: username}, req, authorize(token))
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusCreated {
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		if resp.JSON201 == nil {
+			return nil, problem.DecodeBody(resp.HTTPResponse, resp.Body)
 		}
-
-		var result dto.ReservationResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
-		}
-
-		return &result, nil
+		return resp.JSON201, nil
 	}
 
-	fallback := func() *dto.ReservationResponse {
-		return &dto.ReservationResponse{}
+	fallback := func() *reservation.ReservationResponse {
+		return &reservation.ReservationResponse{}
 	}
 
 	return circuit.WithCircuitBreaker(c.GetBreaker, action, fallback, c.isHealthy)
 
 }
 
-func (c *Reservation) UpdateStatus(uid string, date string, token string) error {
-	body, err := json.Marshal(map[string]string{"date": date})
+// UpdateStatus closes a reservation. The condition the copy came back in is
+// carried on the event reservation-system publishes for the return.
+func (c *Reservation) UpdateStatus(ctx context.Context, uid uuid.UUID, username string, req reservation.UpdateStatusRequest, token string) error {
+	resp, err := c.API.CloseReservationWithResponse(ctx, uid, &reservation.CloseReservationParams{XUserName: username}, req, authorize(token))
 	if err != nil {
 		return err
 	}
-
-	req, err := http.NewRequest(
-		http.MethodPut,
-		fmt.Sprintf("%s/api/v1/reservation/%s", c.BaseURL, uid),
-		bytes.NewReader(body),
-	)
-	if err != nil {
-		return err
+	if resp.StatusCode() != http.StatusNoContent {
+		return statusError(resp.HTTPResponse, resp.Body)
 	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
-
 	return nil
 }
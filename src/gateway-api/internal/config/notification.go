@@ -0,0 +1,5 @@
+package config
+
+type Notification struct {
+	BaseURL string `envconfig:"BASE_URL"`
+}
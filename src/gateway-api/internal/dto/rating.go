@@ -1,5 +0,0 @@
-package dto
-
-type UserRatingResponse struct {
-	Stars int `json:"stars"`
-}
@@ -1,57 +1,42 @@
 package dto
 
-type CreateReservationRequest struct {
-	BookUID    string `json:"bookUid" binding:"required"`
-	LibraryUID string `json:"libraryUid" binding:"required"`
-	TillDate   string `json:"tillDate" binding:"required,datetime=2006-01-02"`
-}
+import (
+	"contract"
 
-type ReturnReservationRequest struct {
-	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
-	Condition string `json:"condition" binding:"required"`
-}
-type ReservationResponse struct {
-	ReservationUID string `json:"reservationUid"`
-	Username       string `json:"username"`
-	BookUID        string `json:"bookUid"`
-	LibraryUID     string `json:"libraryUid"`
-	Status         string `json:"status"`
-	StartDate      string `json:"startDate"`
-	TillDate       string `json:"tillDate"`
+	"github.com/google/uuid"
+)
+
+// CreateReservationRequest is the contract's request to take a book with
+// what the gateway accepts on top of it.
+type CreateReservationRequest struct {
+	contract.TakeBookRequest
+	// PickupLibraryUid asks for the copy to be moved to another library
+	// and held there for the patron.
+	PickupLibraryUid uuid.UUID `json:"pickupLibraryUid,omitzero"`
 }
 
+// ReservationFullResponse is a reservation of the contract with the patron
+// it belongs to.
 type ReservationFullResponse struct {
-	ReservationUID string          `json:"reservationUid"`
-	Username       string          `json:"username"`
-	Book           BookResponseRaw `json:"book"`
-	Library        LibraryResponse `json:"library"`
-	Status         string          `json:"status"`
-	StartDate      string          `json:"startDate"`
-	TillDate       string          `json:"tillDate"`
+	contract.BookReservationResponse
+	Username string `json:"username"`
 }
 
-func ReservationToFull(
-	r ReservationResponse,
-	book BookResponseRaw,
-	library LibraryResponse,
-) ReservationFullResponse {
-	return ReservationFullResponse{
-		ReservationUID: r.ReservationUID,
-		Username:       r.Username,
-		Status:         r.Status,
-		StartDate:      r.StartDate,
-		TillDate:       r.TillDate,
-		Book:           book,
-		Library:        library,
-	}
+// ReservationDetailsResponse is a single reservation with what returning it
+// today would cost.
+type ReservationDetailsResponse struct {
+	ReservationFullResponse
+	OverdueDays      int `json:"overdueDays"`
+	ProjectedPenalty int `json:"projectedPenalty"`
 }
 
 type ReturnRetryEvent struct {
-	Username       string `json:"username,omitzero"`
-	ReservationUID string `json:"reservation_uid,omitzero"`
-	BookUID        string `json:"book_uid,omitzero"`
-	LibraryUID     string `json:"library_uid,omitzero"`
-	RateDelta      int    `json:"rate_delta,omitzero"`
-	Condition      string `json:"condition,omitzero"`
-	Date           string `json:"date,omitzero"`
+	Username       string    `json:"username,omitzero"`
+	ReservationUID uuid.UUID `json:"reservation_uid,omitzero"`
+	BookUID        uuid.UUID `json:"book_uid,omitzero"`
+	LibraryUID     uuid.UUID `json:"library_uid,omitzero"`
+	CopyUID        uuid.UUID `json:"copy_uid,omitzero"`
+	RateDelta      int       `json:"rate_delta,omitzero"`
+	Condition      string    `json:"condition,omitzero"`
+	Date           string    `json:"date,omitzero"`
 }
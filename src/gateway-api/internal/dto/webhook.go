@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,startswith=https"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,required"`
+}
+
+type WebhookResponse struct {
+	SubscriptionUID string    `json:"subscriptionUid"`
+	Owner           string    `json:"owner"`
+	URL             string    `json:"url"`
+	EventTypes      []string  `json:"eventTypes"`
+	CreatedAt       time.Time `json:"createdAt"`
+	// Secret signs the deliveries. It is only returned on creation.
+	Secret string `json:"secret,omitempty"`
+}
+
+type ListDeliveriesRequest struct {
+	Status          string `form:"status" binding:"omitempty,oneof=PENDING RETRYING DELIVERED FAILED"`
+	SubscriptionUID string `form:"subscriptionUid" binding:"omitempty,uuid"`
+	Limit           uint64 `form:"limit,default=50" binding:"max=500"`
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryUID     string    `json:"deliveryUid"`
+	SubscriptionUID string    `json:"subscriptionUid"`
+	EventID         string    `json:"eventId"`
+	EventType       string    `json:"eventType"`
+	Status          string    `json:"status"`
+	Attempts        int       `json:"attempts"`
+	ResponseStatus  *int      `json:"responseStatus,omitempty"`
+	LastError       *string   `json:"lastError,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"contract"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// API serves the operations of the public contract with the handlers of
+// each resource. Its routes come from the spec, so an operation the spec
+// documents can't go missing from the gateway, and its parameters are
+// checked against the spec before a handler runs.
+type API struct {
+	Library     *LibraryHandler
+	Rating      *RatingHandler
+	Reservation *ReservationHandler
+}
+
+var _ contract.ServerInterface = (*API)(nil)
+
+// Register adds the operations of the contract to rg, which must be at the
+// root: the paths of the spec are absolute.
+func (a *API) Register(rg gin.IRouter) {
+	contract.RegisterHandlersWithOptions(rg, a, contract.GinServerOptions{
+		ErrorHandler: func(c *gin.Context, err error, status int) {
+			problem.Write(c, status, problem.CodeFor(status), err.Error())
+		},
+	})
+}
+
+func (a *API) GetApiV1Libraries(c *gin.Context, params contract.GetApiV1LibrariesParams) {
+	a.Library.GetLibraries(c, params)
+}
+
+func (a *API) GetApiV1LibrariesLibraryUidBooks(c *gin.Context, libraryUid openapi_types.UUID, params contract.GetApiV1LibrariesLibraryUidBooksParams) {
+	a.Library.GetLibraryBooks(c, libraryUid, params)
+}
+
+func (a *API) GetApiV1Rating(c *gin.Context) {
+	a.Rating.GetRating(c)
+}
+
+func (a *API) GetApiV1Reservations(c *gin.Context) {
+	a.Reservation.GetReservations(c)
+}
+
+func (a *API) PostApiV1Reservations(c *gin.Context) {
+	a.Reservation.CreateReservation(c)
+}
+
+func (a *API) PostApiV1ReservationsReservationUidReturn(c *gin.Context, reservationUid openapi_types.UUID) {
+	a.Reservation.ReturnBook(c, reservationUid)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// knownErrors gives the status and code of the errors the gateway raises
+// itself. The specific unavailability errors come before the generic one.
+var knownErrors = []struct {
+	err    error
+	status int
+	code   problem.Code
+}{
+	{ext.LibraryServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+	{ext.RatingServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+	{ext.ReservationServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+	{ext.NotificationServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+	{ext.ServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+	{ext.BookNotAvailableError, http.StatusConflict, problem.BookNotAvailable},
+	{ext.ReservationLimitError, http.StatusConflict, problem.ReservationLimitReached},
+	{ext.ReservationNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.BookNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.LibraryNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.RatingNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.NotificationNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.WebhookNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.WebhookDeliveryNotFoundError, http.StatusNotFound, problem.NotFound},
+	{ext.ForbiddenError, http.StatusForbidden, problem.Forbidden},
+	{ext.ReservationClosedError, http.StatusConflict, problem.ReservationClosed},
+}
+
+// writeError answers with the problem matching err. Problems reported by
+// backends are passed on as they are.
+func writeError(c *gin.Context, err error) {
+	for _, known := range knownErrors {
+		if errors.Is(err, known.err) {
+			problem.Write(c, known.status, known.code, known.err.Error())
+			return
+		}
+	}
+
+	var p *problem.Problem
+	if errors.As(err, &p) {
+		problem.Write(c, p.Status, p.Code, p.Detail)
+		return
+	}
+
+	problem.WriteInternal(c, err)
+}
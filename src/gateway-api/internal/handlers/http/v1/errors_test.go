@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"net/http/httptest"
+	"platform/problem"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   problem.Code
+	}{
+		{"known", ext.ReservationNotFoundError, http.StatusNotFound, problem.NotFound},
+		{"wrapped", fmt.Errorf("get reservation: %w", ext.ReservationClosedError), http.StatusConflict, problem.ReservationClosed},
+		{"specific unavailability", ext.RatingServiceUnavailableError, http.StatusServiceUnavailable, problem.ServiceUnavailable},
+		{"backend problem", problem.New(http.StatusConflict, problem.Conflict, "copy is not in the expected status"),
+			http.StatusConflict, problem.Conflict},
+		{"wrapped backend problem", fmt.Errorf("reserve copy: %w", problem.New(http.StatusConflict, problem.BookNotAvailable, "no copies")),
+			http.StatusConflict, problem.BookNotAvailable},
+		{"unknown", errors.New("connection reset"), http.StatusInternalServerError, problem.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/reservations", nil)
+
+			writeError(c, tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			assert.Equal(t, tt.code, p.Code)
+			assert.Equal(t, tt.status, p.Status)
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"contract"
 	"gateway-api/internal/service"
 	"net/http"
-	"strconv"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// defaultPageSize is the page size when the request names none.
+const defaultPageSize = 10
+
 type LibraryHandler struct {
 	Service *service.LibraryService
 }
@@ -16,60 +21,52 @@ func NewLibraryHandler(s *service.LibraryService) *LibraryHandler {
 	return &LibraryHandler{Service: s}
 }
 
-func (h *LibraryHandler) RegisterRoutes(rg *gin.RouterGroup) {
-	routes := rg.Group("/libraries")
-	routes.GET("/", h.GetLibraries)
-	routes.GET("/:uid/books", h.GetLibraryBooks)
-
-}
-
-func (h *LibraryHandler) GetLibraries(c *gin.Context) {
-	city := c.Query("city")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "0"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+func (h *LibraryHandler) GetLibraries(c *gin.Context, params contract.GetApiV1LibrariesParams) {
+	if params.Size == 0 {
+		params.Size = defaultPageSize
+	}
 
 	token, exists := c.Get("token")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	tokenStr, ok := token.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	res, err := h.Service.GetLibraries(city, page, size, tokenStr)
+	res, err := h.Service.GetLibraries(c.Request.Context(), params, tokenStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, res)
 }
 
-func (h *LibraryHandler) GetLibraryBooks(c *gin.Context) {
-	libraryUid := c.Param("uid")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "0"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
-	showAll := c.DefaultQuery("showAll", "false") == "true"
+func (h *LibraryHandler) GetLibraryBooks(c *gin.Context, libraryUid uuid.UUID, params contract.GetApiV1LibrariesLibraryUidBooksParams) {
+	if params.Size == 0 {
+		params.Size = defaultPageSize
+	}
 
 	token, exists := c.Get("token")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	tokenStr, ok := token.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	res, err := h.Service.GetLibraryBooks(libraryUid, page, size, showAll, tokenStr)
+	res, err := h.Service.GetLibraryBooks(c.Request.Context(), libraryUid, params, tokenStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
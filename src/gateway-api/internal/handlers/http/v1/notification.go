@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"contract/notification"
+	"gateway-api/internal/service"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	Service *service.NotificationService
+}
+
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Service: svc}
+}
+
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	routes := rg.Group("/notifications")
+	routes.GET("/", h.List)
+	routes.POST("/:uid/read", h.MarkRead)
+	routes.GET("/preferences", h.GetPreferences)
+	routes.PUT("/preferences", h.UpdatePreferences)
+}
+
+func (h *NotificationHandler) List(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	params := notification.ListNotificationsParams{
+		Unread:    c.Query("unread") == "true",
+		XUserName: username,
+	}
+	notifications, err := h.Service.List(c.Request.Context(), params, token)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	// a notification that can't be a UUID doesn't exist
+	uid, err := uuid.Parse(c.Param("uid"))
+	if err != nil {
+		writeError(c, ext.NotificationNotFoundError)
+		return
+	}
+
+	if err := h.Service.MarkRead(c.Request.Context(), uid, username, token); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.Service.GetPreferences(c.Request.Context(), username, token)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	var req notification.Preferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	prefs, err := h.Service.UpdatePreferences(c.Request.Context(), username, token, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
@@ -5,15 +5,12 @@ import (
 	"gateway-api/internal/service"
 	"gateway-api/pkg/ext"
 	"net/http"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var (
-	RatingServiceUnavailable = errors.New("Bonus Service unavailable")
-)
-
 type RatingHandler struct {
 	Service *service.RatingService
 }
@@ -22,11 +19,6 @@ func NewRatingHandler(svc *service.RatingService) *RatingHandler {
 	return &RatingHandler{Service: svc}
 }
 
-func (h *RatingHandler) RegisterRoutes(rg *gin.RouterGroup) {
-	routes := rg.Group("/rating")
-	routes.GET("/", h.GetRating)
-}
-
 func (h *RatingHandler) GetRating(c *gin.Context) {
 	//username := c.GetHeader("X-User-Name")
 	//if username == "" {
@@ -35,35 +27,34 @@ func (h *RatingHandler) GetRating(c *gin.Context) {
 	//}
 	claimsRaw, exists := c.Get("claims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	claims := claimsRaw.(jwt.MapClaims)
 	username, ok := claims["sub"].(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "sub claim missing"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
 		return
 	}
 	token, exists := c.Get("token")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	tokenStr, ok := token.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	rating, err := h.Service.GetRating(username, tokenStr)
+	rating, err := h.Service.GetRating(c.Request.Context(), username, tokenStr)
 	if err != nil {
 		if errors.Is(err, ext.ServiceUnavailableError) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"message": RatingServiceUnavailable.Error()})
-			return
+			err = ext.RatingServiceUnavailableError
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -1,14 +1,16 @@
 package handlers
 
 import (
-	"errors"
+	"contract"
 	"gateway-api/internal/dto"
 	"gateway-api/internal/service"
 	"gateway-api/pkg/ext"
 	"net/http"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 type ReservationHandler struct {
@@ -19,11 +21,24 @@ func NewReservationHandler(service *service.ReservationService) *ReservationHand
 	return &ReservationHandler{Service: service}
 }
 
+// RegisterRoutes adds the routes the contract doesn't cover; API serves
+// the others.
 func (h *ReservationHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	routes := rg.Group("/reservations")
-	routes.GET("/", h.GetReservations)
-	routes.POST("/", h.CreateReservation)
-	routes.POST("/:uid/return/", h.ReturnBook)
+	routes.GET("/:reservationUid", h.GetReservation)
+	routes.DELETE("/:reservationUid", h.CancelReservation)
+}
+
+// caller pulls the user and the raw Authorization header that
+// AuthMiddleware stored for the request.
+func caller(c *gin.Context) (username string, token string, ok bool) {
+	claims, _ := c.MustGet("claims").(jwt.MapClaims)
+	username, _ = claims["sub"].(string)
+	if username == "" {
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
+		return "", "", false
+	}
+	return username, c.GetString("token"), true
 }
 
 func (h *ReservationHandler) GetReservations(c *gin.Context) {
@@ -35,32 +50,32 @@ func (h *ReservationHandler) GetReservations(c *gin.Context) {
 
 	claimsRaw, exists := c.Get("claims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	claims := claimsRaw.(jwt.MapClaims)
 	username, ok := claims["sub"].(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "sub claim missing"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
 		return
 	}
 
 	token, exists := c.Get("token")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	tokenStr, ok := token.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	reservations, err := h.Service.Get(username, tokenStr)
+	reservations, err := h.Service.Get(c.Request.Context(), username, tokenStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -76,59 +91,44 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 
 	claimsRaw, exists := c.Get("claims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	claims := claimsRaw.(jwt.MapClaims)
 	username, ok := claims["sub"].(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "sub claim missing"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
 		return
 	}
 	var req dto.CreateReservationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
 	token, exists := c.Get("token")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	tokenStr, ok := token.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	reservation, err := h.Service.CreateReservation(username, tokenStr, req)
+	reservation, err := h.Service.CreateReservation(c.Request.Context(), username, tokenStr, req)
 	if err != nil {
-		if errors.Is(err, ext.LibraryServiceUnavailableError) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"message": ext.LibraryServiceUnavailableError.Error()})
-			return
-		}
-		if errors.Is(err, ext.RatingServiceUnavailableError) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"message": ext.RatingServiceUnavailableError.Error()})
-			return
-		}
-		if errors.Is(err, ext.ReservationServiceUnavailableError) {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"message": ext.ReservationServiceUnavailableError.Error()})
-			return
-		}
-		if errors.Is(err, ext.BookNotAvailableError) {
-			c.JSON(http.StatusBadRequest, gin.H{"message": ext.BookNotAvailableError.Error()})
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, reservation)
 }
 
-func (h *ReservationHandler) ReturnBook(c *gin.Context) {
+func (h *ReservationHandler) ReturnBook(c *gin.Context, reservationUID uuid.UUID) {
 	//username := c.GetHeader("X-User-Name")
 	//if username == "" {
 	//	c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header required"})
@@ -137,45 +137,86 @@ func (h *ReservationHandler) ReturnBook(c *gin.Context) {
 
 	claimsRaw, exists := c.Get("claims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
 		return
 	}
 
 	claims := claimsRaw.(jwt.MapClaims)
 	username, ok := claims["sub"].(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "sub claim missing"})
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
 		return
 	}
-	var req dto.ReturnReservationRequest
+	var req contract.ReturnBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
-	var reqURI struct {
-		ReservationUID string `uri:"uid" binding:"required"`
+
+	token, exists := c.Get("token")
+	if !exists {
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "no claims found")
+		return
 	}
 
-	if err := c.ShouldBindUri(&reqURI); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	tokenStr, ok := token.(string)
+	if !ok {
+		problem.Write(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token")
 		return
 	}
 
-	token, exists := c.Get("token")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
+	err := h.Service.ReturnBook(c.Request.Context(), username, tokenStr, req, reservationUID)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 
-	tokenStr, ok := token.(string)
+	c.Status(http.StatusNoContent)
+}
+
+// reservationUID is the reservation named in the path. One that can't be a
+// UUID doesn't exist.
+func reservationUID(c *gin.Context) (uuid.UUID, bool) {
+	uid, err := uuid.Parse(c.Param("reservationUid"))
+	if err != nil {
+		writeError(c, ext.ReservationNotFoundError)
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+	uid, ok := reservationUID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 		return
 	}
 
-	err := h.Service.ReturnBook(username, tokenStr, req, reqURI.ReservationUID)
+	res, err := h.Service.GetReservation(c.Request.Context(), username, token, uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ReservationHandler) CancelReservation(c *gin.Context) {
+	username, token, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	uid, ok := reservationUID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.CancelReservation(c.Request.Context(), username, token, uid); err != nil {
+		writeError(c, err)
 		return
 	}
 
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"gateway-api/internal/dto"
+	"gateway-api/internal/service"
+	"net/http"
+	"platform/auth"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type WebhookHandler struct {
+	Service *service.WebhookService
+}
+
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{Service: svc}
+}
+
+// RegisterRoutes lets integrators and admins manage subscriptions.
+// Deliveries are only for admins.
+func (h *WebhookHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	routes := rg.Group("/webhooks", auth.Require(auth.Admin, auth.Integrator))
+	routes.POST("/", h.Subscribe)
+	routes.GET("/", h.ListSubscriptions)
+	routes.DELETE("/:uid", h.Unsubscribe)
+
+	admin := rg.Group("/admin/webhooks", auth.Require(auth.Admin))
+	admin.GET("/deliveries", h.ListDeliveries)
+	admin.POST("/deliveries/:uid/replay", h.Replay)
+}
+
+func isAdmin(c *gin.Context) bool {
+	claims, _ := c.MustGet("claims").(jwt.MapClaims)
+	return auth.Roles(claims)[auth.Admin]
+}
+
+func (h *WebhookHandler) Subscribe(c *gin.Context) {
+	owner, _, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	sub, err := h.Service.Subscribe(c.Request.Context(), owner, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sub)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	owner, _, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	subs, err := h.Service.ListSubscriptions(c.Request.Context(), owner, isAdmin(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+func (h *WebhookHandler) Unsubscribe(c *gin.Context) {
+	owner, _, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Unsubscribe(c.Request.Context(), c.Param("uid"), owner, isAdmin(c)); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var req dto.ListDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	deliveries, err := h.Service.ListDeliveries(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	delivery, err := h.Service.Replay(c.Request.Context(), c.Param("uid"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,34 @@
+-- Endpoints of external integrators and the events each wants pushed.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id               SERIAL PRIMARY KEY,
+    subscription_uid UUID UNIQUE NOT NULL,
+    owner            VARCHAR(255) NOT NULL,
+    url              VARCHAR(2048) NOT NULL,
+    event_types      TEXT[] NOT NULL,
+    secret           VARCHAR(128) NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_event_types_idx ON webhook_subscriptions USING GIN (event_types);
+
+-- One row per event and subscription, updated on every attempt.
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               SERIAL PRIMARY KEY,
+    delivery_uid     UUID UNIQUE NOT NULL,
+    subscription_uid UUID NOT NULL REFERENCES webhook_subscriptions (subscription_uid) ON DELETE CASCADE,
+    event_id         UUID NOT NULL,
+    event_type       VARCHAR(80) NOT NULL,
+    payload          JSONB NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'RETRYING', 'DELIVERED', 'FAILED')),
+    attempts         INT NOT NULL DEFAULT 0,
+    response_status  INT,
+    last_error       TEXT,
+    created_at       TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (subscription_uid, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_idx ON webhook_deliveries (status, updated_at DESC);
@@ -0,0 +1,7 @@
+// Package migrations embeds the schema history of the webhooks database.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+package migrations_test
+
+import (
+	"gateway-api/internal/migrations"
+	"platform/migrate"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+}
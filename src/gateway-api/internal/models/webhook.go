@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryRetrying  = "RETRYING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryFailed    = "FAILED"
+)
+
+type Subscription struct {
+	ID              int64     `db:"id"`
+	SubscriptionUID uuid.UUID `db:"subscription_uid"`
+	// Owner is the token subject that registered the subscription.
+	Owner      string    `db:"owner"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	Secret     string    `db:"secret"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type Delivery struct {
+	ID              int64           `db:"id"`
+	DeliveryUID     uuid.UUID       `db:"delivery_uid"`
+	SubscriptionUID uuid.UUID       `db:"subscription_uid"`
+	EventID         uuid.UUID       `db:"event_id"`
+	EventType       string          `db:"event_type"`
+	Payload         json.RawMessage `db:"payload"`
+	Status          string          `db:"status"`
+	Attempts        int             `db:"attempts"`
+	ResponseStatus  *int            `db:"response_status"`
+	LastError       *string         `db:"last_error"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+}
+
+// DeliveryFilter narrows a delivery listing. Zero fields match everything.
+type DeliveryFilter struct {
+	Status          string
+	SubscriptionUID *uuid.UUID
+	Limit           uint64
+}
@@ -0,0 +1,336 @@
+// Package openapi puts together the spec the gateway serves on /docs: the
+// contract, the operations only the gateway has, and the specs the backends
+// publish themselves.
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"platform/logging"
+	"platform/problem"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpecPath is where every service publishes its spec.
+const SpecPath = "/docs/swagger.json"
+
+const fetchTimeout = 5 * time.Second
+
+// specTTL is how long a combined spec is served before the backend specs
+// are fetched again.
+const specTTL = time.Minute
+
+// maxSpecSize bounds what is read of the spec of a backend.
+const maxSpecSize = 4 << 20
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+type document = map[string]any
+
+// Combine joins specs, in JSON, into one. The first gives the info and the
+// servers; the others may only add operations and components.
+func Combine(specs ...[]byte) ([]byte, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no spec to combine")
+	}
+	dst, err := decode(specs[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs[1:] {
+		src, err := decode(spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := fold(dst, src, nil); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(dst)
+}
+
+// Backend is a service whose spec is folded into the one of the gateway.
+type Backend struct {
+	// Name tags the operations of the backend and prefixes its components,
+	// which would clash with those of the gateway otherwise.
+	Name    string
+	BaseURL string
+}
+
+// Aggregator serves the spec of the gateway with the specs of the backends
+// folded in. Backend operations keep their paths and are tagged with the
+// backend; where the gateway serves the same method and path, the operation
+// of the gateway is kept. The addresses of the backends are internal and
+// stay out of the spec.
+type Aggregator struct {
+	own      []byte
+	backends []Backend
+	client   *http.Client
+	now      func() time.Time
+
+	mu      sync.Mutex
+	spec    []byte
+	expires time.Time
+}
+
+func New(transport http.RoundTripper, own []byte, backends ...Backend) *Aggregator {
+	return &Aggregator{
+		own:      own,
+		backends: backends,
+		client:   &http.Client{Transport: transport, Timeout: fetchTimeout},
+		now:      time.Now,
+	}
+}
+
+// Spec is the combined spec in JSON. It is built again once it is older
+// than specTTL, so that it follows deployments of the backends without
+// every request for the docs reaching all of them.
+func (a *Aggregator) Spec(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.spec != nil && a.now().Before(a.expires) {
+		return a.spec, nil
+	}
+
+	// a client that goes away mustn't leave its backends out of the spec
+	// everybody is served next
+	doc, err := a.Document(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, err
+	}
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	a.spec, a.expires = spec, a.now().Add(specTTL)
+	return spec, nil
+}
+
+// Document fetches the backend specs and folds them into the spec of the
+// gateway. A backend that can't be reached only leaves a note on its tag;
+// why is logged, not published.
+func (a *Aggregator) Document(ctx context.Context) (map[string]any, error) {
+	doc, err := decode(a.own)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]document, len(a.backends))
+	errs := make([]error, len(a.backends))
+	var wg sync.WaitGroup
+	for i, b := range a.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			specs[i], errs[i] = a.fetch(ctx, b)
+		}()
+	}
+	wg.Wait()
+
+	for i, b := range a.backends {
+		if errs[i] == nil {
+			errs[i] = fold(doc, specs[i], &b)
+		}
+		if errs[i] != nil {
+			logging.FromContext(ctx).WithError(errs[i]).Warnf("failed to add the spec of %s to the docs", b.Name)
+			addTag(doc, b.Name, "Spec unavailable.")
+		}
+	}
+	return doc, nil
+}
+
+func (a *Aggregator) Handler(c *gin.Context) {
+	spec, err := a.Spec(c.Request.Context())
+	if err != nil {
+		problem.WriteInternal(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", spec)
+}
+
+func (a *Aggregator) fetch(ctx context.Context, b Backend) (document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(b.BaseURL, "/")+SpecPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %d", SpecPath, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSpecSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", SpecPath, maxSpecSize)
+	}
+	return decode(body)
+}
+
+func decode(spec []byte) (document, error) {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("decode spec: %w", err)
+	}
+	return doc, nil
+}
+
+// fold adds the operations and components of src to dst. Without a backend
+// the two specs must not overlap.
+func fold(dst, src document, b *Backend) error {
+	if b != nil {
+		prefix(src, b.Name+".")
+	}
+
+	dstComponents := child(dst, "components")
+	for kind, v := range object(src["components"]) {
+		dstKind := child(dstComponents, kind)
+		for name, c := range object(v) {
+			if _, ok := dstKind[name]; ok {
+				// the backends declare the same bearer scheme as the gateway
+				if kind == "securitySchemes" {
+					continue
+				}
+				return fmt.Errorf("components.%s.%s is declared twice", kind, name)
+			}
+			dstKind[name] = c
+		}
+	}
+
+	security, hasSecurity := src["security"]
+	dstPaths := child(dst, "paths")
+	for path, v := range object(src["paths"]) {
+		item := object(v)
+		params, _ := item["parameters"].([]any)
+		dstItem := child(dstPaths, path)
+
+		for method, op := range item {
+			if !methods[method] {
+				if _, ok := dstItem[method]; !ok && method != "parameters" {
+					dstItem[method] = op
+				}
+				continue
+			}
+			if _, ok := dstItem[method]; ok {
+				if b == nil {
+					return fmt.Errorf("%s %s is declared twice", strings.ToUpper(method), path)
+				}
+				continue
+			}
+
+			operation := object(op)
+			// path parameters and the default security of src don't carry
+			// over to dst on their own
+			if len(params) > 0 {
+				own, _ := operation["parameters"].([]any)
+				operation["parameters"] = append(append([]any{}, params...), own...)
+			}
+			if _, ok := operation["security"]; !ok && hasSecurity {
+				operation["security"] = security
+			}
+			if b != nil {
+				operation["tags"] = []any{b.Name}
+			}
+			dstItem[method] = operation
+		}
+	}
+
+	if b != nil {
+		title, _ := object(src["info"])["title"].(string)
+		addTag(dst, b.Name, fmt.Sprintf("%s, served by %s inside the deployment. Operations the gateway serves under the same path are left out.", title, b.Name))
+		return nil
+	}
+	for _, t := range list(src["tags"]) {
+		name, _ := object(t)["name"].(string)
+		if !hasTag(dst, name) {
+			dst["tags"] = append(list(dst["tags"]), t)
+		}
+	}
+	return nil
+}
+
+// prefix renames the components of doc and the references to them.
+func prefix(doc document, p string) {
+	components := object(doc["components"])
+	for kind, v := range components {
+		if kind == "securitySchemes" {
+			continue
+		}
+		renamed := make(map[string]any)
+		for name, c := range object(v) {
+			renamed[p+name] = c
+		}
+		components[kind] = renamed
+	}
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				if kind, name, ok := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/"); ok && kind != "securitySchemes" {
+					v["$ref"] = "#/components/" + kind + "/" + p + name
+				}
+			}
+			for _, e := range v {
+				walk(e)
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(doc)
+}
+
+func addTag(doc document, name, description string) {
+	if hasTag(doc, name) {
+		return
+	}
+	doc["tags"] = append(list(doc["tags"]), map[string]any{"name": name, "description": description})
+}
+
+func hasTag(doc document, name string) bool {
+	for _, t := range list(doc["tags"]) {
+		if object(t)["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns the object under key in doc, adding it when missing.
+func child(doc document, key string) document {
+	c, ok := doc[key].(map[string]any)
+	if !ok {
+		c = make(map[string]any)
+		doc[key] = c
+	}
+	return c
+}
+
+func object(v any) document {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func list(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gateway = `{
+	"openapi": "3.0.1",
+	"info": {"title": "Gateway", "version": "1.0"},
+	"paths": {
+		"/api/v1/libraries": {"get": {"responses": {"200": {"$ref": "#/components/responses/Libraries"}}}}
+	},
+	"components": {
+		"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}},
+		"responses": {"Libraries": {"description": "Libraries"}}
+	}
+}`
+
+const library = `{
+	"openapi": "3.0.1",
+	"info": {"title": "Library System", "version": "1.0"},
+	"security": [{"bearerAuth": []}],
+	"paths": {
+		"/api/v1/libraries": {"get": {"responses": {"200": {"$ref": "#/components/responses/Libraries"}}}},
+		"/api/v1/copies/{uid}": {
+			"parameters": [{"name": "uid", "in": "path", "required": true}],
+			"get": {"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Copy"}}}}}}
+		}
+	},
+	"components": {
+		"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}},
+		"responses": {"Libraries": {"description": "Libraries"}},
+		"schemas": {"Copy": {"type": "object"}}
+	}
+}`
+
+func decodeT(t *testing.T, spec string) document {
+	t.Helper()
+	doc, err := decode([]byte(spec))
+	require.NoError(t, err)
+	return doc
+}
+
+// at walks doc down the keys.
+func at(doc any, keys ...string) any {
+	for _, k := range keys {
+		doc = object(doc)[k]
+	}
+	return doc
+}
+
+func TestCombine(t *testing.T) {
+	extra := `{"paths": {"/api/v1/copies/{uid}": {"get": {"responses": {}}}}, "tags": [{"name": "Copies"}]}`
+	spec, err := Combine([]byte(gateway), []byte(extra))
+	require.NoError(t, err)
+
+	doc := decodeT(t, string(spec))
+	assert.Equal(t, "Gateway", at(doc, "info", "title"))
+	assert.NotNil(t, at(doc, "paths", "/api/v1/libraries", "get"))
+	assert.NotNil(t, at(doc, "paths", "/api/v1/copies/{uid}", "get"))
+	assert.True(t, hasTag(doc, "Copies"))
+}
+
+func TestCombine_Duplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+	}{
+		{"operation", `{"paths": {"/api/v1/libraries": {"get": {"responses": {}}}}}`},
+		{"component", `{"components": {"responses": {"Libraries": {"description": "Others"}}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Combine([]byte(gateway), []byte(tt.extra))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFold_Backend(t *testing.T) {
+	doc := decodeT(t, gateway)
+	b := &Backend{Name: "library-system", BaseURL: "http://library:8060"}
+	require.NoError(t, fold(doc, decodeT(t, library), b))
+
+	// the gateway keeps its own operation and components
+	get := at(doc, "paths", "/api/v1/libraries", "get")
+	assert.Nil(t, at(get, "servers"))
+	assert.Equal(t, "#/components/responses/Libraries", at(get, "responses", "200", "$ref"))
+	assert.NotNil(t, at(doc, "components", "responses", "Libraries"))
+
+	// the backend operation keeps its own components
+	op := at(doc, "paths", "/api/v1/copies/{uid}", "get")
+	assert.Equal(t, []any{"library-system"}, at(op, "tags"))
+	assert.Nil(t, at(op, "servers"))
+	assert.Len(t, list(at(op, "parameters")), 1)
+	assert.NotNil(t, at(op, "security"))
+	assert.Equal(t, "#/components/schemas/library-system.Copy",
+		at(op, "responses", "200", "content", "application/json", "schema", "$ref"))
+	assert.NotNil(t, at(doc, "components", "schemas", "library-system.Copy"))
+	assert.NotNil(t, at(doc, "components", "responses", "library-system.Libraries"))
+	assert.Len(t, object(at(doc, "components", "securitySchemes")), 1)
+	assert.True(t, hasTag(doc, "library-system"))
+
+	// the address of the backend is internal
+	spec, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(spec), b.BaseURL)
+}
+
+func TestAggregator_Spec(t *testing.T) {
+	var fetched atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SpecPath {
+			http.NotFound(w, r)
+			return
+		}
+		fetched.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(library))
+	}))
+	defer backend.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	huge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"openapi": "3.0.1", "x-padding": "` + strings.Repeat("x", maxSpecSize) + `"}`))
+	}))
+	defer huge.Close()
+
+	a := New(http.DefaultTransport, []byte(gateway),
+		Backend{Name: "library-system", BaseURL: backend.URL},
+		Backend{Name: "rating-system", BaseURL: down.URL},
+		Backend{Name: "notification-system", BaseURL: huge.URL},
+	)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	spec, err := a.Spec(context.Background())
+	require.NoError(t, err)
+	doc := decodeT(t, string(spec))
+	assert.NotNil(t, at(doc, "paths", "/api/v1/copies/{uid}", "get"))
+	for _, name := range []string{"rating-system", "notification-system"} {
+		assert.True(t, hasTag(doc, name))
+		for _, tag := range list(doc["tags"]) {
+			if at(tag, "name") == name {
+				assert.Equal(t, "Spec unavailable.", at(tag, "description"))
+			}
+		}
+	}
+	// neither the addresses of the backends nor why they failed get out
+	for _, url := range []string{backend.URL, down.URL, huge.URL} {
+		assert.NotContains(t, string(spec), url)
+	}
+	assert.NotContains(t, string(spec), "503")
+	assert.EqualValues(t, 1, fetched.Load())
+
+	// the docs are served from the cache until it expires
+	again, err := a.Spec(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, spec, again)
+	assert.EqualValues(t, 1, fetched.Load())
+
+	now = now.Add(specTTL)
+	_, err = a.Spec(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, fetched.Load())
+}
+
+func TestAggregator_Spec_Canceled(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(library))
+	}))
+	defer backend.Close()
+
+	a := New(http.DefaultTransport, []byte(gateway), Backend{Name: "library-system", BaseURL: backend.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// a client that went away doesn't leave the backend out of the cache
+	spec, err := a.Spec(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, at(decodeT(t, string(spec)), "paths", "/api/v1/copies/{uid}", "get"))
+}
@@ -0,0 +1,227 @@
+// Package proxy passes requests through to a backend untouched, for the
+// routes where the gateway has nothing to add to what the backend answers.
+//
+// Routes are declared in a table instead of written as handlers: each one
+// names the backend and the path it maps to there, and every route gets the
+// same treatment. The user's token goes along so the backend can enforce
+// its own rules, identity headers a client could forge are dropped, each
+// request runs under a timeout, and the backend's circuit breaker turns
+// failures into 503 once it opens.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateway-api/pkg/circuit"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"platform/auth"
+	"platform/logging"
+	"platform/problem"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultTimeout bounds a proxied request whose route sets no timeout.
+const DefaultTimeout = 10 * time.Second
+
+// dropped are the request headers a client must not pass on: backends take
+// X-User-Name for the acting user from trusted callers, and cookies are
+// never theirs.
+var dropped = []string{"X-User-Name", "Cookie"}
+
+// Backend is a service requests can be proxied to.
+type Backend struct {
+	BaseURL string
+	// Breaker is shared with the backend's client, so both see the
+	// backend's failures. No breaker is used when it is nil.
+	Breaker *circuit.Breaker
+}
+
+// Route maps a gateway route to a backend.
+type Route struct {
+	Method string
+	// Path is the gin pattern, relative to the group the table is
+	// registered on.
+	Path    string
+	Backend string
+	// Target is the path on the backend. Parameters of Path written the
+	// same way, such as :uid, are replaced with their values.
+	Target string
+	// Roles narrow down who may call the route, on top of the group's
+	// policy.
+	Roles   []auth.Role
+	Timeout time.Duration
+}
+
+type Table struct {
+	backends map[string]*httputil.ReverseProxy
+}
+
+// New builds the proxies to the backends, sending requests through
+// transport. Backends without a base URL are left out; only the routes
+// naming them fail to register.
+func New(transport http.RoundTripper, backends map[string]Backend) (*Table, error) {
+	t := &Table{backends: map[string]*httputil.ReverseProxy{}}
+	for name, b := range backends {
+		if b.BaseURL == "" {
+			continue
+		}
+		base, err := url.Parse(b.BaseURL)
+		if err != nil || base.Scheme == "" || base.Host == "" {
+			return nil, fmt.Errorf("backend %s: invalid base url %q", name, b.BaseURL)
+		}
+		rt := transport
+		if b.Breaker != nil {
+			rt = breakerTransport{next: transport, breaker: b.Breaker}
+		}
+		t.backends[name] = &httputil.ReverseProxy{
+			Rewrite:        rewrite(base),
+			Transport:      rt,
+			ModifyResponse: modifyResponse,
+			ErrorHandler:   errorHandler(name),
+		}
+	}
+	return t, nil
+}
+
+// Register adds the routes to rg. A route naming an unknown backend or a
+// parameter its path doesn't have is an error, so a broken table fails at
+// startup.
+func (t *Table) Register(rg *gin.RouterGroup, routes ...Route) error {
+	for _, r := range routes {
+		p, ok := t.backends[r.Backend]
+		if !ok {
+			return fmt.Errorf("route %s %s: backend %q is not configured", r.Method, r.Path, r.Backend)
+		}
+		for _, seg := range strings.Split(r.Target, "/") {
+			if strings.HasPrefix(seg, ":") && !hasParam(r.Path, seg) {
+				return fmt.Errorf("route %s %s: target parameter %s is not in the path", r.Method, r.Path, seg)
+			}
+		}
+
+		handlers := []gin.HandlerFunc{}
+		if len(r.Roles) > 0 {
+			handlers = append(handlers, auth.Require(r.Roles...))
+		}
+		handlers = append(handlers, handle(r, p))
+		rg.Handle(r.Method, r.Path, handlers...)
+	}
+	return nil
+}
+
+func hasParam(path, param string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if seg == param {
+			return true
+		}
+	}
+	return false
+}
+
+// targetKey holds the escaped backend path of a request for rewrite.
+type targetKey struct{}
+
+func handle(r Route, p *httputil.ReverseProxy) gin.HandlerFunc {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return func(c *gin.Context) {
+		segs := strings.Split(r.Target, "/")
+		for i, seg := range segs {
+			if strings.HasPrefix(seg, ":") {
+				segs[i] = url.PathEscape(c.Param(seg[1:]))
+			}
+		}
+		target := strings.Join(segs, "/")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, targetKey{}, target)
+		p.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	}
+}
+
+func rewrite(base *url.URL) func(*httputil.ProxyRequest) {
+	return func(pr *httputil.ProxyRequest) {
+		target, _ := pr.In.Context().Value(targetKey{}).(string)
+		pr.Out.URL.Scheme = base.Scheme
+		pr.Out.URL.Host = base.Host
+		pr.Out.URL.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + target
+		pr.Out.URL.Path, _ = url.PathUnescape(pr.Out.URL.RawPath)
+		pr.Out.Host = base.Host
+		pr.SetXForwarded()
+		for _, h := range dropped {
+			pr.Out.Header.Del(h)
+		}
+	}
+}
+
+// modifyResponse drops the backend's request id: the gateway has already
+// set the same one on the response.
+func modifyResponse(resp *http.Response) error {
+	resp.Header.Del(logging.RequestIDHeader)
+	return nil
+}
+
+func errorHandler(backend string) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		status := http.StatusBadGateway
+		switch {
+		case errors.Is(err, ext.ServiceUnavailableError):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, context.DeadlineExceeded):
+			status = http.StatusGatewayTimeout
+		case errors.Is(err, context.Canceled):
+			// The client went away; there is no one to answer.
+			return
+		}
+		logging.FromContext(r.Context()).WithError(err).WithField("backend", backend).
+			Warn("proxied request failed")
+
+		p := problem.New(status, problem.ServiceUnavailable, backend+" service unavailable")
+		p.Instance = r.URL.Path
+		w.Header().Set("Content-Type", problem.ContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(p)
+	}
+}
+
+// errServerError marks a 5xx answer as a failure for the breaker; the
+// answer itself still reaches the client.
+var errServerError = errors.New("backend answered with a server error")
+
+type breakerTransport struct {
+	next    http.RoundTripper
+	breaker *circuit.Breaker
+}
+
+func (t breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	called := false
+	_, err := t.breaker.Execute(func() (any, error) {
+		called = true
+		var err error
+		resp, err = t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+			return resp, errServerError
+		}
+		return resp, err
+	}, func() any { return nil })
+
+	switch {
+	case !called:
+		return nil, ext.ServiceUnavailableError
+	case errors.Is(err, errServerError):
+		return resp, nil
+	default:
+		return resp, err
+	}
+}
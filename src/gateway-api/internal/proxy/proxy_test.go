@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"encoding/json"
+	"gateway-api/pkg/circuit"
+	"net/http"
+	"net/http/httptest"
+	"platform/auth"
+	"platform/problem"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGateway(t *testing.T, backend Backend, routes ...Route) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	table, err := New(http.DefaultTransport, map[string]Backend{"library": backend})
+	require.NoError(t, err)
+
+	r := gin.New()
+	v1 := r.Group("/api/v1", func(c *gin.Context) {
+		c.Set("claims", jwt.MapClaims{"sub": "alice"})
+	})
+	require.NoError(t, table.Register(v1, routes...))
+	return r
+}
+
+func get(r http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestProxy_PassesRequestThrough(t *testing.T) {
+	var got *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer backend.Close()
+
+	r := newGateway(t, Backend{BaseURL: backend.URL + "/base/"},
+		Route{Method: http.MethodGet, Path: "/libraries/:uid/books/:bookUid/copies", Backend: "library",
+			Target: "/api/v1/library/:uid/books/:bookUid/copies"})
+
+	w := get(r, "/api/v1/libraries/lib%201/books/b1/copies?status=AVAILABLE", http.Header{
+		"Authorization": {"Bearer token"},
+		"X-User-Name":   {"mallory"},
+		"Cookie":        {"session=1"},
+	})
+
+	assert.Equal(t, http.StatusTeapot, w.Code, "the backend's answer is passed on as it is")
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	require.NotNil(t, got)
+	assert.Equal(t, "/base/api/v1/library/lib%201/books/b1/copies", got.URL.EscapedPath())
+	assert.Equal(t, "status=AVAILABLE", got.URL.RawQuery)
+	assert.Equal(t, "Bearer token", got.Header.Get("Authorization"))
+	assert.Empty(t, got.Header.Get("X-User-Name"))
+	assert.Empty(t, got.Header.Get("Cookie"))
+	assert.NotEmpty(t, got.Header.Get("X-Forwarded-For"))
+}
+
+func TestProxy_Roles(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer backend.Close()
+
+	r := newGateway(t, Backend{BaseURL: backend.URL},
+		Route{Method: http.MethodGet, Path: "/transfers", Backend: "library", Target: "/api/v1/transfers",
+			Roles: []auth.Role{auth.Librarian}})
+
+	w := get(r, "/api/v1/transfers", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProxy_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer backend.Close()
+	defer close(release)
+
+	r := newGateway(t, Backend{BaseURL: backend.URL},
+		Route{Method: http.MethodGet, Path: "/books/:uid", Backend: "library", Target: "/api/v1/books/:uid/",
+			Timeout: 50 * time.Millisecond})
+
+	w := get(r, "/api/v1/books/b1", nil)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, problem.ServiceUnavailable, p.Code)
+}
+
+func TestProxy_BreakerOpensOnServerErrors(t *testing.T) {
+	calls := 0
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer backend.Close()
+
+	r := newGateway(t, Backend{BaseURL: backend.URL, Breaker: circuit.NewBreaker(2, time.Minute, time.Minute, 1)},
+		Route{Method: http.MethodGet, Path: "/books/:uid", Backend: "library", Target: "/api/v1/books/:uid/"})
+
+	assert.Equal(t, http.StatusInternalServerError, get(r, "/api/v1/books/b1", nil).Code)
+	assert.Equal(t, http.StatusInternalServerError, get(r, "/api/v1/books/b1", nil).Code)
+
+	w := get(r, "/api/v1/books/b1", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, 2, calls, "an open breaker keeps requests off the backend")
+}
+
+func TestTable_RegisterRejectsBrokenRoutes(t *testing.T) {
+	table, err := New(http.DefaultTransport, map[string]Backend{"library": {BaseURL: "http://library"}, "rating": {}})
+	require.NoError(t, err)
+	rg := gin.New().Group("/api/v1")
+
+	assert.Error(t, table.Register(rg, Route{Method: http.MethodGet, Path: "/rating", Backend: "rating", Target: "/api/v1/rating"}))
+	assert.Error(t, table.Register(rg, Route{Method: http.MethodGet, Path: "/books/:uid", Backend: "library", Target: "/api/v1/books/:id"}))
+
+	_, err = New(http.DefaultTransport, map[string]Backend{"library": {BaseURL: "library:8050"}})
+	assert.Error(t, err)
+}
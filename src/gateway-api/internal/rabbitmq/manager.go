@@ -0,0 +1,236 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway-api/internal/dto"
+	"platform/health"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+type State int32
+
+const (
+	Disconnected State = iota
+	Connecting
+	Connected
+	Closed
+)
+
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Closed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+var ErrNotConnected = errors.New("not connected to RabbitMQ")
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Worker starts a consumer on ch, which it may also use to declare what it
+// consumes from. It returns a channel that is closed once the consumer has
+// stopped after ctx is cancelled and settled the delivery in hand.
+type Worker func(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error)
+
+type worker struct {
+	name  string
+	start Worker
+}
+
+// Manager keeps a RabbitMQ connection alive. Every time it connects it
+// declares the registered queues, opens the publishing channel and starts
+// the registered workers, each on a channel of its own; when the
+// connection drops it dials again with exponential backoff.
+type Manager struct {
+	url   string
+	state atomic.Int32
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	publisher *amqp.Channel
+	queues    []string
+	workers   []worker
+}
+
+func NewManager(url string) *Manager {
+	return &Manager{url: url}
+}
+
+// Declare registers durable queues to declare on every connection.
+func (m *Manager) Declare(queues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues = append(m.queues, queues...)
+}
+
+// Consume registers a retry worker for queue. Workers must be registered
+// before Run.
+func (m *Manager) Consume(queue string, process func(ctx context.Context, evt dto.ReturnRetryEvent) error) {
+	m.Attach(queue, func(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error) {
+		return RunRetryWorker(ctx, ch, queue, process)
+	})
+}
+
+// Attach registers a worker to start on every connection. Workers must be
+// registered before Run.
+func (m *Manager) Attach(name string, start Worker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers = append(m.workers, worker{name: name, start: start})
+}
+
+func (m *Manager) State() State {
+	return State(m.state.Load())
+}
+
+// Publish sends msg to queue over the current connection. It fails with
+// ErrNotConnected while the manager is reconnecting.
+func (m *Manager) Publish(queue string, msg amqp.Publishing) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.publisher == nil {
+		return ErrNotConnected
+	}
+	return m.publisher.Publish("", queue, false, false, msg)
+}
+
+// Check reports the manager as down while it has no connection.
+func (m *Manager) Check() health.Check {
+	return func(context.Context) error {
+		if state := m.State(); state != Connected {
+			return fmt.Errorf("RabbitMQ is %s", state)
+		}
+		return nil
+	}
+}
+
+// Run connects and keeps reconnecting until ctx is cancelled. It then stops
+// the workers, waits for the deliveries they hold and closes the connection.
+func (m *Manager) Run(ctx context.Context) {
+	backoff := minBackoff
+	for {
+		m.state.Store(int32(Connecting))
+		connCtx, stopWorkers := context.WithCancel(ctx)
+		lost, workers, err := m.connect(connCtx)
+		if err != nil {
+			// the workers started before the failure hold on until
+			// they are stopped
+			stopWorkers()
+			wait(workers)
+			m.state.Store(int32(Disconnected))
+			log.WithError(err).Warnf("failed to connect to RabbitMQ, retrying in %s", backoff)
+			select {
+			case <-ctx.Done():
+				m.state.Store(int32(Closed))
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+		m.state.Store(int32(Connected))
+		log.Info("connected to RabbitMQ")
+
+		select {
+		case err := <-lost:
+			log.WithError(err).Warn("RabbitMQ connection lost")
+			m.state.Store(int32(Disconnected))
+			stopWorkers()
+			wait(workers)
+			m.drop()
+		case <-ctx.Done():
+			stopWorkers()
+			wait(workers)
+			m.drop()
+			m.state.Store(int32(Closed))
+			log.Info("RabbitMQ connection closed")
+			return
+		}
+	}
+}
+
+// connect dials and starts the registered workers. On failure it returns
+// the workers it did start; they stop once ctx is cancelled. The lock is
+// only taken to read the registrations and to publish the connection, so a
+// worker may publish while the others are being started.
+func (m *Manager) connect(ctx context.Context) (<-chan *amqp.Error, []<-chan struct{}, error) {
+	m.mu.RLock()
+	queues, registered := m.queues, m.workers
+	m.mu.RUnlock()
+
+	conn, err := amqp.Dial(m.url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publisher, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	for _, q := range queues {
+		if _, err := publisher.QueueDeclare(q, true, false, false, false, nil); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("failed to declare queue %s: %w", q, err)
+		}
+	}
+
+	workers := make([]<-chan struct{}, 0, len(registered))
+	for _, w := range registered {
+		ch, err := conn.Channel()
+		if err == nil {
+			err = ch.Qos(1, 0, false)
+		}
+		var done <-chan struct{}
+		if err == nil {
+			done, err = w.start(ctx, ch)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, workers, fmt.Errorf("failed to start worker for %s: %w", w.name, err)
+		}
+		workers = append(workers, done)
+	}
+
+	m.mu.Lock()
+	m.conn, m.publisher = conn, publisher
+	m.mu.Unlock()
+	return conn.NotifyClose(make(chan *amqp.Error, 1)), workers, nil
+}
+
+// drop forgets the current connection, closing it if it is still open.
+func (m *Manager) drop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn != nil && !m.conn.IsClosed() {
+		if err := m.conn.Close(); err != nil {
+			log.WithError(err).Warn("failed to close RabbitMQ connection")
+		}
+	}
+	m.conn, m.publisher = nil, nil
+}
+
+func wait(workers []<-chan struct{}) {
+	for _, done := range workers {
+		<-done
+	}
+}
@@ -0,0 +1,132 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"platform/amqptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWorker counts how often it is started and stopped. It stops once its
+// context is cancelled, like RunRetryWorker.
+type fakeWorker struct {
+	started, stopped atomic.Int32
+}
+
+func (w *fakeWorker) start(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error) {
+	w.started.Add(1)
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		w.stopped.Add(1)
+		close(done)
+	}()
+	return done, nil
+}
+
+// run runs m until the test ends. The returned func stops it and waits for
+// Run to return.
+func run(t *testing.T, m *Manager) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	returned := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(returned)
+	}()
+	stop = func() {
+		cancel()
+		select {
+		case <-returned:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run did not return")
+		}
+	}
+	t.Cleanup(cancel)
+	return stop
+}
+
+func connected(m *Manager) func() bool {
+	return func() bool { return m.State() == Connected }
+}
+
+func TestManager_PublishWhileDisconnected(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	m := NewManager(b.URL())
+	assert.ErrorIs(t, m.Publish("returns", amqp.Publishing{}), ErrNotConnected)
+	assert.Error(t, m.Check()(context.Background()))
+
+	run(t, m)
+	require.Eventually(t, connected(m), 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, m.Check()(context.Background()))
+
+	// the broker goes away and doesn't come back
+	b.Close()
+	require.Eventually(t, func() bool { return m.State() != Connected }, 5*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, m.Publish("returns", amqp.Publishing{}), ErrNotConnected)
+	assert.Error(t, m.Check()(context.Background()))
+}
+
+func TestManager_Reconnects(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	m := NewManager(b.URL())
+	m.Declare("returns")
+	w := &fakeWorker{}
+	m.Attach("returns", w.start)
+
+	run(t, m)
+	require.Eventually(t, connected(m), 5*time.Second, 10*time.Millisecond)
+
+	b.Drop()
+	require.Eventually(t, func() bool {
+		return w.started.Load() == 2 && m.State() == Connected
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 1, w.stopped.Load(), "the worker of the lost connection is stopped")
+	assert.Equal(t, []string{"returns", "returns"}, b.Declared(), "queues are declared on every connection")
+
+	require.NoError(t, m.Publish("returns", amqp.Publishing{Body: []byte("{}")}))
+	assert.Eventually(t, func() bool {
+		return slices.ContainsFunc(b.Published(), func(msg amqptest.Message) bool {
+			return msg.RoutingKey == "returns"
+		})
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	m := NewManager(b.URL())
+	w := &fakeWorker{}
+	m.Attach("returns", w.start)
+
+	stop := run(t, m)
+	require.Eventually(t, connected(m), 5*time.Second, 10*time.Millisecond)
+
+	stop()
+	assert.Equal(t, Closed, m.State())
+	assert.EqualValues(t, 1, w.stopped.Load(), "Run waits for the workers")
+	assert.ErrorIs(t, m.Publish("returns", amqp.Publishing{}), ErrNotConnected)
+}
+
+func TestManager_WorkerFailsToStart(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	m := NewManager(b.URL())
+	first := &fakeWorker{}
+	m.Attach("first", first.start)
+	m.Attach("second", func(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error) {
+		// a worker may publish while it starts
+		_ = m.Publish("returns", amqp.Publishing{})
+		return nil, errors.New("no such queue")
+	})
+
+	stop := run(t, m)
+	require.Eventually(t, func() bool { return first.stopped.Load() >= 1 }, 5*time.Second, 10*time.Millisecond,
+		"the worker already started is stopped")
+	assert.NotEqual(t, Connected, m.State())
+	stop()
+}
@@ -0,0 +1,53 @@
+package rabbitmq
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	messagesDesc = prometheus.NewDesc("rabbitmq_queue_messages",
+		"Messages ready for delivery in the queue.", []string{"queue"}, nil)
+	consumersDesc = prometheus.NewDesc("rabbitmq_queue_consumers",
+		"Consumers attached to the queue.", []string{"queue"}, nil)
+)
+
+// depthCollector inspects queues at scrape time. Nothing is reported while
+// the manager is disconnected.
+type depthCollector struct {
+	m      *Manager
+	queues []string
+}
+
+// DepthCollector reports how many messages wait in each of queues.
+func (m *Manager) DepthCollector(queues ...string) prometheus.Collector {
+	return depthCollector{m: m, queues: queues}
+}
+
+func (d depthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- messagesDesc
+	ch <- consumersDesc
+}
+
+func (d depthCollector) Collect(ch chan<- prometheus.Metric) {
+	d.m.mu.RLock()
+	conn := d.m.conn
+	d.m.mu.RUnlock()
+	if conn == nil {
+		return
+	}
+
+	// a channel of its own: a failed inspection closes the channel it ran on
+	inspector, err := conn.Channel()
+	if err != nil {
+		return
+	}
+	defer inspector.Close()
+	for _, q := range d.queues {
+		state, err := inspector.QueueInspect(q)
+		if err != nil {
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(messagesDesc, prometheus.GaugeValue, float64(state.Messages), q)
+		ch <- prometheus.MustNewConstMetric(consumersDesc, prometheus.GaugeValue, float64(state.Consumers), q)
+	}
+}
@@ -1,52 +1,101 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"gateway-api/internal/dto"
 	"gateway-api/pkg/ext"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 )
 
-func RunRetryWorker(ch *amqp.Channel, queueName string, process func(evt dto.ReturnRetryEvent) error) error {
-	msgs, err := ch.Consume(queueName, "", false, false, false, false, nil)
+const retryDelay = 10 * time.Second
+
+// RunRetryWorker consumes queueName until ctx is cancelled. The returned
+// channel is closed once the consumer is cancelled and the delivery in hand
+// is settled, so the caller can wait on it before closing ch. A delivery
+// still waiting for its service when ctx ends is requeued. Each delivery is
+// processed in a span continuing the trace of the request that enqueued it.
+func RunRetryWorker(ctx context.Context, ch *amqp.Channel, queueName string, process func(ctx context.Context, evt dto.ReturnRetryEvent) error) (<-chan struct{}, error) {
+	consumer := "retry-" + queueName
+	msgs, err := ch.Consume(queueName, consumer, false, false, false, false, nil)
 	if err != nil {
-		return fmt.Errorf("failed to register consumer for %s: %w", queueName, err)
+		return nil, fmt.Errorf("failed to register consumer for %s: %w", queueName, err)
 	}
 
+	done := make(chan struct{})
 	go func() {
+		select {
+		case <-done:
+			// the channel went away with the connection
+		case <-ctx.Done():
+			if err := ch.Cancel(consumer, false); err != nil {
+				log.WithError(err).WithField("queue", queueName).Warn("failed to cancel consumer")
+			}
+		}
+	}()
+
+	go func() {
+		defer close(done)
 		for d := range msgs {
+			// a step in progress runs to completion: only the wait between
+			// attempts gives way to shutdown
+			msgCtx, span := tracing.StartConsume(context.WithoutCancel(ctx), queueName, d)
+			msgCtx = logging.WithRequestID(msgCtx, d.CorrelationId)
+			entry := logging.FromContext(msgCtx).WithField("queue", queueName)
+
 			var evt dto.ReturnRetryEvent
 			if err := json.Unmarshal(d.Body, &evt); err != nil {
-				log.Printf("[worker %s] bad payload, acking: %v", queueName, err)
+				entry.WithError(err).Error("bad payload, acking")
 				_ = d.Ack(false)
+				metrics.Consumed(queueName, metrics.Dropped)
+				tracing.End(span, err)
 				continue
 			}
 
+			entry = entry.WithFields(log.Fields{
+				"reservation": evt.ReservationUID,
+				"copy":        evt.CopyUID,
+			})
+		retry:
 			for {
-				err := process(evt)
+				err := process(msgCtx, evt)
 				if err == nil {
 					_ = d.Ack(false)
-					log.Printf("[worker %s] success: %+v", queueName, evt)
+					entry.Info("return resumed")
+					metrics.Consumed(queueName, metrics.Acked)
+					span.End()
 					break
 				}
 
 				if errors.Is(err, ext.ServiceUnavailableError) {
-					log.Printf("[worker %s] service unavailable, retrying in 10s: %v", queueName, err)
-					time.Sleep(10 * time.Second)
-					continue
+					entry.WithError(err).Warnf("service unavailable, retrying in %s", retryDelay)
+					select {
+					case <-time.After(retryDelay):
+						continue
+					case <-ctx.Done():
+						_ = d.Nack(false, true)
+						metrics.Consumed(queueName, metrics.Requeued)
+						tracing.End(span, err)
+						break retry
+					}
 				}
 
-				log.Printf("[worker %s] non-retryable, dropping: %v", queueName, err)
+				entry.WithError(err).Error("non-retryable, dropping")
 				_ = d.Ack(false)
+				metrics.Consumed(queueName, metrics.Dropped)
+				tracing.End(span, err)
 				break
 			}
 		}
 	}()
 
-	return nil
+	return done, nil
 }
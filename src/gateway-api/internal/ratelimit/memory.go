@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how often the memory store drops the buckets that have
+// filled up again and so hold nothing a fresh bucket wouldn't.
+const sweepEvery = time.Minute
+
+// Memory keeps the buckets in the gateway process. Replicas don't share
+// them, so each one lets a client through at the full rate.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	full time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*memoryBucket{}, now: time.Now}
+}
+
+func (m *Memory) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: float64(limit.Requests), at: now}}
+		m.buckets[key] = b
+	}
+	res := b.take(now, limit)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepEvery {
+		return
+	}
+	m.swept = now
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
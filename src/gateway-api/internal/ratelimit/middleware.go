@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"platform/logging"
+	"platform/problem"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The response headers of draft-ietf-httpapi-ratelimit-headers. Reset is in
+// seconds from now.
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+)
+
+type Config struct {
+	// Redis is the URL of a Redis-compatible server to keep the buckets in,
+	// such as redis://redis:6379/0. They are kept in memory when it is
+	// empty.
+	Redis string `envconfig:"REDIS"`
+	// Client bounds the requests from one IP, checked before the token is.
+	Client Limit `envconfig:"CLIENT" default:"300/1m"`
+	// User is the quota of a user on the routes no rule names.
+	User Limit `envconfig:"USER" default:"120/1m"`
+	// Routes give routes quotas of their own, counted apart from User.
+	Routes Routes `envconfig:"ROUTES" default:"GET /api/v1/libraries=60/1m;POST /api/v1/reservations=10/1m"`
+}
+
+// Rule is the quota of a user on one route.
+type Rule struct {
+	Method string
+	Route  string
+	Limit  Limit
+}
+
+func (r Rule) String() string {
+	return r.Method + " " + r.Route
+}
+
+// Routes are rules written as "METHOD /route=limit", separated by
+// semicolons. Routes are the gin patterns, such as
+// /api/v1/reservations/:uid/return, and trailing slashes don't matter.
+type Routes []Rule
+
+func (rs *Routes) Decode(value string) error {
+	var rules Routes
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("route limit %q: want METHOD /route=limit", entry)
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			return fmt.Errorf("route limit %q: want METHOD /route=limit", entry)
+		}
+		rule := Rule{Method: strings.ToUpper(method), Route: normalize(path)}
+		if err := rule.Limit.Decode(limit); err != nil {
+			return fmt.Errorf("route limit %q: %w", entry, err)
+		}
+		rules = append(rules, rule)
+	}
+	*rs = rules
+	return nil
+}
+
+func normalize(route string) string {
+	return strings.TrimSuffix(strings.TrimSpace(route), "/")
+}
+
+var rejected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limited_requests_total",
+	Help: "Requests turned down with 429, by the quota they ran out of.",
+}, []string{"scope"})
+
+type Limiter struct {
+	store Store
+	cfg   Config
+}
+
+func New(store Store, cfg Config) *Limiter {
+	return &Limiter{store: store, cfg: cfg}
+}
+
+// Clients limits the requests per client IP. It goes before
+// authentication, so that a flood of bad tokens is turned away cheaply.
+func (l *Limiter) Clients() gin.HandlerFunc {
+	return l.handler("client", func(c *gin.Context) (string, Limit) {
+		return "client:" + c.ClientIP(), l.cfg.Client
+	})
+}
+
+// Users limits the requests per token subject, with the quota of the
+// route's rule if it has one. It goes after authentication; requests
+// without claims are counted by client IP instead.
+func (l *Limiter) Users() gin.HandlerFunc {
+	return l.handler("user", func(c *gin.Context) (string, Limit) {
+		key := "user:ip:" + c.ClientIP()
+		if sub := subject(c); sub != "" {
+			key = "user:sub:" + sub
+		}
+		route := normalize(c.FullPath())
+		for _, rule := range l.cfg.Routes {
+			if rule.Method == c.Request.Method && rule.Route == route {
+				return key + ":" + rule.String(), rule.Limit
+			}
+		}
+		return key, l.cfg.User
+	})
+}
+
+func (l *Limiter) handler(scope string, pick func(c *gin.Context) (string, Limit)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, limit := pick(c)
+		if limit.Unlimited() {
+			c.Next()
+			return
+		}
+
+		res, err := l.store.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			// Losing the store shouldn't take the gateway down with it.
+			logging.FromContext(c).WithError(err).WithField("scope", scope).
+				Warn("rate limit store unavailable, letting the request through")
+			c.Next()
+			return
+		}
+
+		writeHeaders(c, res)
+		if !res.Allowed {
+			rejected.WithLabelValues(scope).Inc()
+			retry := seconds(res.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retry))
+			problem.Abort(c, http.StatusTooManyRequests, problem.TooManyRequests,
+				fmt.Sprintf("rate limit of %s exceeded, retry in %d s", limit, retry))
+			return
+		}
+		c.Next()
+	}
+}
+
+// writeHeaders reports the quota of res unless an earlier limiter already
+// reported a tighter one.
+func writeHeaders(c *gin.Context, res Result) {
+	if prev, err := strconv.Atoi(c.Writer.Header().Get(HeaderRemaining)); err == nil && prev < res.Remaining {
+		return
+	}
+	c.Header(HeaderLimit, strconv.Itoa(res.Limit))
+	c.Header(HeaderRemaining, strconv.Itoa(res.Remaining))
+	c.Header(HeaderReset, strconv.Itoa(seconds(res.Reset)))
+}
+
+// seconds rounds d up, so that a client waiting for it is never early.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func subject(c *gin.Context) string {
+	raw, _ := c.Get("claims")
+	claims, _ := raw.(jwt.MapClaims)
+	sub, _ := claims["sub"].(string)
+	return sub
+}
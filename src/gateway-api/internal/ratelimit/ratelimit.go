@@ -0,0 +1,109 @@
+// Package ratelimit throttles the clients of the gateway with token buckets.
+//
+// Every client IP has a bucket that bounds its requests before they are
+// authenticated, and every user has one per route rule, refilled at the
+// rate of the rule. A request that finds its bucket empty is answered with
+// 429 and never reaches a backend. The buckets live in a Store: in memory
+// for a single gateway, in Redis when replicas have to share the quotas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests requests per Per, in bursts of up to Requests. The
+// zero Limit allows everything.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Decode reads a limit written as "requests/period", such as "60/1m", or
+// "off" for no limit.
+func (l *Limit) Decode(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" {
+		*l = Limit{}
+		return nil
+	}
+	requests, per, ok := strings.Cut(value, "/")
+	if !ok {
+		return fmt.Errorf("limit %q: want requests/period", value)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("limit %q: requests must be a positive number", value)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("limit %q: period must be a positive duration", value)
+	}
+	*l = Limit{Requests: n, Per: d}
+	return nil
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Result is the state of a bucket after a request drew on it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until a denied request would be let through.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets.
+type Store interface {
+	// Take draws a token from the bucket under key, filling a missing
+	// bucket to the limit first.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is the token bucket arithmetic shared by the stores: the state
+// is the tokens left at a point in time, refilled lazily on the next take.
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+func (b *bucket) take(now time.Time, limit Limit) Result {
+	capacity := float64(limit.Requests)
+	interval := float64(limit.interval())
+	if elapsed := now.Sub(b.at); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/interval)
+	}
+	b.at = now
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) * interval))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration(math.Ceil((capacity - b.tokens) * interval))
+	return res
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimit_Decode(t *testing.T) {
+	var l Limit
+	require.NoError(t, l.Decode("10/1m"))
+	assert.Equal(t, Limit{Requests: 10, Per: time.Minute}, l)
+
+	require.NoError(t, l.Decode("off"))
+	assert.True(t, l.Unlimited())
+
+	for _, bad := range []string{"10", "0/1m", "x/1m", "10/soon", "10/-1s"} {
+		assert.Error(t, l.Decode(bad), bad)
+	}
+}
+
+func TestRoutes_Decode(t *testing.T) {
+	var rs Routes
+	require.NoError(t, rs.Decode("post /api/v1/reservations/=10/1m; GET /api/v1/reservations/:uid=5/1s"))
+	assert.Equal(t, Routes{
+		{Method: "POST", Route: "/api/v1/reservations", Limit: Limit{Requests: 10, Per: time.Minute}},
+		{Method: "GET", Route: "/api/v1/reservations/:uid", Limit: Limit{Requests: 5, Per: time.Second}},
+	}, rs)
+
+	assert.Error(t, rs.Decode("/api/v1/reservations=10/1m"))
+	assert.Error(t, rs.Decode("GET /api/v1/reservations"))
+}
+
+func TestMemory_Take(t *testing.T) {
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Per: 10 * time.Second}
+	ctx := context.Background()
+
+	res, _ := m.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, 5*time.Second, res.Reset)
+
+	res, _ = m.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, _ = m.Take(ctx, "k", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 5*time.Second, res.RetryAfter)
+
+	res, _ = m.Take(ctx, "other", limit)
+	assert.True(t, res.Allowed, "buckets are per key")
+
+	now = now.Add(5 * time.Second)
+	res, _ = m.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed, "a token is back after one interval")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemory_SweepsFullBuckets(t *testing.T) {
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.Take(ctx, "short", Limit{Requests: 1, Per: time.Second})
+	m.Take(ctx, "long", Limit{Requests: 1, Per: time.Hour})
+
+	now = now.Add(2 * sweepEvery)
+	m.Take(ctx, "new", Limit{Requests: 1, Per: time.Second})
+	assert.NotContains(t, m.buckets, "short")
+	assert.Contains(t, m.buckets, "long")
+}
+
+func newRouter(cfg Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	l := New(NewMemory(), cfg)
+	r := gin.New()
+	v1 := r.Group("/api/v1")
+	v1.Use(l.Clients(), func(c *gin.Context) {
+		if sub := c.GetHeader("X-Test-Sub"); sub != "" {
+			c.Set("claims", jwt.MapClaims{"sub": sub})
+		}
+	}, l.Users())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v1.GET("/libraries/", ok)
+	v1.POST("/reservations/", ok)
+	return r
+}
+
+func do(r http.Handler, method, path, sub, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if sub != "" {
+		req.Header.Set("X-Test-Sub", sub)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_RejectsExhaustedUser(t *testing.T) {
+	r := newRouter(Config{
+		Client: Limit{Requests: 100, Per: time.Minute},
+		User:   Limit{Requests: 5, Per: time.Minute},
+		Routes: Routes{{Method: "POST", Route: "/api/v1/reservations", Limit: Limit{Requests: 2, Per: time.Minute}}},
+	})
+
+	w := do(r, http.MethodPost, "/api/v1/reservations/", "alice", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(HeaderLimit))
+	assert.Equal(t, "1", w.Header().Get(HeaderRemaining))
+	assert.Equal(t, "30", w.Header().Get(HeaderReset))
+
+	do(r, http.MethodPost, "/api/v1/reservations/", "alice", "10.0.0.2")
+	w = do(r, http.MethodPost, "/api/v1/reservations/", "alice", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "the quota follows the user across IPs")
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"TOO_MANY_REQUESTS"`)
+
+	w = do(r, http.MethodGet, "/api/v1/libraries/", "alice", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code, "other routes draw on the default quota")
+	assert.Equal(t, "5", w.Header().Get(HeaderLimit))
+
+	w = do(r, http.MethodPost, "/api/v1/reservations/", "bob", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code, "users have buckets of their own")
+}
+
+func TestMiddleware_RejectsExhaustedClient(t *testing.T) {
+	r := newRouter(Config{
+		Client: Limit{Requests: 2, Per: time.Minute},
+		User:   Limit{Requests: 100, Per: time.Minute},
+	})
+
+	w := do(r, http.MethodGet, "/api/v1/libraries/", "alice", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(HeaderLimit), "the tighter quota is reported")
+
+	do(r, http.MethodGet, "/api/v1/libraries/", "bob", "10.0.0.1")
+	w = do(r, http.MethodGet, "/api/v1/libraries/", "carol", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = do(r, http.MethodGet, "/api/v1/libraries/", "carol", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMiddleware_Unlimited(t *testing.T) {
+	r := newRouter(Config{})
+	for range 10 {
+		w := do(r, http.MethodGet, "/api/v1/libraries/", "alice", "10.0.0.1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderLimit))
+	}
+}
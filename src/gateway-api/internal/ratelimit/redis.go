@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is the token bucket of bucket.take, run atomically in Redis.
+// Times are in milliseconds on the Redis clock, so the replicas agree on
+// them whatever their own clocks say. A bucket expires once it would be
+// full again.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+if now > at then
+  tokens = math.min(capacity, tokens + (now - at) / interval)
+end
+
+local allowed, retry = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * interval)
+end
+local reset = math.ceil((capacity - tokens) * interval)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', tostring(now))
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), reset, retry}
+`)
+
+// Redis keeps the buckets in a Redis-compatible server, so that all gateway
+// replicas draw on the same quotas.
+type Redis struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedis(client redis.Scripter) *Redis {
+	return &Redis{client: client, prefix: "ratelimit:"}
+}
+
+func (r *Redis) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	interval := float64(limit.interval()) / float64(time.Millisecond)
+	vals, err := takeScript.Run(ctx, r.client, []string{r.prefix + key}, limit.Requests, interval).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take a token: %w", err)
+	}
+	if len(vals) != 4 {
+		return Result{}, fmt.Errorf("failed to take a token: unexpected reply %v", vals)
+	}
+	return Result{
+		Allowed:    vals[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  int(vals[1]),
+		Reset:      time.Duration(vals[2]) * time.Millisecond,
+		RetryAfter: time.Duration(vals[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return NewRedis(client), mr
+}
+
+func TestRedis_Take(t *testing.T) {
+	r, mr := newRedis(t)
+	now := time.Now()
+	mr.SetTime(now)
+	limit := Limit{Requests: 2, Per: 10 * time.Second}
+	ctx := context.Background()
+
+	res, err := r.Take(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 5 * time.Second}, res)
+
+	res, _ = r.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 10*time.Second, res.Reset)
+
+	res, _ = r.Take(ctx, "k", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 5*time.Second, res.RetryAfter)
+
+	res, _ = r.Take(ctx, "other", limit)
+	assert.True(t, res.Allowed, "buckets are per key")
+
+	mr.SetTime(now.Add(5 * time.Second))
+	res, _ = r.Take(ctx, "k", limit)
+	assert.True(t, res.Allowed, "a token is back after one interval")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestRedis_BucketExpires(t *testing.T) {
+	r, mr := newRedis(t)
+	mr.SetTime(time.Now())
+
+	_, err := r.Take(context.Background(), "k", Limit{Requests: 1, Per: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "0", mr.HGet("ratelimit:k", "tokens"), "the bucket is kept under the prefix")
+	assert.Equal(t, 2*time.Second, mr.TTL("ratelimit:k"), "until it would be full again, and a second")
+
+	mr.FastForward(2 * time.Second)
+	assert.False(t, mr.Exists("ratelimit:k"))
+}
+
+func TestRedis_Unavailable(t *testing.T) {
+	r, mr := newRedis(t)
+	mr.Close()
+
+	_, err := r.Take(context.Background(), "k", Limit{Requests: 1, Per: time.Second})
+	assert.ErrorContains(t, err, "failed to take a token")
+}
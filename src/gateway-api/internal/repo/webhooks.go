@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway-api/internal/models"
+	"platform/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+const deliveryColumns = "id, delivery_uid, subscription_uid, event_id, event_type, payload, status, attempts, response_status, last_error, created_at, updated_at"
+
+type WebhookRepo interface {
+	CreateSubscription(ctx context.Context, s models.Subscription) (*models.Subscription, error)
+	// ListSubscriptions returns the subscriptions of owner, or all of them
+	// when owner is empty.
+	ListSubscriptions(ctx context.Context, owner string) ([]models.Subscription, error)
+	GetSubscription(ctx context.Context, uid uuid.UUID) (*models.Subscription, error)
+	DeleteSubscription(ctx context.Context, uid uuid.UUID) error
+
+	// CreateDeliveries queues an event for every subscription to its type
+	// and returns the deliveries not attempted yet, including those a
+	// previous copy of the event queued.
+	CreateDeliveries(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte) ([]models.Delivery, error)
+	GetDelivery(ctx context.Context, uid uuid.UUID) (*models.Delivery, error)
+	ListDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.Delivery, error)
+	// RecordAttempt stores the outcome of an attempt and counts it.
+	RecordAttempt(ctx context.Context, uid uuid.UUID, status string, responseStatus *int, attemptErr error) error
+	// ResetDelivery puts a delivery back to pending with no attempts made.
+	ResetDelivery(ctx context.Context, uid uuid.UUID) (*models.Delivery, error)
+}
+
+type webhookRepo struct {
+	conn postgres.Connection
+}
+
+var qb = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+func NewWebhookRepo(client postgres.Client) WebhookRepo {
+	return &webhookRepo{conn: client.Conn()}
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, s models.Subscription) (*models.Subscription, error) {
+	sql, args, err := qb.Insert("webhook_subscriptions").
+		Columns("subscription_uid", "owner", "url", "event_types", "secret").
+		Values(s.SubscriptionUID, s.Owner, s.URL, s.EventTypes, s.Secret).
+		Suffix("RETURNING *").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Subscription])
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context, owner string) ([]models.Subscription, error) {
+	query := qb.Select("*").From("webhook_subscriptions").OrderBy("id")
+	if owner != "" {
+		query = query.Where(squirrel.Eq{"owner": owner})
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Subscription])
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, uid uuid.UUID) (*models.Subscription, error) {
+	rows, err := r.conn.Query(ctx, `SELECT * FROM webhook_subscriptions WHERE subscription_uid = $1;`, uid)
+	if err != nil {
+		return nil, err
+	}
+	s, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Subscription])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, uid uuid.UUID) error {
+	tag, err := r.conn.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE subscription_uid = $1;`, uid)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepo) CreateDeliveries(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte) ([]models.Delivery, error) {
+	_, err := r.conn.Exec(ctx, `
+        INSERT INTO webhook_deliveries (delivery_uid, subscription_uid, event_id, event_type, payload)
+        SELECT gen_random_uuid(), subscription_uid, $1, $2, $3
+        FROM webhook_subscriptions
+        WHERE $2 = ANY(event_types)
+        ON CONFLICT (subscription_uid, event_id) DO NOTHING;
+    `, eventID, eventType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue deliveries: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, `
+        SELECT `+deliveryColumns+` FROM webhook_deliveries
+        WHERE event_id = $1 AND status = 'PENDING' AND attempts = 0
+        ORDER BY id;
+    `, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Delivery])
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, uid uuid.UUID) (*models.Delivery, error) {
+	rows, err := r.conn.Query(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE delivery_uid = $1;`, uid)
+	if err != nil {
+		return nil, err
+	}
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Delivery])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.Delivery, error) {
+	query := qb.Select(deliveryColumns).From("webhook_deliveries").OrderBy("updated_at DESC", "id DESC")
+	if filter.Status != "" {
+		query = query.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if filter.SubscriptionUID != nil {
+		query = query.Where(squirrel.Eq{"subscription_uid": *filter.SubscriptionUID})
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[models.Delivery])
+}
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, uid uuid.UUID, status string, responseStatus *int, attemptErr error) error {
+	var message *string
+	if attemptErr != nil {
+		s := attemptErr.Error()
+		message = &s
+	}
+	sql, args, err := qb.Update("webhook_deliveries").
+		Set("status", status).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("response_status", responseStatus).
+		Set("last_error", message).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"delivery_uid": uid}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := r.conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepo) ResetDelivery(ctx context.Context, uid uuid.UUID) (*models.Delivery, error) {
+	rows, err := r.conn.Query(ctx, `
+        UPDATE webhook_deliveries
+        SET status = 'PENDING', attempts = 0, response_status = NULL, last_error = NULL, updated_at = now()
+        WHERE delivery_uid = $1
+        RETURNING `+deliveryColumns+`;
+    `, uid)
+	if err != nil {
+		return nil, err
+	}
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Delivery])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"contract/contracttest"
+	"contract/library"
+	"contract/rating"
+	"contract/reservation"
+	"encoding/json"
+	"gateway-api/internal/client"
+	"gateway-api/internal/rabbitmq"
+	"gateway-api/internal/ratelimit"
+	"gateway-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"platform/auth"
+	"platform/auth/authtest"
+	"platform/lifecycle"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	libraryUID     = "83575e12-7ce0-48ee-9931-51919ff3c9ee"
+	bookUID        = "f7cdc58f-2caf-4b15-9727-f89dcc629b27"
+	copyUID        = "1c4d6a3e-5f0b-4f0e-9d7e-2b8a3f6c9e10"
+	reservationUID = "f464ca3a-fcf7-4e3f-86f0-76c7bba96f72"
+)
+
+// backends stands in for library-, rating- and reservation-system with the
+// answers the gateway needs to serve each operation of the contract.
+func backends() http.Handler {
+	date := func(s string) openapi_types.Date {
+		t, _ := time.Parse(time.DateOnly, s)
+		return openapi_types.Date{Time: t}
+	}
+	lib := library.LibraryResponse{LibraryUid: uuid.MustParse(libraryUID), Name: "Library", Address: "Street 1", City: "Moscow"}
+	book := library.BookResponse{BookUid: uuid.MustParse(bookUID), Name: "Book", Author: "Author", Genre: "Genre", Condition: library.EXCELLENT, AvailableCount: 1}
+	res := reservation.ReservationResponse{
+		ReservationUid: uuid.MustParse(reservationUID), Username: "alice", BookUid: book.BookUid, LibraryUid: lib.LibraryUid,
+		CopyUid: uuid.MustParse(copyUID), Status: reservation.RENTED, StartDate: date("2021-10-09"), TillDate: date("2021-10-11"),
+	}
+	returned := res
+	returned.Status = reservation.RETURNED
+
+	answer := func(status int, body any) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if body == nil {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(body)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /manage/health", answer(http.StatusOK, nil))
+	mux.Handle("GET /api/v1/libraries", answer(http.StatusOK, library.LibraryPaginationResponse{
+		Page: 1, PageSize: 1, TotalElements: 1, Items: []library.LibraryResponse{lib}}))
+	mux.Handle("GET /api/v1/libraries/{uid}/books/{$}", answer(http.StatusOK, library.BookPaginationResponse{
+		Page: 1, PageSize: 1, TotalElements: 1, Items: []library.BookResponse{book}}))
+	mux.Handle("GET /api/v1/libraries/{uid}/{$}", answer(http.StatusOK, lib))
+	mux.Handle("GET /api/v1/books/{uid}/{$}", answer(http.StatusOK, book))
+	mux.Handle("POST /api/v1/library/{lib}/books/{book}/copies/reserve", answer(http.StatusOK, library.CopyResponse{
+		CopyUid: res.CopyUid, BookUid: book.BookUid, LibraryUid: lib.LibraryUid, Condition: library.EXCELLENT, Status: library.CopyStatusRESERVED}))
+	mux.Handle("POST /api/v1/copies/{uid}/release", answer(http.StatusOK, nil))
+	mux.Handle("GET /api/v1/rating/{$}", answer(http.StatusOK, rating.RatingResponse{Stars: 75}))
+	mux.Handle("GET /api/v1/reservation/{$}", answer(http.StatusOK, []reservation.ReservationResponse{res}))
+	mux.Handle("GET /api/v1/reservation/amount", answer(http.StatusOK, reservation.AmountResponse{Amount: 0}))
+	mux.Handle("POST /api/v1/reservation/{$}", answer(http.StatusCreated, res))
+	mux.Handle("PUT /api/v1/reservation/{uid}", answer(http.StatusNoContent, nil))
+	mux.Handle("GET /api/v1/reservation/{uid}", answer(http.StatusOK, returned))
+	return mux
+}
+
+var (
+	minter = authtest.NewMinter()
+	// testServer is shared by the tests that only serve requests.
+	testServer *Server
+)
+
+func newTestServer(backendURL string, serviceAuth *auth.ServiceToken) (*Server, error) {
+	return newLimitedServer(backendURL, serviceAuth, nil, ratelimit.Config{})
+}
+
+func newLimitedServer(backendURL string, serviceAuth *auth.ServiceToken, trustedProxies []string, limits ratelimit.Config) (*Server, error) {
+	verifier, err := auth.NewVerifier(minter.Config())
+	if err != nil {
+		return nil, err
+	}
+	return New("", 0, lifecycle.Shutdown{}, trustedProxies,
+		client.NewLibrary(backendURL),
+		client.NewRating(backendURL),
+		client.NewReservation(backendURL),
+		client.NewNotification(backendURL),
+		&service.WebhookService{},
+		rabbitmq.NewManager("amqp://localhost"),
+		serviceAuth,
+		verifier,
+		ratelimit.New(ratelimit.NewMemory(), limits),
+	)
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	backend := httptest.NewServer(backends())
+	serviceAuth, tokens := minter.ServiceToken()
+
+	var err error
+	testServer, err = newTestServer(backend.URL, serviceAuth)
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	backend.Close()
+	tokens.Close()
+	os.Exit(code)
+}
+
+// Nothing a server sets up is global, so a process can hold more than one.
+func TestNew_Again(t *testing.T) {
+	srv, err := newTestServer("http://backend.test", testServer.ServiceAuth)
+	require.NoError(t, err)
+
+	// nor are the metrics served next to the API
+	w := httptest.NewRecorder()
+	srv.GinRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNew_TrustedProxies(t *testing.T) {
+	limits := ratelimit.Config{Client: ratelimit.Limit{Requests: 1, Per: time.Minute}}
+	srv, err := newLimitedServer("http://backend.test", testServer.ServiceAuth, []string{"10.0.0.9"}, limits)
+	require.NoError(t, err)
+	request := func(peer, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/rating", nil)
+		req.RemoteAddr = peer + ":1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		srv.GinRouter.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.1", "192.0.2.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1", "192.0.2.2"),
+		"a client can't pass for another one")
+
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.9", "192.0.2.3"))
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.9", "192.0.2.4"),
+		"the clients behind a trusted proxy have buckets of their own")
+}
+
+// Integrators hold no role but their own, so the webhook routes are kept
+// out of the patron and service gate of the rest of the API.
+func TestServer_WebhookRoles(t *testing.T) {
+	integrator := minter.Token(jwt.MapClaims{"sub": "portal@clients", "gty": "client-credentials", "permissions": []string{"integrator"}})
+	patron := minter.Token(jwt.MapClaims{"sub": "alice"})
+	request := func(token, method, path string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testServer.GinRouter.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// the body is refused by the handler, so the integrator got through
+	assert.Equal(t, http.StatusBadRequest, request(integrator, http.MethodPost, "/api/v1/webhooks/"))
+	assert.Equal(t, http.StatusForbidden, request(patron, http.MethodPost, "/api/v1/webhooks/"))
+
+	// the rest of the API is still not theirs
+	assert.Equal(t, http.StatusForbidden, request(integrator, http.MethodGet, "/api/v1/rating"))
+	assert.Equal(t, http.StatusForbidden, request(integrator, http.MethodGet, "/api/v1/admin/webhooks/deliveries"))
+}
+
+func TestServer_Contract(t *testing.T) {
+	token := minter.Token(jwt.MapClaims{"sub": "alice", "roles": []string{"patron"}})
+	request := func(method, path, body string) *http.Request {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", token)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"libraries", request(http.MethodGet, "/api/v1/libraries?city=Moscow&page=1&size=1", ""), http.StatusOK},
+		{"library books", request(http.MethodGet, "/api/v1/libraries/"+libraryUID+"/books?showAll=true", ""), http.StatusOK},
+		{"rating", request(http.MethodGet, "/api/v1/rating", ""), http.StatusOK},
+		{"reservations", request(http.MethodGet, "/api/v1/reservations", ""), http.StatusOK},
+		{"take book", request(http.MethodPost, "/api/v1/reservations",
+			`{"bookUid":"`+bookUID+`","libraryUid":"`+libraryUID+`","tillDate":"2021-10-11"}`), http.StatusOK},
+		{"return book", request(http.MethodPost, "/api/v1/reservations/"+reservationUID+"/return",
+			`{"condition":"EXCELLENT","date":"2021-10-11"}`), http.StatusNoContent},
+		{"city missing", request(http.MethodGet, "/api/v1/libraries", ""), http.StatusBadRequest},
+		{"reservation uid malformed", request(http.MethodPost, "/api/v1/reservations/42/return",
+			`{"condition":"EXCELLENT","date":"2021-10-11"}`), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := contracttest.Check(t, testServer.GinRouter, tt.req)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
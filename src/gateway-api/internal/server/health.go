@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"gateway-api/pkg/circuit"
+	"platform/health"
+	"platform/lifecycle"
+	"time"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// initHealthRoutes registers the probes. A downstream with an open breaker
+// only degrades the gateway: it still serves the other routes and the
+// fallbacks.
+func (s *Server) initHealthRoutes() {
+	s.health = health.New(healthCheckTimeout)
+	s.health.Critical("server", lifecycle.Serving(&s.ready))
+	s.health.Optional("library-system", breakerCheck(s.LibraryClient.GetBreaker))
+	s.health.Optional("rating-system", breakerCheck(s.RatingClient.GetBreaker))
+	s.health.Optional("reservation-system", breakerCheck(s.ReservationClient.GetBreaker))
+	s.health.Optional("notification-system", breakerCheck(s.NotifyClient.GetBreaker))
+
+	s.health.Register(s.GinRouter)
+}
+
+// Health lets the owner of other dependencies add their checks.
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
+func breakerCheck(b *circuit.Breaker) health.Check {
+	return func(context.Context) error {
+		if state := b.State(); state != circuit.Closed {
+			return fmt.Errorf("circuit breaker is %s", state)
+		}
+		return nil
+	}
+}
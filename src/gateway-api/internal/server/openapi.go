@@ -0,0 +1,47 @@
+package server
+
+import (
+	"contract"
+	_ "embed"
+	"encoding/json"
+	"gateway-api/internal/client"
+	"gateway-api/internal/openapi"
+	"platform/docs"
+)
+
+// openapiSpec documents what the gateway serves besides the contract.
+//
+//go:embed openapi.yml
+var openapiSpec []byte
+
+// ownSpec is the spec of the operations the gateway serves itself.
+func ownSpec() ([]byte, error) {
+	swagger, err := contract.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	base, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := docs.JSON(openapiSpec)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Combine(base, extra)
+}
+
+func (s *Server) initDocsRoutes() error {
+	own, err := ownSpec()
+	if err != nil {
+		return err
+	}
+	aggregator := openapi.New(client.Transport, own,
+		openapi.Backend{Name: "library-system", BaseURL: s.LibraryClient.BaseURL},
+		openapi.Backend{Name: "rating-system", BaseURL: s.RatingClient.BaseURL},
+		openapi.Backend{Name: "reservation-system", BaseURL: s.ReservationClient.BaseURL},
+		openapi.Backend{Name: "notification-system", BaseURL: s.NotifyClient.BaseURL},
+	)
+	docs.Register(s.GinRouter, aggregator.Handler)
+	return nil
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"platform/docs"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI_Valid(t *testing.T) {
+	spec, err := ownSpec()
+	require.NoError(t, err)
+
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	require.NoError(t, err)
+	assert.NoError(t, doc.Validate(context.Background()))
+}
+
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	spec, err := ownSpec()
+	require.NoError(t, err)
+
+	missing, err := docs.Undocumented(spec, testServer.GinRouter.Routes(), "/api/")
+	require.NoError(t, err)
+	assert.Empty(t, missing, "routes missing from the spec")
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"gateway-api/internal/client"
+	"gateway-api/internal/proxy"
+	"net/http"
+	"platform/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// passthrough are the reads the gateway hands to a backend as they are.
+// A read that needs nothing from the gateway but auth goes here rather
+// than into a handler of its own.
+var passthrough = []proxy.Route{
+	{Method: http.MethodGet, Path: "/libraries/:libraryUid", Backend: "library", Target: "/api/v1/libraries/:libraryUid/"},
+	{Method: http.MethodGet, Path: "/libraries/:libraryUid/books/:bookUid/copies", Backend: "library", Target: "/api/v1/library/:libraryUid/books/:bookUid/copies"},
+	{Method: http.MethodGet, Path: "/books/:uid", Backend: "library", Target: "/api/v1/books/:uid/"},
+	{Method: http.MethodGet, Path: "/copies/:uid", Backend: "library", Target: "/api/v1/copies/:uid"},
+	{Method: http.MethodGet, Path: "/transfers", Backend: "library", Target: "/api/v1/transfers", Roles: []auth.Role{auth.Librarian}},
+	{Method: http.MethodGet, Path: "/transfers/:uid", Backend: "library", Target: "/api/v1/transfers/:uid", Roles: []auth.Role{auth.Librarian}},
+}
+
+func (s *Server) initPassthroughRoutes(rg *gin.RouterGroup) error {
+	table, err := proxy.New(client.Transport, map[string]proxy.Backend{
+		"library":      {BaseURL: s.LibraryClient.BaseURL, Breaker: s.LibraryClient.GetBreaker},
+		"rating":       {BaseURL: s.RatingClient.BaseURL, Breaker: s.RatingClient.GetBreaker},
+		"reservation":  {BaseURL: s.ReservationClient.BaseURL, Breaker: s.ReservationClient.GetBreaker},
+		"notification": {BaseURL: s.NotifyClient.BaseURL, Breaker: s.NotifyClient.GetBreaker},
+	})
+	if err != nil {
+		return err
+	}
+	return table.Register(rg, passthrough...)
+}
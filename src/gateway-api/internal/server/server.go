@@ -1,52 +1,98 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"gateway-api/internal/auth"
 	"gateway-api/internal/client"
 	handlers "gateway-api/internal/handlers/http/v1"
+	"gateway-api/internal/rabbitmq"
+	"gateway-api/internal/ratelimit"
 	"gateway-api/internal/service"
 	"net/http"
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
-	log "github.com/sirupsen/logrus"
-	"github.com/streadway/amqp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Server struct {
-	Host              string `envconfig:"HOST"`
-	Port              int    `envconfig:"PORT" required:"true"`
+	Host     string             `envconfig:"HOST"`
+	Port     int                `envconfig:"PORT" required:"true"`
+	Shutdown lifecycle.Shutdown `envconfig:"SHUTDOWN"`
+	// TrustedProxies are the addresses and CIDRs of the proxies in front of
+	// the gateway, such as the ingress. Only their X-Forwarded-For is
+	// believed; with none, the client IP is the peer address.
+	TrustedProxies    []string `envconfig:"TRUSTED_PROXIES"`
 	LibraryClient     *client.Library
 	RatingClient      *client.Rating
 	ReservationClient *client.Reservation
-	GinRouter         *gin.Engine
-	RmqChannel        *amqp.Channel
-	libQueue          string
-	ratingQueue       string
-	reservationQueue  string
+	NotifyClient      *client.Notification
+	// Webhooks is nil when webhooks are off.
+	Webhooks         *service.WebhookService
+	GinRouter        *gin.Engine
+	Rmq              *rabbitmq.Manager
+	ServiceAuth      *auth.ServiceToken
+	Verifier         *auth.Verifier `ignored:"true"`
+	Limiter          *ratelimit.Limiter
+	libQueue         string
+	ratingQueue      string
+	reservationQueue string
+	ready            atomic.Bool
+	health           *health.Checker
 }
 
 func New(
 	host string,
 	port int,
+	shutdown lifecycle.Shutdown,
+	trustedProxies []string,
 	libSys *client.Library,
 	rateSys *client.Rating,
 	resSys *client.Reservation,
-	rmqChannel *amqp.Channel,
+	notifySys *client.Notification,
+	webhooks *service.WebhookService,
+	rmq *rabbitmq.Manager,
+	serviceAuth *auth.ServiceToken,
+	verifier *auth.Verifier,
+	limiter *ratelimit.Limiter,
 ) (*Server, error) {
 	s := &Server{
 		Host:              host,
 		Port:              port,
-		GinRouter:         gin.Default(),
+		Shutdown:          shutdown,
+		TrustedProxies:    trustedProxies,
+		GinRouter:         gin.New(),
 		LibraryClient:     libSys,
 		RatingClient:      rateSys,
 		ReservationClient: resSys,
-		RmqChannel:        rmqChannel,
+		NotifyClient:      notifySys,
+		Webhooks:          webhooks,
+		Rmq:               rmq,
+		ServiceAuth:       serviceAuth,
+		Verifier:          verifier,
+		Limiter:           limiter,
 		libQueue:          "lib-status-queue",
 		ratingQueue:       "rate-status-queue",
 		reservationQueue:  "reservation-status-queue",
 	}
 
+	// the gin context stands in for the request context in places, so it
+	// must answer with the request's values, the trace span among them.
+	// Backend calls take c.Request.Context() instead: the transport may still
+	// be done with them after gin has moved on from c.
+	s.GinRouter.ContextWithFallback = true
+	// the client rate limit keys on the client IP, which anyone could set
+	// if every peer were trusted to forward it
+	if err := s.GinRouter.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+	s.GinRouter.Use(logging.Middleware(), metrics.Middleware(), gin.Recovery(), otelgin.Middleware("gateway-api"))
+
 	if err := s.initRoutes(); err != nil {
 		return nil, err
 	}
@@ -59,28 +105,50 @@ func (s *Server) initRoutes() error {
 		c.JSON(http.StatusOK, gin.H{"msg": "pong"})
 	})
 
-	s.GinRouter.GET("/manage/health", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
+	// metrics are served apart, on a port that is not published
+	s.initHealthRoutes()
+	if err := s.initDocsRoutes(); err != nil {
+		return err
+	}
 
-	authMiddleware := auth.AuthMiddleware()
+	authMiddleware := auth.AuthMiddleware(s.Verifier)
 
-	v1 := s.GinRouter.Group("/api/v1")
-	v1.Use(authMiddleware)
+	protected := []gin.HandlerFunc{
+		s.Limiter.Clients(),
+		authMiddleware,
+		auth.Require(auth.Patron, auth.Service),
+		s.Limiter.Users(),
+	}
+	v1 := s.GinRouter.Group("/api/v1", protected...)
 
 	libService := service.NewLibraryService(s.LibraryClient)
 	libHandler := handlers.NewLibraryHandler(libService)
-	libHandler.RegisterRoutes(v1)
+
+	if err := s.initPassthroughRoutes(v1); err != nil {
+		return err
+	}
 
 	rateService := service.NewRatingService(s.RatingClient)
 	rateHandler := handlers.NewRatingHandler(rateService)
-	rateHandler.RegisterRoutes(v1)
+
+	notifyService := service.NewNotificationService(s.NotifyClient)
+	notifyHandler := handlers.NewNotificationHandler(notifyService)
+	notifyHandler.RegisterRoutes(v1)
+
+	// integrators are neither patrons nor services; the webhook routes
+	// require roles of their own
+	if s.Webhooks != nil {
+		partners := s.GinRouter.Group("/api/v1", s.Limiter.Clients(), authMiddleware, s.Limiter.Users())
+		webhookHandler := handlers.NewWebhookHandler(s.Webhooks)
+		webhookHandler.RegisterRoutes(partners)
+	}
 
 	reservationService := service.NewReservationService(
 		s.ReservationClient,
 		s.LibraryClient,
 		s.RatingClient,
-		s.RmqChannel,
+		s.Rmq,
+		s.ServiceAuth,
 		s.libQueue,
 		s.ratingQueue,
 		s.reservationQueue,
@@ -88,68 +156,23 @@ func (s *Server) initRoutes() error {
 	reservationHandler := handlers.NewReservationHandler(reservationService)
 	reservationHandler.RegisterRoutes(v1)
 
-	queues := []string{
-		s.reservationQueue,
-		s.libQueue,
-		s.ratingQueue,
-	}
+	api := &handlers.API{Library: libHandler, Rating: rateHandler, Reservation: reservationHandler}
+	api.Register(s.GinRouter.Group("", protected...))
 
-	for _, q := range queues {
-		_, err := s.RmqChannel.QueueDeclare(
-			q,
-			true,  // durable
-			false, // autoDelete
-			false, // exclusive
-			false, // noWait
-			nil,   // args
-		)
-		if err != nil {
-			log.Fatalf("failed to declare queue %s: %v", q, err)
-		}
+	s.Rmq.Declare(s.RetryQueues()...)
+	for queue, process := range reservationService.RetryHandlers() {
+		s.Rmq.Consume(queue, process)
 	}
 
-	//token, exists := c.Get("token")
-	//if !exists {
-	//	c.JSON(http.StatusUnauthorized, gin.H{"error": "no claims found"})
-	//	return
-	//}
-	//
-	//tokenStr, ok := token.(string)
-	//if !ok {
-	//	c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-	//	return
-	//}
-	//
-	//err := rabbitmq.RunRetryWorker(s.RmqChannel, s.reservationQueue, func(evt dto.ReturnRetryEvent) error {
-	//	return s.ReservationClient.UpdateStatus(evt.ReservationUID, evt.Date)
-	//})
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//err = rabbitmq.RunRetryWorker(s.RmqChannel, s.libQueue, func(evt dto.ReturnRetryEvent) error {
-	//	if err := s.LibraryClient.UpdateBookCount(evt.LibraryUID, evt.BookUID, +1); err != nil {
-	//		return err
-	//	}
-	//	if evt.Condition != "" {
-	//		return s.LibraryClient.UpdateBookCondition(evt.BookUID, evt.Condition)
-	//	}
-	//	return nil
-	//})
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//err = rabbitmq.RunRetryWorker(s.RmqChannel, s.ratingQueue, func(evt dto.ReturnRetryEvent) error {
-	//	return s.RatingClient.Update(evt.Username, evt.RateDelta)
-	//})
-	//if err != nil {
-	//	return err
-	//}
-
 	return nil
 }
 
-func (s *Server) Run() error {
-	return s.GinRouter.Run(fmt.Sprintf("%s:%d", s.Host, s.Port))
+// RetryQueues are the queues the server resumes returns from.
+func (s *Server) RetryQueues() []string {
+	return []string{s.reservationQueue, s.libQueue, s.ratingQueue}
+}
+
+// Run serves until ctx is cancelled, then drains as lifecycle.Run does.
+func (s *Server) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, fmt.Sprintf("%s:%d", s.Host, s.Port), s.GinRouter, s.Shutdown, &s.ready)
 }
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"contract"
+	"contract/library"
 	"gateway-api/internal/client"
-	"gateway-api/internal/dto"
+
+	"github.com/google/uuid"
 )
 
 type LibraryService struct {
@@ -13,10 +17,73 @@ func NewLibraryService(c *client.Library) *LibraryService {
 	return &LibraryService{Client: c}
 }
 
-func (s *LibraryService) GetLibraries(city string, page, size int, token string) (*dto.LibraryPaginationResponse, error) {
-	return s.Client.GetLibraries(city, page, size, token)
+func (s *LibraryService) GetLibraries(ctx context.Context, params contract.GetApiV1LibrariesParams, token string) (*contract.LibraryPaginationResponse, error) {
+	res, err := s.Client.GetLibraries(ctx, library.ListLibrariesParams{
+		Page: params.Page,
+		Size: params.Size,
+		City: params.City,
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]contract.LibraryResponse, 0, len(res.Items))
+	for _, l := range res.Items {
+		items = append(items, toLibrary(l))
+	}
+	return &contract.LibraryPaginationResponse{
+		Page:          res.Page,
+		PageSize:      res.PageSize,
+		TotalElements: res.TotalElements,
+		Items:         items,
+	}, nil
+}
+
+func (s *LibraryService) GetLibraryBooks(ctx context.Context, libraryUid uuid.UUID, params contract.GetApiV1LibrariesLibraryUidBooksParams, token string) (*contract.LibraryBookPaginationResponse, error) {
+	res, err := s.Client.GetLibraryBooks(ctx, libraryUid, library.ListBooksParams{
+		Page:    params.Page,
+		Size:    params.Size,
+		ShowAll: params.ShowAll,
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]contract.LibraryBookResponse, 0, len(res.Items))
+	for _, b := range res.Items {
+		items = append(items, contract.LibraryBookResponse{
+			BookUid:        b.BookUid,
+			Name:           b.Name,
+			Author:         b.Author,
+			Genre:          b.Genre,
+			Condition:      contract.LibraryBookResponseCondition(b.Condition),
+			AvailableCount: b.AvailableCount,
+		})
+	}
+	return &contract.LibraryBookPaginationResponse{
+		Page:          res.Page,
+		PageSize:      res.PageSize,
+		TotalElements: res.TotalElements,
+		Items:         items,
+	}, nil
+}
+
+// toLibrary is a library of library-system as the contract shows it.
+func toLibrary(l library.LibraryResponse) contract.LibraryResponse {
+	return contract.LibraryResponse{
+		LibraryUid: l.LibraryUid,
+		Name:       l.Name,
+		Address:    l.Address,
+		City:       l.City,
+	}
 }
 
-func (s *LibraryService) GetLibraryBooks(libraryUid string, page, size int, showAll bool, token string) (*dto.LibraryBookPaginationResponse, error) {
-	return s.Client.GetLibraryBooks(libraryUid, page, size, showAll, token)
+// toBookInfo is a book of library-system as a reservation shows it.
+func toBookInfo(b library.BookResponse) contract.BookInfo {
+	return contract.BookInfo{
+		BookUid: b.BookUid,
+		Name:    b.Name,
+		Author:  b.Author,
+		Genre:   b.Genre,
+	}
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"contract/notification"
+	"gateway-api/internal/client"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	Client *client.Notification
+}
+
+func NewNotificationService(client *client.Notification) *NotificationService {
+	return &NotificationService{Client: client}
+}
+
+func (s *NotificationService) List(ctx context.Context, params notification.ListNotificationsParams, token string) ([]notification.NotificationResponse, error) {
+	return s.Client.List(ctx, params, token)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, uid uuid.UUID, username string, token string) error {
+	return s.Client.MarkRead(ctx, uid, username, token)
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, username string, token string) (*notification.Preferences, error) {
+	return s.Client.GetPreferences(ctx, username, token)
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, username string, token string, prefs notification.Preferences) (*notification.Preferences, error) {
+	return s.Client.UpdatePreferences(ctx, username, token, prefs)
+}
@@ -1,8 +1,9 @@
 package service
 
 import (
+	"context"
+	"contract"
 	"gateway-api/internal/client"
-	"gateway-api/internal/dto"
 )
 
 type RatingService struct {
@@ -13,6 +14,10 @@ func NewRatingService(client *client.Rating) *RatingService {
 	return &RatingService{Client: client}
 }
 
-func (s *RatingService) GetRating(username string, token string) (*dto.UserRatingResponse, error) {
-	return s.Client.Get(username, token)
+func (s *RatingService) GetRating(ctx context.Context, username string, token string) (*contract.UserRatingResponse, error) {
+	res, err := s.Client.Get(ctx, username, token)
+	if err != nil {
+		return nil, err
+	}
+	return &contract.UserRatingResponse{Stars: res.Stars}, nil
 }
@@ -1,26 +1,65 @@
 package service
 
 import (
+	"context"
+	"contract"
+	"contract/library"
+	"contract/reservation"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"gateway-api/internal/client"
 	"gateway-api/internal/dto"
 	"gateway-api/pkg/ext"
+	"platform/auth"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
+	"time"
 
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/streadway/amqp"
 )
 
+// Publisher sends a message to a queue.
+type Publisher interface {
+	Publish(queue string, msg amqp.Publishing) error
+}
+
 type ReservationService struct {
 	ClientRes        *client.Reservation
 	ClientLib        *client.Library
 	ClientRate       *client.Rating
-	rmqChannel       *amqp.Channel
+	publisher        Publisher
+	serviceAuth      *auth.ServiceToken
 	libQueue         string
 	ratingQueue      string
 	reservationQueue string
 }
 
+// serviceToken returns the Authorization header for the calls that need the
+// service role. Without it those calls can't be made at all, which the
+// caller sees as a service being unavailable.
+func (s *ReservationService) serviceToken() (string, error) {
+	token, err := s.serviceAuth.Authorization()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ext.ServiceUnavailableError, err)
+	}
+	return token, nil
+}
+
+// stepTimeout bounds a step that undoes or completes what a request has
+// started.
+const stepTimeout = 10 * time.Second
+
+// detached returns a context for such a step. It keeps the values of ctx but
+// not its cancellation: a client that goes away must not leave a copy off
+// the shelf or a reservation half cancelled.
+func detached(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), stepTimeout)
+}
+
 func mapUnavailable(err error, target error) error {
 	if errors.Is(err, ext.ServiceUnavailableError) {
 		return target
@@ -32,7 +71,8 @@ func NewReservationService(
 	clRes *client.Reservation,
 	clLib *client.Library,
 	clRate *client.Rating,
-	rmqChannel *amqp.Channel,
+	publisher Publisher,
+	serviceAuth *auth.ServiceToken,
 	libQ string,
 	ratingQ string,
 	reservationQ string,
@@ -41,239 +81,465 @@ func NewReservationService(
 		ClientRes:        clRes,
 		ClientLib:        clLib,
 		ClientRate:       clRate,
-		rmqChannel:       rmqChannel,
+		publisher:        publisher,
+		serviceAuth:      serviceAuth,
 		libQueue:         libQ,
 		ratingQueue:      ratingQ,
 		reservationQueue: reservationQ,
 	}
 }
 
-func (s *ReservationService) Get(username string, token string) ([]dto.ReservationFullResponse, error) {
-	raw, err := s.ClientRes.Get(username, token)
+func (s *ReservationService) Get(ctx context.Context, username string, token string) ([]dto.ReservationFullResponse, error) {
+	raw, err := s.ClientRes.Get(ctx, username, token)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]dto.ReservationFullResponse, 0, len(raw))
 	for _, r := range raw {
-		book, err := s.ClientLib.GetBookByUID(r.BookUID, token)
+		book, err := s.ClientLib.GetBookByUID(ctx, r.BookUid, r.LibraryUid, token)
 		if err != nil {
 			return nil, err
 		}
 
-		lib, err := s.ClientLib.GetLibraryByUID(r.LibraryUID, token)
+		lib, err := s.ClientLib.GetLibraryByUID(ctx, r.LibraryUid, token)
 		if err != nil {
 			return nil, err
 		}
 
-		fullRes := dto.ReservationToFull(r, dto.BookToRaw(*book), *lib)
-		result = append(result, fullRes)
+		result = append(result, toReservation(r, *book, *lib))
 	}
 	return result, nil
 }
 
-func (s *ReservationService) CreateReservation(username string, token string, req dto.CreateReservationRequest) (*dto.ReservationFullResponse, error) {
+// toReservation is a reservation of reservation-system with its book and
+// library as the gateway shows it.
+func toReservation(r reservation.ReservationResponse, book library.BookResponse, lib library.LibraryResponse) dto.ReservationFullResponse {
+	return dto.ReservationFullResponse{
+		BookReservationResponse: contract.BookReservationResponse{
+			ReservationUid: r.ReservationUid,
+			Status:         contract.BookReservationResponseStatus(r.Status),
+			StartDate:      r.StartDate.String(),
+			TillDate:       r.TillDate.String(),
+			Book:           toBookInfo(book),
+			Library:        toLibrary(lib),
+		},
+		Username: r.Username,
+	}
+}
+
+func (s *ReservationService) CreateReservation(ctx context.Context, username string, token string, req dto.CreateReservationRequest) (*contract.TakeBookResponse, error) {
+	tillDate, err := time.Parse(time.DateOnly, req.TillDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse till date: %w", err)
+	}
 
-	resCount, err := s.ClientRes.GetCurrentAmount(username, token)
+	resCount, err := s.ClientRes.GetCurrentAmount(ctx, username, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current amount: %s", err)
+		return nil, fmt.Errorf("failed to get current amount: %w", err)
 	}
 
-	starsCount, err := s.ClientRate.Get(username, token)
+	starsCount, err := s.ClientRate.Get(ctx, username, token)
 	if err != nil {
 		if errors.Is(err, ext.ServiceUnavailableError) {
 			return nil, ext.RatingServiceUnavailableError
 		}
-		return nil, fmt.Errorf("failed to get rating: %s", err)
+		return nil, fmt.Errorf("failed to get rating: %w", err)
 	}
-	books, err := s.ClientLib.GetBookByUID(req.BookUID, token)
+	books, err := s.ClientLib.GetBookByUID(ctx, req.BookUid, req.LibraryUid, token)
 	if err != nil {
 		if errors.Is(err, ext.ServiceUnavailableError) {
 			return nil, ext.LibraryServiceUnavailableError
 		}
-		return nil, fmt.Errorf("failed to get book by uid: %s", err)
+		return nil, fmt.Errorf("failed to get book by uid: %w", err)
 	}
-	if books.AvailableCount < 0 {
+	if books.AvailableCount <= 0 {
 		return nil, ext.BookNotAvailableError
 	}
 	if resCount >= starsCount.Stars {
-		return nil, fmt.Errorf("You rented maximum amount of books", resCount)
+		return nil, ext.ReservationLimitError
 	}
-	result, err := s.ClientRes.Create(username, token, req)
+	// moving stock and opening the reservation need the service role
+	svcToken, err := s.serviceToken()
 	if err != nil {
+		return nil, err
+	}
+
+	create := reservation.CreateReservationRequest{
+		BookUid:    req.BookUid,
+		LibraryUid: req.LibraryUid,
+		TillDate:   openapi_types.Date{Time: tillDate},
+	}
+	release, err := s.takeCopy(ctx, &create, req.PickupLibraryUid, svcToken)
+	if err != nil {
+		return nil, mapUnavailable(err, ext.LibraryServiceUnavailableError)
+	}
+
+	result, err := s.ClientRes.Create(ctx, username, svcToken, create)
+	if err != nil {
+		// the copy is already off the shelf, put it back before failing
+		release(username)
 		if errors.Is(err, ext.ServiceUnavailableError) {
 			return nil, ext.ReservationServiceUnavailableError
 		}
-		return nil, fmt.Errorf("failed to create reservation: %s", err)
+		return nil, fmt.Errorf("failed to create reservation: %w", err)
 	}
-	err = s.ClientLib.UpdateBookCount(result.LibraryUID, result.BookUID, -1, token)
+
+	book, err := s.ClientLib.GetBookByUID(ctx, result.BookUid, result.LibraryUid, token)
 	if err != nil {
+		return nil, s.undoCreate(ctx, result.ReservationUid, username, svcToken, release,
+			mapUnavailable(err, ext.LibraryServiceUnavailableError))
+	}
+	lib, err := s.ClientLib.GetLibraryByUID(ctx, result.LibraryUid, token)
+	if err != nil {
+		return nil, s.undoCreate(ctx, result.ReservationUid, username, svcToken, release,
+			mapUnavailable(err, ext.LibraryServiceUnavailableError))
+	}
 
-		return nil, fmt.Errorf("failed to update book count: %s", err)
+	return &contract.TakeBookResponse{
+		ReservationUid: result.ReservationUid,
+		Status:         contract.TakeBookResponseStatus(result.Status),
+		StartDate:      result.StartDate.String(),
+		TillDate:       result.TillDate.String(),
+		Book:           toBookInfo(*book),
+		Library:        toLibrary(*lib),
+		Rating:         contract.UserRatingResponse{Stars: starsCount.Stars},
+	}, nil
+}
+
+// undoCreate drops a reservation that was made but can't be shown to the
+// patron, then puts its copy back, and returns cause. A reservation that
+// can't be dropped keeps its copy.
+func (s *ReservationService) undoCreate(ctx context.Context, reservationUID uuid.UUID, username, token string, release func(string), cause error) error {
+	undoCtx, cancel := detached(ctx)
+	defer cancel()
+	if err := s.ClientRes.DeleteReservation(undoCtx, reservationUID, username, token); err != nil {
+		return fmt.Errorf("%w; failed to delete reservation %s: %v", cause, reservationUID, err)
 	}
+	release(username)
+	return cause
+}
 
-	book, err := s.ClientLib.GetBookByUID(result.BookUID, token)
-	if err != nil {
-		err := s.ClientRes.DeleteReservation(result.ReservationUID, token)
+// takeCopy takes a copy of the requested book off the shelf and points req
+// at it. When the patron wants to pick the book up at another library, a
+// held transfer to pickup is requested instead and the reservation is made
+// there. The returned func undoes the step if the reservation can't be
+// created.
+func (s *ReservationService) takeCopy(ctx context.Context, req *reservation.CreateReservationRequest, pickup uuid.UUID, token string) (func(username string), error) {
+	if pickup != uuid.Nil && pickup != req.LibraryUid {
+		transfer, err := s.ClientLib.RequestTransfer(ctx, library.TransferRequest{
+			BookUid:        req.BookUid,
+			FromLibraryUid: req.LibraryUid,
+			ToLibraryUid:   pickup,
+			Hold:           true,
+		}, token)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete book: %s", err)
+			return nil, err
 		}
-		return nil, err
+		req.CopyUid = transfer.CopyUid
+		req.LibraryUid = pickup
+		return func(string) {
+			ctx, cancel := detached(ctx)
+			defer cancel()
+			// there is no retry queue for transfers, a librarian has to
+			// cancel it by hand
+			if err := s.ClientLib.CancelTransfer(ctx, transfer.TransferUid, token); err != nil {
+				logging.FromContext(ctx).WithError(err).Errorf("failed to cancel transfer %s of a reservation that failed", transfer.TransferUid)
+			}
+		}, nil
 	}
-	lib, err := s.ClientLib.GetLibraryByUID(result.LibraryUID, token)
+
+	bookCopy, err := s.ClientLib.ReserveCopy(ctx, req.LibraryUid, req.BookUid, token)
 	if err != nil {
 		return nil, err
 	}
-	fullRes := dto.ReservationToFull(*result, dto.BookToRaw(*book), *lib)
-
-	return &fullRes, nil
+	req.CopyUid = bookCopy.CopyUid
+	return func(username string) {
+		ctx, cancel := detached(ctx)
+		defer cancel()
+		if relErr := s.ClientLib.ReleaseCopy(ctx, bookCopy.CopyUid, "", token); relErr != nil {
+			err := s.enqueueReturn(ctx, dto.ReturnRetryEvent{
+				Username:   username,
+				BookUID:    req.BookUid,
+				LibraryUID: req.LibraryUid,
+				CopyUID:    bookCopy.CopyUid,
+			}, s.libQueue)
+			if err != nil {
+				logging.FromContext(ctx).WithError(err).Errorf("copy %s stays reserved", bookCopy.CopyUid)
+			}
+		}
+	}, nil
 }
 
-func (s *ReservationService) ReturnBook_(
-	username string,
-	req dto.ReturnReservationRequest,
-	reservationUID string,
-	token string,
-) error {
-	rate := 1
-	err := s.ClientRes.UpdateStatus(reservationUID, req.Date, token)
+// expiredPenalty is how many stars an overdue return costs.
+const expiredPenalty = 10
+
+// GetReservation returns one reservation of the user together with how many
+// days it is overdue and the penalty returning it today would bring.
+func (s *ReservationService) GetReservation(ctx context.Context, username string, token string, reservationUID uuid.UUID) (*dto.ReservationDetailsResponse, error) {
+	svcToken, err := s.serviceToken()
 	if err != nil {
-		return fmt.Errorf("failed to update status: %s", err)
+		return nil, err
 	}
-	res, err := s.ClientRes.GetByUID(reservationUID, token)
+
+	res, err := s.ClientRes.GetByUID(ctx, reservationUID, username, svcToken)
 	if err != nil {
-		return fmt.Errorf("failed to get reservation by uid: %s", err)
+		return nil, mapUnavailable(err, ext.ReservationServiceUnavailableError)
 	}
-	if res.Status == "EXPIRED" {
-		rate = -10
+	book, err := s.ClientLib.GetBookByUID(ctx, res.BookUid, res.LibraryUid, token)
+	if err != nil {
+		return nil, mapUnavailable(err, ext.LibraryServiceUnavailableError)
 	}
-	book, err := s.ClientLib.GetBookByUID(res.BookUID, token)
+	lib, err := s.ClientLib.GetLibraryByUID(ctx, res.LibraryUid, token)
 	if err != nil {
-		return fmt.Errorf("failed to get book by uid: %s", err)
+		return nil, mapUnavailable(err, ext.LibraryServiceUnavailableError)
 	}
-	if book.Condition != req.Condition {
-		rate = -10
-		err = s.ClientLib.UpdateBookCondition(res.BookUID, req.Condition, token)
-		if err != nil {
-			return fmt.Errorf("failed to update book condition: %s", err)
+
+	details := dto.ReservationDetailsResponse{
+		ReservationFullResponse: toReservation(*res, *book, *lib),
+	}
+	if res.Status == reservation.RENTED {
+		tillDate := res.TillDate.Time
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		if today.After(tillDate) {
+			details.OverdueDays = int(today.Sub(tillDate).Hours() / 24)
+			details.ProjectedPenalty = expiredPenalty
 		}
 	}
+	return &details, nil
+}
+
+// CancelReservation drops a rented reservation and puts its copy back into
+// stock: a transfer still under way is cancelled, a copy held at the library
+// is released there. The copy is restocked first, so a reservation that
+// can't be dropped is cancelled again later without double counting.
+func (s *ReservationService) CancelReservation(ctx context.Context, username string, token string, reservationUID uuid.UUID) error {
+	svcToken, err := s.serviceToken()
+	if err != nil {
+		return err
+	}
 
-	err = s.ClientLib.UpdateBookCount(res.LibraryUID, res.BookUID, 1, token)
+	res, err := s.ClientRes.GetByUID(ctx, reservationUID, username, svcToken)
 	if err != nil {
-		return fmt.Errorf("failed to update book count: %s", err)
+		return mapUnavailable(err, ext.ReservationServiceUnavailableError)
+	}
+	if res.Status != reservation.RENTED {
+		return ext.ReservationClosedError
+	}
+
+	// once under way the cancellation is seen through
+	ctx, cancel := detached(ctx)
+	defer cancel()
+
+	if res.CopyUid == uuid.Nil {
+		err = s.ClientLib.UpdateBookCount(ctx, res.LibraryUid, res.BookUid, +1, "", svcToken)
+	} else {
+		err = s.restockCopy(ctx, res.CopyUid, svcToken)
 	}
-	err = s.ClientRate.Update(username, rate, token)
 	if err != nil {
-		return fmt.Errorf("failed to update rate: %s", err)
+		return mapUnavailable(err, ext.LibraryServiceUnavailableError)
+	}
+
+	if err := s.ClientRes.DeleteReservation(ctx, reservationUID, username, svcToken); err != nil {
+		return mapUnavailable(err, ext.ReservationServiceUnavailableError)
 	}
 	return nil
 }
 
-func (s *ReservationService) ReturnBook(username string, token string, req dto.ReturnReservationRequest, reservationUID string) error {
-	rate := 1
+// restockCopy puts a copy taken for a reservation back into stock. A copy
+// already back on the shelf, or already on its way back without a hold, is
+// left as it is so that the step can be repeated.
+func (s *ReservationService) restockCopy(ctx context.Context, copyUID uuid.UUID, token string) error {
+	bookCopy, err := s.ClientLib.GetCopy(ctx, copyUID, token)
+	if err != nil {
+		return err
+	}
+	switch bookCopy.Status {
+	case library.CopyStatusAVAILABLE:
+		return nil
+	case library.CopyStatusINTRANSIT:
+	default:
+		return s.ClientLib.ReleaseCopy(ctx, copyUID, "", token)
+	}
 
-	if err := s.ClientRes.UpdateStatus(reservationUID, req.Date, token); err != nil {
-		if errors.Is(err, ext.ServiceUnavailableError) {
-			evt := dto.ReturnRetryEvent{
-				Username:       username,
-				ReservationUID: reservationUID,
-				Date:           req.Date,
-				RateDelta:      rate,
+	transfers, err := s.ClientLib.ListTransfers(ctx, copyUID, token)
+	if err != nil {
+		return err
+	}
+	for _, t := range transfers {
+		if t.Status == library.TransferStatusREQUESTED || t.Status == library.TransferStatusINTRANSIT {
+			if !t.Hold {
+				return nil
 			}
-			s.enqueueReturn(evt, s.reservationQueue)
-			return nil // пользователю success
+			return s.ClientLib.CancelTransfer(ctx, t.TransferUid, token)
+		}
+	}
+	return fmt.Errorf("no open transfer for copy %s", copyUID)
+}
+
+// ReturnBook closes the reservation and puts the copy back into stock.
+// rating-system adjusts the rating from the event the return publishes. A
+// step whose service is down is handed to that step's retry queue together
+// with the rest of the return, and the patron gets success; when the queue
+// can't take it either the patron is told the service is unavailable.
+func (s *ReservationService) ReturnBook(ctx context.Context, username string, token string, req contract.ReturnBookRequest, reservationUID uuid.UUID) error {
+	svcToken, err := s.serviceToken()
+	if err != nil {
+		return err
+	}
+	// the steps are seen through, or handed to a retry queue, even when the
+	// patron goes away
+	ctx, cancel := detached(ctx)
+	defer cancel()
+
+	evt := dto.ReturnRetryEvent{
+		Username:       username,
+		ReservationUID: reservationUID,
+		Date:           req.Date,
+		Condition:      string(req.Condition),
+	}
+	update, err := closeRequest(evt.Condition, evt.Date)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ClientRes.UpdateStatus(ctx, reservationUID, username, update, svcToken); err != nil {
+		if errors.Is(err, ext.ServiceUnavailableError) {
+			return s.enqueueReturn(ctx, evt, s.reservationQueue)
 		}
 		return fmt.Errorf("failed to update reservation status: %w", err)
 	}
 
-	res, err := s.ClientRes.GetByUID(reservationUID, token)
+	res, err := s.ClientRes.GetByUID(ctx, reservationUID, username, svcToken)
 	if err != nil {
 		if errors.Is(err, ext.ServiceUnavailableError) {
-			evt := dto.ReturnRetryEvent{
-				Username:       username,
-				ReservationUID: reservationUID,
-				Date:           req.Date,
-				RateDelta:      rate,
-			}
-			s.enqueueReturn(evt, s.reservationQueue)
-			return nil
+			return s.enqueueReturn(ctx, evt, s.reservationQueue)
 		}
 		return fmt.Errorf("failed to get reservation by uid: %w", err)
 	}
 
-	// Если истек срок — штраф
-	if res.Status == "EXPIRED" {
-		rate = -10
+	evt.BookUID, evt.LibraryUID, evt.CopyUID = res.BookUid, res.LibraryUid, res.CopyUid
+	if err := s.stock(ctx, evt, svcToken); err != nil {
+		if errors.Is(err, ext.ServiceUnavailableError) {
+			return s.enqueueReturn(ctx, evt, s.libQueue)
+		}
+		return err
 	}
+	return nil
+}
 
-	if err := s.ClientLib.UpdateBookCount(res.LibraryUID, res.BookUID, +1, token); err != nil {
-		if errors.Is(err, ext.ServiceUnavailableError) {
-			evt := dto.ReturnRetryEvent{
-				Username:       username,
-				ReservationUID: reservationUID,
-				BookUID:        res.BookUID,
-				LibraryUID:     res.LibraryUID,
-				RateDelta:      rate,
-				Condition:      req.Condition,
-			}
-			s.enqueueReturn(evt, s.libQueue)
-			return nil
+// closeRequest is the update that closes the reservation of a return.
+func closeRequest(condition, date string) (reservation.UpdateStatusRequest, error) {
+	returned, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return reservation.UpdateStatusRequest{}, fmt.Errorf("failed to parse return date: %w", err)
+	}
+	return reservation.UpdateStatusRequest{
+		Condition: reservation.Condition(condition),
+		Date:      openapi_types.Date{Time: returned},
+	}, nil
+}
+
+// stock puts the copy of a returned reservation back on the shelf.
+func (s *ReservationService) stock(ctx context.Context, evt dto.ReturnRetryEvent, token string) error {
+	condition := library.Condition(evt.Condition)
+	if evt.CopyUID != uuid.Nil {
+		if err := s.ClientLib.ReleaseCopy(ctx, evt.CopyUID, condition, token); err != nil {
+			return fmt.Errorf("failed to release book copy: %w", err)
 		}
+		return nil
+	}
+
+	// reservations made before copies were tracked individually: a copy in
+	// the condition the book came back in is shelved in its place
+	if err := s.ClientLib.UpdateBookCount(ctx, evt.LibraryUID, evt.BookUID, +1, condition, token); err != nil {
 		return fmt.Errorf("failed to update book count: %w", err)
 	}
+	return nil
+}
+
+// RetryHandlers resumes returns from the retry queues. A handler failing
+// because a service is still down keeps its message to try again.
+func (s *ReservationService) RetryHandlers() map[string]func(ctx context.Context, evt dto.ReturnRetryEvent) error {
+	return map[string]func(ctx context.Context, evt dto.ReturnRetryEvent) error{
+		s.reservationQueue: s.retryStatus,
+		s.libQueue:         s.retryStock,
+		s.ratingQueue:      s.retryRating,
+	}
+}
 
-	book, err := s.ClientLib.GetBookByUID(res.BookUID, token)
+// retryStatus closes the reservation of a return and restocks its copy.
+func (s *ReservationService) retryStatus(ctx context.Context, evt dto.ReturnRetryEvent) error {
+	svcToken, err := s.serviceToken()
 	if err != nil {
-		return fmt.Errorf("failed to get book by uid: %s", err)
-	}
-
-	if req.Condition != "" && req.Condition != book.Condition {
-		if err := s.ClientLib.UpdateBookCondition(res.BookUID, req.Condition, token); err != nil {
-			if errors.Is(err, ext.ServiceUnavailableError) {
-				evt := dto.ReturnRetryEvent{
-					Username:       username,
-					ReservationUID: reservationUID,
-					BookUID:        res.BookUID,
-					LibraryUID:     res.LibraryUID,
-					RateDelta:      rate,
-					Condition:      req.Condition,
-				}
-				s.enqueueReturn(evt, s.libQueue)
-				return nil
-			}
-			return fmt.Errorf("failed to update book condition: %w", err)
-		}
+		return err
+	}
+	update, err := closeRequest(evt.Condition, evt.Date)
+	if err != nil {
+		return err
+	}
+	// an earlier attempt may have closed the reservation already
+	err = s.ClientRes.UpdateStatus(ctx, evt.ReservationUID, evt.Username, update, svcToken)
+	if errors.Is(err, ext.ServiceUnavailableError) {
+		return err
 	}
 
-	if err := s.ClientRate.Update(username, rate, token); err != nil {
-		if errors.Is(err, ext.ServiceUnavailableError) {
-			evt := dto.ReturnRetryEvent{
-				Username:       username,
-				ReservationUID: reservationUID,
-				BookUID:        res.BookUID,
-				LibraryUID:     res.LibraryUID,
-				RateDelta:      rate,
-			}
-			s.enqueueReturn(evt, s.ratingQueue)
-			return nil
-		}
-		return fmt.Errorf("failed to update user rating: %w", err)
+	res, err := s.ClientRes.GetByUID(ctx, evt.ReservationUID, evt.Username, svcToken)
+	if err != nil {
+		return fmt.Errorf("failed to get reservation by uid: %w", err)
+	}
+	if res.Status != reservation.RETURNED && res.Status != reservation.EXPIRED {
+		return fmt.Errorf("reservation %s is still %s", evt.ReservationUID, res.Status)
+	}
+
+	evt.BookUID, evt.LibraryUID, evt.CopyUID = res.BookUid, res.LibraryUid, res.CopyUid
+	return s.stock(ctx, evt, svcToken)
+}
+
+// retryStock restocks the copy of a return. Events without a reservation
+// come from cancelled or undone reservations.
+func (s *ReservationService) retryStock(ctx context.Context, evt dto.ReturnRetryEvent) error {
+	svcToken, err := s.serviceToken()
+	if err != nil {
+		return err
 	}
+	if evt.ReservationUID == uuid.Nil {
+		return s.restockCopy(ctx, evt.CopyUID, svcToken)
+	}
+	return s.stock(ctx, evt, svcToken)
+}
 
+// retryRating drains the rating queue of deltas enqueued before
+// rating-system took the returns from reservation events.
+func (s *ReservationService) retryRating(ctx context.Context, evt dto.ReturnRetryEvent) error {
+	if evt.RateDelta == 0 {
+		return nil
+	}
+	svcToken, err := s.serviceToken()
+	if err != nil {
+		return err
+	}
+	if err := s.ClientRate.Update(ctx, evt.Username, evt.RateDelta, svcToken); err != nil {
+		return fmt.Errorf("failed to update user rating: %w", err)
+	}
 	return nil
 }
 
-func (s *ReservationService) enqueueReturn(evt dto.ReturnRetryEvent, queue string) {
+// enqueueReturn hands the rest of a return to queue. It fails with
+// ext.ServiceUnavailableError when the queue can't take it.
+func (s *ReservationService) enqueueReturn(ctx context.Context, evt dto.ReturnRetryEvent, queue string) error {
 	body, _ := json.Marshal(evt)
-	_ = s.rmqChannel.Publish(
-		"",
-		queue,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
+	_, span, headers := tracing.StartPublish(ctx, queue, nil)
+	err := s.publisher.Publish(queue, amqp.Publishing{
+		ContentType:   "application/json",
+		CorrelationId: logging.RequestID(ctx),
+		Headers:       headers,
+		Body:          body,
+	})
+	tracing.End(span, err)
+	metrics.Published(queue, err)
+	if err != nil {
+		return fmt.Errorf("%w: failed to enqueue return %s to %s: %v", ext.ServiceUnavailableError, evt.ReservationUID, queue, err)
+	}
+	return nil
 }
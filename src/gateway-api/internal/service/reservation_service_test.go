@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"contract"
+	"contract/library"
+	"contract/rating"
+	"contract/reservation"
+	"encoding/json"
+	"errors"
+	"gateway-api/internal/client"
+	"gateway-api/internal/dto"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"net/http/httptest"
+	"platform/auth/authtest"
+	"platform/problem"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	libraryUID     = "83575e12-7ce0-48ee-9931-51919ff3c9ee"
+	bookUID        = "f7cdc58f-2caf-4b15-9727-f89dcc629b27"
+	copyUID        = "1c4d6a3e-5f0b-4f0e-9d7e-2b8a3f6c9e10"
+	reservationUID = "f464ca3a-fcf7-4e3f-86f0-76c7bba96f72"
+)
+
+var (
+	libraryID     = uuid.MustParse(libraryUID)
+	bookID        = uuid.MustParse(bookUID)
+	copyID        = uuid.MustParse(copyUID)
+	reservationID = uuid.MustParse(reservationUID)
+)
+
+func date(s string) openapi_types.Date {
+	t, _ := time.Parse(time.DateOnly, s)
+	return openapi_types.Date{Time: t}
+}
+
+// takeBook is the request of alice to take the book until 2021-10-11.
+func takeBook() dto.CreateReservationRequest {
+	return dto.CreateReservationRequest{TakeBookRequest: contract.TakeBookRequest{
+		BookUid: bookID, LibraryUid: libraryID, TillDate: "2021-10-11"}}
+}
+
+// backends stands in for library-, rating- and reservation-system. Routes
+// answer as set with handle; every call is recorded.
+type backends struct {
+	mu     sync.Mutex
+	routes map[string]http.HandlerFunc
+	calls  []string
+}
+
+func newBackends(t *testing.T) (*backends, string) {
+	b := &backends{routes: make(map[string]http.HandlerFunc)}
+	b.handle("GET /manage/health", http.StatusOK, nil)
+	b.handle("GET /api/v1/reservation/amount", http.StatusOK, reservation.AmountResponse{Amount: 0})
+	b.handle("GET /api/v1/rating/{$}", http.StatusOK, rating.RatingResponse{Stars: 75})
+	b.handle("GET /api/v1/books/{uid}/{$}", http.StatusOK, library.BookResponse{
+		BookUid: bookID, Name: "Book", Condition: library.EXCELLENT, AvailableCount: 1})
+	b.handle("GET /api/v1/libraries/{uid}/{$}", http.StatusOK, library.LibraryResponse{LibraryUid: libraryID, Name: "Library"})
+	b.handle("POST /api/v1/library/{lib}/books/{book}/copies/reserve", http.StatusOK, library.CopyResponse{
+		CopyUid: copyID, BookUid: bookID, LibraryUid: libraryID, Status: library.CopyStatusRESERVED})
+	b.handle("POST /api/v1/copies/{uid}/release", http.StatusOK, nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux := http.NewServeMux()
+		b.mu.Lock()
+		b.calls = append(b.calls, r.Method+" "+r.URL.Path)
+		for pattern, h := range b.routes {
+			mux.HandleFunc(pattern, h)
+		}
+		b.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return b, srv.URL
+}
+
+func (b *backends) handle(pattern string, status int, body any) {
+	b.handleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if body == nil {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	})
+}
+
+// handleFunc sets, or replaces, the answer of pattern.
+func (b *backends) handleFunc(pattern string, h http.HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.routes[pattern] = h
+}
+
+func (b *backends) called(call string) bool {
+	return b.order(call) >= 0
+}
+
+// order is the position of the first call, or -1 when it wasn't made.
+func (b *backends) order(call string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, c := range b.calls {
+		if c == call {
+			return i
+		}
+	}
+	return -1
+}
+
+func writeProblem(w http.ResponseWriter, status int, code problem.Code) {
+	w.Header().Set("Content-Type", problem.ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem.New(status, code, "from the backend"))
+}
+
+// publisher records what is published, failing with err if set.
+type publisher struct {
+	mu   sync.Mutex
+	sent map[string][]dto.ReturnRetryEvent
+	err  error
+}
+
+func (p *publisher) Publish(queue string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	var evt dto.ReturnRetryEvent
+	_ = json.Unmarshal(msg.Body, &evt)
+	if p.sent == nil {
+		p.sent = make(map[string][]dto.ReturnRetryEvent)
+	}
+	p.sent[queue] = append(p.sent[queue], evt)
+	return nil
+}
+
+func newTestService(t *testing.T, url string, pub Publisher) *ReservationService {
+	serviceAuth, tokens := authtest.NewMinter().ServiceToken()
+	t.Cleanup(tokens.Close)
+	return NewReservationService(
+		client.NewReservation(url),
+		client.NewLibrary(url),
+		client.NewRating(url),
+		pub,
+		serviceAuth,
+		"library-retry",
+		"rating-retry",
+		"reservation-retry",
+	)
+}
+
+func TestCreateReservation_ReleasesAfterRequestGone(t *testing.T) {
+	b, url := newBackends(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the patron goes away while the reservation is being made, and it fails
+	b.handleFunc("POST /api/v1/reservation/{$}", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		writeProblem(w, http.StatusInternalServerError, problem.Internal)
+	})
+
+	s := newTestService(t, url, &publisher{})
+	_, err := s.CreateReservation(ctx, "alice", "Bearer patron", takeBook())
+	require.Error(t, err)
+
+	assert.True(t, b.called("POST /api/v1/copies/"+copyUID+"/release"), "the copy is put back")
+}
+
+func TestCreateReservation_NoCopiesLeft(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("GET /api/v1/books/{uid}/{$}", http.StatusOK, library.BookResponse{
+		BookUid: bookID, Name: "Book", Condition: library.EXCELLENT, AvailableCount: 0})
+
+	s := newTestService(t, url, &publisher{})
+	_, err := s.CreateReservation(context.Background(), "alice", "Bearer patron", takeBook())
+	assert.ErrorIs(t, err, ext.BookNotAvailableError)
+	assert.False(t, b.called("POST /api/v1/library/"+libraryUID+"/books/"+bookUID+"/copies/reserve"))
+}
+
+func TestCreateReservation_UndoesWhenBookLookupFails(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("POST /api/v1/reservation/{$}", http.StatusCreated, rented(date("2021-10-11")))
+	b.handle("DELETE /api/v1/reservation/{uid}", http.StatusNoContent, nil)
+
+	// the book is found before the reservation is made, not after
+	var lookups atomic.Int32
+	b.handleFunc("GET /api/v1/books/{uid}/{$}", func(w http.ResponseWriter, r *http.Request) {
+		if lookups.Add(1) > 1 {
+			writeProblem(w, http.StatusServiceUnavailable, problem.ServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(library.BookResponse{BookUid: bookID, Name: "Book", AvailableCount: 1})
+	})
+
+	s := newTestService(t, url, &publisher{})
+	res, err := s.CreateReservation(context.Background(), "alice", "Bearer patron", takeBook())
+	assert.Nil(t, res)
+	assert.Error(t, err)
+
+	assert.True(t, b.called("DELETE /api/v1/reservation/"+reservationUID), "the reservation is dropped")
+	assert.True(t, b.called("POST /api/v1/copies/"+copyUID+"/release"), "the copy is put back")
+}
+
+func TestCreateReservation_KeepsCopyWhenDeleteFails(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("POST /api/v1/reservation/{$}", http.StatusCreated, rented(date("2021-10-11")))
+	b.handleFunc("DELETE /api/v1/reservation/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusInternalServerError, problem.Internal)
+	})
+	b.handleFunc("GET /api/v1/libraries/{uid}/{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusServiceUnavailable, problem.ServiceUnavailable)
+	})
+
+	s := newTestService(t, url, &publisher{})
+	res, err := s.CreateReservation(context.Background(), "alice", "Bearer patron", takeBook())
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.False(t, b.called("POST /api/v1/copies/"+copyUID+"/release"), "the reservation still holds the copy")
+}
+
+func TestCreateReservation_CancelsTransferWhenCreateFails(t *testing.T) {
+	const pickupUID = "2d5c9e8a-1f4b-4c3d-8e7a-6b5c4d3e2f1a"
+	b, url := newBackends(t)
+	b.handle("POST /api/v1/transfers", http.StatusCreated, library.TransferResponse{
+		TransferUid: uuid.MustParse("9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"), CopyUid: copyID, BookUid: bookID,
+		FromLibraryUid: libraryID, ToLibraryUid: uuid.MustParse(pickupUID), Hold: true, Status: library.TransferStatusREQUESTED})
+	b.handle("POST /api/v1/transfers/{uid}/cancel", http.StatusOK, nil)
+	b.handleFunc("POST /api/v1/reservation/{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusInternalServerError, problem.Internal)
+	})
+
+	s := newTestService(t, url, &publisher{})
+	req := takeBook()
+	req.PickupLibraryUid = uuid.MustParse(pickupUID)
+	_, err := s.CreateReservation(context.Background(), "alice", "Bearer patron", req)
+	require.Error(t, err)
+	assert.True(t, b.called("POST /api/v1/transfers/9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d/cancel"))
+	assert.False(t, b.called("POST /api/v1/copies/"+copyUID+"/release"), "the copy is the transfer's to put back")
+}
+
+func rented(tillDate openapi_types.Date) reservation.ReservationResponse {
+	return reservation.ReservationResponse{
+		ReservationUid: reservationID, Username: "alice", BookUid: bookID, LibraryUid: libraryID,
+		CopyUid: copyID, Status: reservation.RENTED, StartDate: date("2021-10-01"), TillDate: tillDate}
+}
+
+func TestGetReservation_Overdue(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tests := []struct {
+		name     string
+		status   reservation.ReservationStatus
+		tillDate time.Time
+		days     int
+		penalty  int
+	}{
+		{"on time", reservation.RENTED, today.AddDate(0, 0, 3), 0, 0},
+		{"due today", reservation.RENTED, today, 0, 0},
+		{"overdue", reservation.RENTED, today.AddDate(0, 0, -4), 4, expiredPenalty},
+		{"returned late", reservation.EXPIRED, today.AddDate(0, 0, -4), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, url := newBackends(t)
+			res := rented(openapi_types.Date{Time: tt.tillDate})
+			res.Status = tt.status
+			b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, res)
+
+			s := newTestService(t, url, &publisher{})
+			details, err := s.GetReservation(context.Background(), "alice", "Bearer patron", reservationID)
+			require.NoError(t, err)
+			assert.Equal(t, contract.BookReservationResponseStatus(tt.status), details.Status)
+			assert.Equal(t, "Book", details.Book.Name)
+			assert.Equal(t, tt.days, details.OverdueDays)
+			assert.Equal(t, tt.penalty, details.ProjectedPenalty)
+		})
+	}
+}
+
+func TestCancelReservation_RestocksBeforeDelete(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, rented(date("2021-10-11")))
+	b.handle("GET /api/v1/copies/{uid}", http.StatusOK, library.CopyResponse{
+		CopyUid: copyID, BookUid: bookID, LibraryUid: libraryID, Status: library.CopyStatusRESERVED})
+	b.handle("DELETE /api/v1/reservation/{uid}", http.StatusNoContent, nil)
+
+	s := newTestService(t, url, &publisher{})
+	require.NoError(t, s.CancelReservation(context.Background(), "alice", "Bearer patron", reservationID))
+
+	release := b.order("POST /api/v1/copies/" + copyUID + "/release")
+	drop := b.order("DELETE /api/v1/reservation/" + reservationUID)
+	require.GreaterOrEqual(t, release, 0, "the copy is put back")
+	assert.Greater(t, drop, release, "the reservation is dropped after its copy is back")
+}
+
+func TestCancelReservation_KeepsReservationWhenRestockFails(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, rented(date("2021-10-11")))
+	b.handle("GET /api/v1/copies/{uid}", http.StatusOK, library.CopyResponse{
+		CopyUid: copyID, BookUid: bookID, LibraryUid: libraryID, Status: library.CopyStatusRESERVED})
+	b.handleFunc("POST /api/v1/copies/{uid}/release", func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusConflict, problem.Conflict)
+	})
+
+	s := newTestService(t, url, &publisher{})
+	assert.Error(t, s.CancelReservation(context.Background(), "alice", "Bearer patron", reservationID))
+	assert.False(t, b.called("DELETE /api/v1/reservation/"+reservationUID), "the reservation still holds the copy")
+}
+
+func TestCancelReservation_RepeatsAfterDeleteFails(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, rented(date("2021-10-11")))
+	// an earlier attempt put the copy back but couldn't drop the reservation
+	b.handle("GET /api/v1/copies/{uid}", http.StatusOK, library.CopyResponse{
+		CopyUid: copyID, BookUid: bookID, LibraryUid: libraryID, Status: library.CopyStatusAVAILABLE})
+	b.handle("DELETE /api/v1/reservation/{uid}", http.StatusNoContent, nil)
+
+	s := newTestService(t, url, &publisher{})
+	require.NoError(t, s.CancelReservation(context.Background(), "alice", "Bearer patron", reservationID))
+	assert.False(t, b.called("POST /api/v1/copies/"+copyUID+"/release"), "the copy isn't put back twice")
+	assert.True(t, b.called("DELETE /api/v1/reservation/"+reservationUID))
+}
+
+func TestCancelReservation_Closed(t *testing.T) {
+	b, url := newBackends(t)
+	res := rented(date("2021-10-11"))
+	res.Status = reservation.RETURNED
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, res)
+
+	s := newTestService(t, url, &publisher{})
+	err := s.CancelReservation(context.Background(), "alice", "Bearer patron", reservationID)
+	assert.ErrorIs(t, err, ext.ReservationClosedError)
+	assert.False(t, b.called("POST /api/v1/copies/"+copyUID+"/release"))
+	assert.False(t, b.called("DELETE /api/v1/reservation/"+reservationUID))
+}
+
+func TestReturnBook_EnqueuesWhenLibraryDown(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("PUT /api/v1/reservation/{uid}", http.StatusNoContent, nil)
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, rented(date("2021-10-11")))
+	b.handleFunc("POST /api/v1/copies/{uid}/release", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	pub := &publisher{}
+	s := newTestService(t, url, pub)
+	err := s.ReturnBook(context.Background(), "alice", "Bearer patron",
+		contract.ReturnBookRequest{Condition: contract.ReturnBookRequestConditionGOOD, Date: "2021-10-10"}, reservationID)
+	require.NoError(t, err, "the patron is done, the queue sees the rest through")
+
+	require.Len(t, pub.sent["library-retry"], 1)
+	evt := pub.sent["library-retry"][0]
+	assert.Equal(t, reservationID, evt.ReservationUID)
+	assert.Equal(t, copyID, evt.CopyUID)
+	assert.Equal(t, "GOOD", evt.Condition)
+}
+
+func TestReturnBook_FailsWhenQueueDown(t *testing.T) {
+	b, url := newBackends(t)
+	b.handle("PUT /api/v1/reservation/{uid}", http.StatusNoContent, nil)
+	b.handle("GET /api/v1/reservation/{uid}", http.StatusOK, rented(date("2021-10-11")))
+	b.handleFunc("POST /api/v1/copies/{uid}/release", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	s := newTestService(t, url, &publisher{err: errors.New("not connected to RabbitMQ")})
+	err := s.ReturnBook(context.Background(), "alice", "Bearer patron",
+		contract.ReturnBookRequest{Condition: contract.ReturnBookRequestConditionGOOD, Date: "2021-10-10"}, reservationID)
+	assert.ErrorIs(t, err, ext.ServiceUnavailableError)
+}
+
+func TestRetryHandlers_KeepMessageWhileServiceDown(t *testing.T) {
+	b, url := newBackends(t)
+	b.handleFunc("POST /api/v1/copies/{uid}/release", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	s := newTestService(t, url, &publisher{})
+	stock := s.RetryHandlers()["library-retry"]
+	evt := dto.ReturnRetryEvent{Username: "alice", ReservationUID: reservationID, CopyUID: copyID}
+	assert.ErrorIs(t, stock(context.Background(), evt), ext.ServiceUnavailableError)
+
+	b.handle("POST /api/v1/copies/{uid}/release", http.StatusOK, nil)
+	assert.NoError(t, stock(context.Background(), evt))
+}
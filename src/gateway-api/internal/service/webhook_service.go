@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gateway-api/internal/dto"
+	"gateway-api/internal/models"
+	"gateway-api/internal/repo"
+	"gateway-api/internal/webhooks"
+	"gateway-api/pkg/events"
+	"gateway-api/pkg/ext"
+	"net/http"
+	"platform/egress"
+	"platform/problem"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService struct {
+	Repo       repo.WebhookRepo
+	Dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhookService(r repo.WebhookRepo, dispatcher *webhooks.Dispatcher) *WebhookService {
+	return &WebhookService{Repo: r, Dispatcher: dispatcher}
+}
+
+func (s *WebhookService) Subscribe(ctx context.Context, owner string, req dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	for _, typ := range req.EventTypes {
+		if !slices.Contains(events.Types, events.Type(typ)) {
+			return nil, problem.New(http.StatusBadRequest, problem.BadRequest, fmt.Sprintf("unknown event type %q", typ))
+		}
+	}
+	if err := egress.CheckURL(ctx, req.URL); err != nil {
+		return nil, problem.New(http.StatusBadRequest, problem.BadRequest, err.Error())
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.Repo.CreateSubscription(ctx, models.Subscription{
+		SubscriptionUID: uuid.New(),
+		Owner:           owner,
+		URL:             req.URL,
+		EventTypes:      slices.Compact(slices.Sorted(slices.Values(req.EventTypes))),
+		Secret:          hex.EncodeToString(secret),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := webhookResponse(sub)
+	resp.Secret = sub.Secret
+	return &resp, nil
+}
+
+// ListSubscriptions returns the subscriptions of owner, or every one for
+// an admin.
+func (s *WebhookService) ListSubscriptions(ctx context.Context, owner string, admin bool) ([]dto.WebhookResponse, error) {
+	if admin {
+		owner = ""
+	}
+	subs, err := s.Repo.ListSubscriptions(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.WebhookResponse, 0, len(subs))
+	for i := range subs {
+		resp = append(resp, webhookResponse(&subs[i]))
+	}
+	return resp, nil
+}
+
+// Unsubscribe deletes a subscription along with its deliveries. Only its
+// owner or an admin may, anybody else is told it doesn't exist.
+func (s *WebhookService) Unsubscribe(ctx context.Context, uid string, owner string, admin bool) error {
+	subUID, err := uuid.Parse(uid)
+	if err != nil {
+		return ext.WebhookNotFoundError
+	}
+	sub, err := s.Repo.GetSubscription(ctx, subUID)
+	if errors.Is(err, repo.ErrSubscriptionNotFound) || (err == nil && !admin && sub.Owner != owner) {
+		return ext.WebhookNotFoundError
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.DeleteSubscription(ctx, subUID); errors.Is(err, repo.ErrSubscriptionNotFound) {
+		return ext.WebhookNotFoundError
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, req dto.ListDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error) {
+	filter := models.DeliveryFilter{Status: req.Status, Limit: req.Limit}
+	if req.SubscriptionUID != "" {
+		uid := uuid.MustParse(req.SubscriptionUID)
+		filter.SubscriptionUID = &uid
+	}
+	deliveries, err := s.Repo.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		resp = append(resp, deliveryResponse(&deliveries[i]))
+	}
+	return resp, nil
+}
+
+// Replay sends a delivery again from scratch, with the full retry schedule.
+func (s *WebhookService) Replay(ctx context.Context, uid string) (*dto.WebhookDeliveryResponse, error) {
+	deliveryUID, err := uuid.Parse(uid)
+	if err != nil {
+		return nil, ext.WebhookDeliveryNotFoundError
+	}
+	delivery, err := s.Repo.ResetDelivery(ctx, deliveryUID)
+	if errors.Is(err, repo.ErrDeliveryNotFound) {
+		return nil, ext.WebhookDeliveryNotFoundError
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Dispatcher.Enqueue(ctx, deliveryUID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ext.ServiceUnavailableError, err)
+	}
+	resp := deliveryResponse(delivery)
+	return &resp, nil
+}
+
+func webhookResponse(s *models.Subscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		SubscriptionUID: s.SubscriptionUID.String(),
+		Owner:           s.Owner,
+		URL:             s.URL,
+		EventTypes:      s.EventTypes,
+		CreatedAt:       s.CreatedAt,
+	}
+}
+
+func deliveryResponse(d *models.Delivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		DeliveryUID:     d.DeliveryUID.String(),
+		SubscriptionUID: d.SubscriptionUID.String(),
+		EventID:         d.EventID.String(),
+		EventType:       d.EventType,
+		Status:          d.Status,
+		Attempts:        d.Attempts,
+		ResponseStatus:  d.ResponseStatus,
+		LastError:       d.LastError,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
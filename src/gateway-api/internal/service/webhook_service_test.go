@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gateway-api/internal/dto"
+	"gateway-api/pkg/events"
+	"net/http"
+	"platform/problem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Integrators choose where the gateway posts, so nothing is saved for a URL
+// that leads back inside the deployment.
+func TestSubscribe_RefusesPrivateURLs(t *testing.T) {
+	s := &WebhookService{}
+	for _, url := range []string{
+		"http://93.184.216.34/hook",
+		"https://127.0.0.1/hook",
+		"https://library-system:8060/api/v1/books",
+		"https://169.254.169.254/latest/meta-data",
+	} {
+		_, err := s.Subscribe(context.Background(), "portal@clients", dto.CreateWebhookRequest{
+			URL:        url,
+			EventTypes: []string{string(events.ReservationReturned)},
+		})
+
+		var p *problem.Problem
+		require.True(t, errors.As(err, &p), url)
+		assert.Equal(t, http.StatusBadRequest, p.Status, url)
+	}
+}
@@ -0,0 +1,304 @@
+// Package webhooks pushes domain events to the endpoints external
+// integrators subscribe.
+//
+// The dispatcher takes events off the events exchange and queues one
+// delivery per matching subscription. Each delivery is attempted from the
+// deliveries queue; a failed attempt is parked in the retry queue of the
+// next backoff step, whose TTL dead-letters it back to the deliveries queue.
+// Deliveries are at least once: receivers dedupe by the delivery header.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateway-api/internal/models"
+	"gateway-api/internal/rabbitmq"
+	"gateway-api/internal/repo"
+	"gateway-api/pkg/events"
+	"io"
+	"net/http"
+	"platform/egress"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// EventsQueue is where the gateway receives the events it relays.
+	EventsQueue = "gateway.webhook-events"
+	// DeliveriesQueue carries the uid of each delivery due for an attempt.
+	DeliveriesQueue = "gateway.webhook-deliveries"
+
+	requeueDelay = 5 * time.Second
+)
+
+type Config struct {
+	// DB is the connection string of the webhooks database. Webhooks are
+	// off when it is empty.
+	DB      string        `envconfig:"DB"`
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"10s"`
+	// Backoff is the wait before each retry. A delivery failing once more
+	// than there are steps is given up.
+	Backoff []time.Duration `envconfig:"BACKOFF" default:"10s,1m,5m,30m,2h"`
+}
+
+// Publisher sends a message to a queue, as rabbitmq.Manager does.
+type Publisher interface {
+	Publish(queue string, msg amqp.Publishing) error
+}
+
+type Dispatcher struct {
+	repo    repo.WebhookRepo
+	pub     Publisher
+	client  *http.Client
+	backoff []time.Duration
+	now     func() time.Time
+}
+
+func NewDispatcher(r repo.WebhookRepo, pub Publisher, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		repo:    r,
+		pub:     pub,
+		client:  egress.Client(cfg.Timeout),
+		backoff: cfg.Backoff,
+		now:     time.Now,
+	}
+}
+
+// Attach starts the dispatcher on every connection m makes.
+func (d *Dispatcher) Attach(m *rabbitmq.Manager) {
+	m.Attach(EventsQueue, func(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error) {
+		if err := d.declare(ch); err != nil {
+			return nil, err
+		}
+		return consume(ctx, ch, EventsQueue, d.ingest)
+	})
+	m.Attach(DeliveriesQueue, func(ctx context.Context, ch *amqp.Channel) (<-chan struct{}, error) {
+		if err := d.declare(ch); err != nil {
+			return nil, err
+		}
+		return consume(ctx, ch, DeliveriesQueue, d.attempt)
+	})
+}
+
+func retryQueue(delay time.Duration) string {
+	return "gateway.webhook-retry." + delay.String()
+}
+
+// declare sets up the queues. Retry queues are named after their delay, as
+// RabbitMQ refuses to redeclare a queue with another TTL.
+func (d *Dispatcher) declare(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(events.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	if _, err := ch.QueueDeclare(EventsQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare %s: %w", EventsQueue, err)
+	}
+	for _, typ := range events.Types {
+		key := events.Envelope{Type: typ, Version: 1}.RoutingKey()
+		if err := ch.QueueBind(EventsQueue, key, events.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", key, err)
+		}
+	}
+	if _, err := ch.QueueDeclare(DeliveriesQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare %s: %w", DeliveriesQueue, err)
+	}
+	for _, delay := range d.backoff {
+		_, err := ch.QueueDeclare(retryQueue(delay), true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": DeliveriesQueue,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue for %s: %w", delay, err)
+		}
+	}
+	return nil
+}
+
+// Enqueue schedules an attempt of the delivery right away.
+func (d *Dispatcher) Enqueue(ctx context.Context, uid uuid.UUID) error {
+	return d.publish(ctx, DeliveriesQueue, uid)
+}
+
+func (d *Dispatcher) publish(ctx context.Context, queue string, uid uuid.UUID) error {
+	_, span, headers := tracing.StartPublish(ctx, queue, nil)
+	err := d.pub.Publish(queue, amqp.Publishing{
+		ContentType:   "text/plain",
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     uid.String(),
+		CorrelationId: logging.RequestID(ctx),
+		Headers:       headers,
+		Body:          []byte(uid.String()),
+	})
+	tracing.End(span, err)
+	metrics.Published(queue, err)
+	if err != nil {
+		return fmt.Errorf("failed to queue delivery %s: %w", uid, err)
+	}
+	return nil
+}
+
+// errDrop marks a message that no retry will ever process.
+var errDrop = errors.New("dropping message")
+
+// ingest queues a delivery of the event for every subscription to it.
+func (d *Dispatcher) ingest(ctx context.Context, msg amqp.Delivery) error {
+	var evt events.Envelope
+	if err := json.Unmarshal(msg.Body, &evt); err != nil || evt.ID == uuid.Nil {
+		return fmt.Errorf("%w: undecodable event %s", errDrop, msg.MessageId)
+	}
+	deliveries, err := d.repo.CreateDeliveries(ctx, evt.ID, string(evt.Type), msg.Body)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := d.Enqueue(ctx, delivery.DeliveryUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, msg amqp.Delivery) error {
+	uid, err := uuid.ParseBytes(msg.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a delivery uid", errDrop, msg.Body)
+	}
+	return d.Attempt(ctx, uid)
+}
+
+// Attempt sends a delivery once and records the outcome. A failure is
+// scheduled for retry until the backoff steps run out.
+func (d *Dispatcher) Attempt(ctx context.Context, uid uuid.UUID) error {
+	delivery, err := d.repo.GetDelivery(ctx, uid)
+	if errors.Is(err, repo.ErrDeliveryNotFound) {
+		// the subscription was deleted and its deliveries with it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status == models.DeliveryDelivered || delivery.Status == models.DeliveryFailed {
+		// a copy left over from before a replay or a redelivery
+		return nil
+	}
+	sub, err := d.repo.GetSubscription(ctx, delivery.SubscriptionUID)
+	if errors.Is(err, repo.ErrSubscriptionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	code, sendErr := d.send(ctx, sub, delivery)
+	entry := logging.FromContext(ctx).WithFields(log.Fields{
+		"delivery":     delivery.DeliveryUID,
+		"subscription": sub.SubscriptionUID,
+		"event":        delivery.EventID,
+		"attempt":      delivery.Attempts + 1,
+	})
+	if sendErr == nil {
+		entry.Info("webhook delivered")
+		return d.repo.RecordAttempt(ctx, uid, models.DeliveryDelivered, code, nil)
+	}
+
+	if delivery.Attempts >= len(d.backoff) {
+		entry.WithError(sendErr).Warn("webhook delivery failed, giving up")
+		return d.repo.RecordAttempt(ctx, uid, models.DeliveryFailed, code, sendErr)
+	}
+	delay := d.backoff[delivery.Attempts]
+	entry.WithError(sendErr).Infof("webhook delivery failed, retrying in %s", delay)
+	if err := d.repo.RecordAttempt(ctx, uid, models.DeliveryRetrying, code, sendErr); err != nil {
+		return err
+	}
+	return d.publish(ctx, retryQueue(delay), uid)
+}
+
+// send posts the event and returns the status the receiver answered with,
+// if it answered.
+func (d *Dispatcher) send(ctx context.Context, sub *models.Subscription, delivery *models.Delivery) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	if err := egress.CheckScheme(req.URL); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, d.now(), delivery.Payload))
+	req.Header.Set(DeliveryHeader, delivery.DeliveryUID.String())
+	req.Header.Set(EventHeader, delivery.EventType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	code := resp.StatusCode
+	if code < 200 || code > 299 {
+		return &code, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return &code, nil
+}
+
+// consume runs handle on every message of queue until ctx is cancelled.
+// A message is acked once handled or when it can never be, and requeued
+// after a pause otherwise. handle runs in a span continuing the trace the
+// message was published in.
+func consume(ctx context.Context, ch *amqp.Channel, queue string, handle func(context.Context, amqp.Delivery) error) (<-chan struct{}, error) {
+	tag := "gateway-" + queue
+	msgs, err := ch.Consume(queue, tag, false, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register consumer for %s: %w", queue, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if err := ch.Cancel(tag, false); err != nil {
+				log.WithError(err).Warnf("failed to cancel consumer %s", tag)
+			}
+		}
+	}()
+
+	go func() {
+		defer close(done)
+		for msg := range msgs {
+			msgCtx, span := tracing.StartConsume(ctx, queue, msg)
+			msgCtx = logging.WithRequestID(msgCtx, msg.CorrelationId)
+			err := handle(msgCtx, msg)
+			tracing.End(span, err)
+			switch {
+			case err == nil:
+				_ = msg.Ack(false)
+				metrics.Consumed(queue, metrics.Acked)
+			case errors.Is(err, errDrop):
+				logging.FromContext(msgCtx).WithError(err).Error(queue)
+				_ = msg.Ack(false)
+				metrics.Consumed(queue, metrics.Dropped)
+			default:
+				logging.FromContext(msgCtx).WithError(err).Warnf("%s: failed to handle message, retrying in %s", queue, requeueDelay)
+				select {
+				case <-time.After(requeueDelay):
+				case <-ctx.Done():
+				}
+				_ = msg.Nack(false, true)
+				metrics.Consumed(queue, metrics.Requeued)
+			}
+		}
+	}()
+	return done, nil
+}
@@ -0,0 +1,139 @@
+package webhooks_test
+
+import (
+	"context"
+	"gateway-api/internal/models"
+	"gateway-api/internal/repo"
+	"gateway-api/internal/webhooks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"platform/egress"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo holds one subscription and one delivery.
+type fakeRepo struct {
+	repo.WebhookRepo
+	sub      models.Subscription
+	delivery models.Delivery
+	lastErr  error
+}
+
+func (r *fakeRepo) GetSubscription(_ context.Context, uid uuid.UUID) (*models.Subscription, error) {
+	if uid != r.sub.SubscriptionUID {
+		return nil, repo.ErrSubscriptionNotFound
+	}
+	return &r.sub, nil
+}
+
+func (r *fakeRepo) GetDelivery(_ context.Context, uid uuid.UUID) (*models.Delivery, error) {
+	if uid != r.delivery.DeliveryUID {
+		return nil, repo.ErrDeliveryNotFound
+	}
+	d := r.delivery
+	return &d, nil
+}
+
+func (r *fakeRepo) RecordAttempt(_ context.Context, _ uuid.UUID, status string, code *int, err error) error {
+	r.delivery.Status = status
+	r.delivery.Attempts++
+	r.delivery.ResponseStatus = code
+	r.lastErr = err
+	return nil
+}
+
+type fakePublisher struct {
+	queues []string
+}
+
+func (p *fakePublisher) Publish(queue string, _ amqp.Publishing) error {
+	p.queues = append(p.queues, queue)
+	return nil
+}
+
+func setup(t *testing.T, handler http.HandlerFunc) (*fakeRepo, *fakePublisher, *webhooks.Dispatcher) {
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	r := &fakeRepo{sub: models.Subscription{SubscriptionUID: uuid.New(), URL: srv.URL, Secret: "s3cret"}}
+	r.delivery = models.Delivery{
+		DeliveryUID:     uuid.New(),
+		SubscriptionUID: r.sub.SubscriptionUID,
+		EventType:       "reservation.returned",
+		Payload:         []byte(`{"type":"reservation.returned"}`),
+		Status:          models.DeliveryPending,
+	}
+	pub := &fakePublisher{}
+	d := webhooks.NewDispatcher(r, pub, webhooks.Config{
+		Timeout: time.Second,
+		Backoff: []time.Duration{10 * time.Second, time.Minute},
+	}).WithClient(srv.Client())
+	return r, pub, d
+}
+
+func TestAttempt_SignsAndDelivers(t *testing.T) {
+	var got http.Header
+	var body []byte
+	r, pub, d := setup(t, func(w http.ResponseWriter, req *http.Request) {
+		got = req.Header.Clone()
+		body, _ = io.ReadAll(req.Body)
+	})
+
+	require.NoError(t, d.Attempt(context.Background(), r.delivery.DeliveryUID))
+
+	assert.Equal(t, models.DeliveryDelivered, r.delivery.Status)
+	assert.Empty(t, pub.queues)
+	assert.Equal(t, r.delivery.DeliveryUID.String(), got.Get(webhooks.DeliveryHeader))
+	assert.Equal(t, "reservation.returned", got.Get(webhooks.EventHeader))
+	assert.NoError(t, webhooks.Verify("s3cret", got.Get(webhooks.SignatureHeader), body, time.Minute, time.Now()))
+	assert.ErrorIs(t, webhooks.Verify("other", got.Get(webhooks.SignatureHeader), body, time.Minute, time.Now()), webhooks.ErrBadSignature)
+
+	require.NoError(t, d.Attempt(context.Background(), r.delivery.DeliveryUID))
+	assert.Equal(t, 1, r.delivery.Attempts, "a delivered webhook is not sent again")
+}
+
+func TestAttempt_RetriesThenGivesUp(t *testing.T) {
+	r, pub, d := setup(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	for range 3 {
+		require.NoError(t, d.Attempt(context.Background(), r.delivery.DeliveryUID))
+	}
+
+	assert.Equal(t, []string{"gateway.webhook-retry.10s", "gateway.webhook-retry.1m0s"}, pub.queues)
+	assert.Equal(t, models.DeliveryFailed, r.delivery.Status)
+	assert.Equal(t, 3, r.delivery.Attempts)
+	require.NotNil(t, r.delivery.ResponseStatus)
+	assert.Equal(t, http.StatusServiceUnavailable, *r.delivery.ResponseStatus)
+}
+
+func TestVerify_RejectsStaleSignature(t *testing.T) {
+	body := []byte(`{}`)
+	sig := webhooks.Sign("s3cret", time.Now().Add(-time.Hour), body)
+	assert.ErrorIs(t, webhooks.Verify("s3cret", sig, body, 5*time.Minute, time.Now()), webhooks.ErrBadSignature)
+}
+
+func TestAttempt_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	r, pub, _ := setup(t, func(w http.ResponseWriter, req *http.Request) {
+		called = true
+	})
+	d := webhooks.NewDispatcher(r, pub, webhooks.Config{
+		Timeout: time.Second,
+		Backoff: []time.Duration{10 * time.Second},
+	})
+
+	require.NoError(t, d.Attempt(context.Background(), r.delivery.DeliveryUID))
+
+	assert.False(t, called)
+	assert.Equal(t, models.DeliveryRetrying, r.delivery.Status)
+	assert.ErrorIs(t, r.lastErr, egress.ErrForbiddenURL)
+}
@@ -0,0 +1,9 @@
+package webhooks
+
+import "net/http"
+
+// WithClient sends over client, which may reach test servers on loopback.
+func (d *Dispatcher) WithClient(client *http.Client) *Dispatcher {
+	d.client = client
+	return d
+}
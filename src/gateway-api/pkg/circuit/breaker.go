@@ -13,6 +13,19 @@ const (
 	HalfOpen
 )
 
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
 type Breaker struct {
 	mu                sync.Mutex
 	state             State
@@ -37,6 +50,18 @@ func NewBreaker(threshold int, retryAfter, window time.Duration, halfOpenLimit i
 	}
 }
 
+// State is the breaker's current state. An open breaker whose retry delay
+// has passed is reported half-open, as the next call will probe.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && time.Since(b.openTime) >= b.retryAfter {
+		return HalfOpen
+	}
+	return b.state
+}
+
 func (b *Breaker) clearOldFailures() {
 	now := time.Now()
 	validFailures := make([]time.Time, 0)
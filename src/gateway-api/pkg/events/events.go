@@ -0,0 +1,35 @@
+// Package events describes the domain events the gateway relays to webhook
+// subscribers. Services publish them to the Exchange topic exchange with
+// the routing key <type>.v<version>; the gateway passes the envelope on
+// unchanged, so subscribers see the same schema as internal consumers.
+package events
+
+import "platform/events"
+
+const Exchange = events.Exchange
+
+type (
+	Type     = events.Type
+	Envelope = events.Envelope
+)
+
+const (
+	ReservationCreated   Type = "reservation.created"
+	ReservationReturned  Type = "reservation.returned"
+	ReservationExpired   Type = "reservation.expired"
+	ReservationCancelled Type = "reservation.cancelled"
+	ReservationDueSoon   Type = "reservation.due_soon"
+	ReservationOverdue   Type = "reservation.overdue"
+	TransferReceived     Type = "transfer.received"
+)
+
+// Types lists the events a webhook can subscribe to.
+var Types = []Type{
+	ReservationCreated,
+	ReservationReturned,
+	ReservationExpired,
+	ReservationCancelled,
+	ReservationDueSoon,
+	ReservationOverdue,
+	TransferReceived,
+}
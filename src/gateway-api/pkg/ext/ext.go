@@ -3,9 +3,20 @@ package ext
 import "errors"
 
 var (
-	ServiceUnavailableError            = errors.New("service unavailable")
-	RatingServiceUnavailableError      = errors.New("Bonus Service unavailable")
-	LibraryServiceUnavailableError     = errors.New("Library Service unavailable")
-	ReservationServiceUnavailableError = errors.New("Reservation Service unavailable")
-	BookNotAvailableError              = errors.New("Book not available")
+	ServiceUnavailableError             = errors.New("service unavailable")
+	RatingServiceUnavailableError       = errors.New("Bonus Service unavailable")
+	LibraryServiceUnavailableError      = errors.New("Library Service unavailable")
+	ReservationServiceUnavailableError  = errors.New("Reservation Service unavailable")
+	NotificationServiceUnavailableError = errors.New("Notification Service unavailable")
+	BookNotAvailableError               = errors.New("Book not available")
+	ReservationLimitError               = errors.New("You rented maximum amount of books")
+	ReservationNotFoundError            = errors.New("Reservation not found")
+	BookNotFoundError                   = errors.New("Book not found")
+	LibraryNotFoundError                = errors.New("Library not found")
+	RatingNotFoundError                 = errors.New("Rating not found")
+	ForbiddenError                      = errors.New("Reservation belongs to another user")
+	ReservationClosedError              = errors.New("Reservation is already closed")
+	NotificationNotFoundError           = errors.New("Notification not found")
+	WebhookNotFoundError                = errors.New("Webhook subscription not found")
+	WebhookDeliveryNotFoundError        = errors.New("Webhook delivery not found")
 )
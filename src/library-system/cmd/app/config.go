@@ -2,10 +2,21 @@ package main
 
 import (
 	"lab2-rsoi/library-system/internal/server"
-	"lab2-rsoi/library-system/pkg/postgres"
+	"platform/auth"
+	"platform/logging"
+	"platform/outbox"
+	"platform/postgres"
+	"platform/tracing"
 )
 
 type Config struct {
 	Server server.Server   `envconfig:"SERVER"`
 	DB     postgres.Config `envconfig:"DB"`
+	Auth   auth.Config     `envconfig:"AUTH"`
+	// RabbitMQ is where domain events are published. Without it they stay
+	// in the outbox.
+	RabbitMQ string         `envconfig:"RABBITMQ"`
+	Outbox   outbox.Config  `envconfig:"OUTBOX"`
+	Tracing  tracing.Config `envconfig:"TRACING"`
+	Log      logging.Config `envconfig:"LOG"`
 }
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"contract/library"
+	"errors"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"net/http"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime"
+)
+
+type CatalogHandler struct {
+	service service.CatalogServiceIface
+}
+
+func NewCatalog(service service.CatalogServiceIface) *CatalogHandler {
+	return &CatalogHandler{service: service}
+}
+
+func (h *CatalogHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	adminRoutes := rg.Group("/admin")
+	{
+		adminRoutes.POST("/libraries", h.CreateLibrary)
+		adminRoutes.PUT("/libraries/:uid", h.UpdateLibrary)
+		adminRoutes.DELETE("/libraries/:uid", h.DeleteLibrary)
+		adminRoutes.POST("/books", h.CreateBook)
+		adminRoutes.PUT("/books/:uid", h.UpdateBook)
+		adminRoutes.DELETE("/books/:uid", h.DeleteBook)
+		adminRoutes.POST("/libraries/:uid/books/:bookUid/copies", h.AddCopies)
+		adminRoutes.POST("/libraries/:uid/books/:bookUid/copies/retire", h.RetireCopies)
+		adminRoutes.POST("/copies/:uid/retire", h.RetireCopy)
+	}
+}
+
+func writeCatalogError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.EmptyFieldError):
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+	case errors.Is(err, repo.ErrLibraryNotFound),
+		errors.Is(err, repo.ErrBookNotFound),
+		errors.Is(err, repo.ErrHoldingNotFound),
+		errors.Is(err, repo.ErrCopyNotFound):
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+	case errors.Is(err, repo.ErrAlreadyExists),
+		errors.Is(err, repo.ErrInUse),
+		errors.Is(err, repo.ErrCopyStateConflict):
+		problem.Write(c, http.StatusConflict, problem.Conflict, err.Error())
+	case errors.Is(err, repo.ErrNotEnoughCopies):
+		problem.Write(c, http.StatusConflict, problem.BookNotAvailable, err.Error())
+	default:
+		problem.WriteInternal(c, err)
+	}
+}
+
+func bindUID(c *gin.Context, param string) (uuid.UUID, bool) {
+	uid, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid "+param)
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+// bindQuery reads the query parameter name into dest the way the contract
+// encodes it, and answers 400 when it can't.
+func bindQuery(c *gin.Context, name string, dest any) bool {
+	if err := runtime.BindQueryParameter("form", true, false, name, c.Request.URL.Query(), dest); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid "+name)
+		return false
+	}
+	return true
+}
+
+func (h *CatalogHandler) CreateLibrary(c *gin.Context) {
+	var req library.LibraryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateLibrary(c, req)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *CatalogHandler) UpdateLibrary(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req library.LibraryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.UpdateLibrary(c, libraryUID, req)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CatalogHandler) DeleteLibrary(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteLibrary(c, libraryUID); err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CatalogHandler) CreateBook(c *gin.Context) {
+	var req library.BookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateBook(c, req)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *CatalogHandler) UpdateBook(c *gin.Context) {
+	bookUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req library.BookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.UpdateBook(c, bookUID, req)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CatalogHandler) DeleteBook(c *gin.Context) {
+	bookUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteBook(c, bookUID); err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CatalogHandler) AddCopies(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+	bookUID, ok := bindUID(c, "bookUid")
+	if !ok {
+		return
+	}
+
+	var req library.CopiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.AddCopies(c, libraryUID, bookUID, req.Count)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CatalogHandler) RetireCopies(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+	bookUID, ok := bindUID(c, "bookUid")
+	if !ok {
+		return
+	}
+
+	var req library.CopiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.RetireCopies(c, libraryUID, bookUID, req.Count)
+	if err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CatalogHandler) RetireCopy(c *gin.Context) {
+	copyUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RetireCopy(c, copyUID); err != nil {
+		writeCatalogError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
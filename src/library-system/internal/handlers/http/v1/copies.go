@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"contract/library"
+	"errors"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"net/http"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CopyHandler struct {
+	service service.CopyServiceIface
+}
+
+func NewCopy(service service.CopyServiceIface) *CopyHandler {
+	return &CopyHandler{service: service}
+}
+
+func (h *CopyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/library/:libraryUid/books/:bookUid/copies", h.ListCopies)
+	rg.POST("/library/:libraryUid/books/:bookUid/copies/reserve", staffOnly, h.ReserveCopy)
+	rg.GET("/copies/:uid", h.GetCopy)
+	rg.POST("/copies/:uid/release", staffOnly, h.ReleaseCopy)
+	rg.PUT("/copies/:uid/condition", staffOnly, h.SetCondition)
+}
+
+func writeCopyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repo.ErrCopyNotFound):
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+	case errors.Is(err, repo.ErrNotEnoughCopies):
+		problem.Write(c, http.StatusConflict, problem.BookNotAvailable, err.Error())
+	case errors.Is(err, repo.ErrCopyStateConflict):
+		problem.Write(c, http.StatusConflict, problem.Conflict, err.Error())
+	default:
+		problem.WriteInternal(c, err)
+	}
+}
+
+func (h *CopyHandler) ListCopies(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "libraryUid")
+	if !ok {
+		return
+	}
+	bookUID, ok := bindUID(c, "bookUid")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListCopies(c, libraryUID, bookUID)
+	if err != nil {
+		writeCopyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CopyHandler) ReserveCopy(c *gin.Context) {
+	libraryUID, ok := bindUID(c, "libraryUid")
+	if !ok {
+		return
+	}
+	bookUID, ok := bindUID(c, "bookUid")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ReserveCopy(c, libraryUID, bookUID)
+	if err != nil {
+		writeCopyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CopyHandler) GetCopy(c *gin.Context) {
+	copyUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetCopy(c, copyUID)
+	if err != nil {
+		writeCopyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CopyHandler) ReleaseCopy(c *gin.Context) {
+	copyUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req library.ReleaseCopyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+			return
+		}
+	}
+
+	resp, err := h.service.ReleaseCopy(c, copyUID, req.Condition)
+	if err != nil {
+		writeCopyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CopyHandler) SetCondition(c *gin.Context) {
+	copyUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	var req library.ConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+	if !validCondition(req.Condition) {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid condition")
+		return
+	}
+
+	resp, err := h.service.SetCondition(c, copyUID, req.Condition)
+	if err != nil {
+		writeCopyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// validCondition tells whether condition is one of the contract's.
+func validCondition(condition library.Condition) bool {
+	switch condition {
+	case library.EXCELLENT, library.GOOD, library.BAD:
+		return true
+	}
+	return false
+}
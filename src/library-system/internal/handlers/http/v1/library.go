@@ -1,24 +1,25 @@
 package handlers
 
 import (
+	"contract/library"
 	"errors"
-	"fmt"
-	"lab2-rsoi/library-system/internal/dto"
 	"lab2-rsoi/library-system/internal/service"
 	"net/http"
+	"platform/auth"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// staffOnly guards the routes that change stock: only librarians and
+// services acting for a patron may call them.
+var staffOnly = auth.Require(auth.Librarian, auth.Service)
+
 type LibraryHandler struct {
 	service service.LibraryServiceIface
 }
 
-type GetBookLibRequest struct {
-	UID string `uri:"uid" binding:"required"`
-}
-
 func New(service service.LibraryServiceIface) *LibraryHandler {
 	return &LibraryHandler{service: service}
 }
@@ -30,27 +31,23 @@ func (h *LibraryHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		libraryRoutes.GET("/:uid/", h.GetLibraryByUid)
 	}
 	rg.GET("/books/:uid/", h.GetBookInfoByUid)
-	rg.PUT("/books/:uid/condition", h.UpdateBookCondition)
-	rg.PUT("/library/:libraryUid/books/:bookUid/count/:delta/", h.UpdateBookCount)
+	rg.PUT("/library/:libraryUid/books/:bookUid/count/:delta/", staffOnly, h.UpdateBookCount)
 }
 
 func (h *LibraryHandler) GetLibraries(c *gin.Context) {
-	var req dto.GetLibrariesRequest
+	var req library.ListLibrariesParams
 
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
-	fmt.Println(req)
-
 	if req.City == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "city is required"})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "city is required")
 		return
 	}
 
 	page := req.Page
 	size := req.Size
-	fmt.Println(req.Page, req.Size)
 	if page == 0 {
 		page = 0
 	}
@@ -60,7 +57,7 @@ func (h *LibraryHandler) GetLibraries(c *gin.Context) {
 
 	resp, err := h.service.ListLibraries(c, req.City, page, size)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.WriteInternal(c, err)
 		return
 	}
 
@@ -68,21 +65,14 @@ func (h *LibraryHandler) GetLibraries(c *gin.Context) {
 }
 
 func (h *LibraryHandler) GetBooks(c *gin.Context) {
-	var req dto.GetBooksRequest
-
-	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
 		return
 	}
 
+	var req library.ListBooksParams
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	libraryUID, err := uuid.Parse(req.LibraryUID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid libraryUid"})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
@@ -97,7 +87,7 @@ func (h *LibraryHandler) GetBooks(c *gin.Context) {
 
 	resp, err := h.service.ListBooks(c, libraryUID, req.ShowAll, page, size)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.WriteInternal(c, err)
 		return
 	}
 
@@ -105,23 +95,14 @@ func (h *LibraryHandler) GetBooks(c *gin.Context) {
 }
 
 func (h *LibraryHandler) GetLibraryByUid(c *gin.Context) {
-	var req GetBookLibRequest
-
-	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	libraryUID, err := uuid.Parse(req.UID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid libraryUid"})
+	libraryUID, ok := bindUID(c, "uid")
+	if !ok {
 		return
 	}
 
 	resp, err := h.service.GetLibraryByUID(c, libraryUID)
 	if err != nil {
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeCatalogError(c, err)
 		return
 	}
 
@@ -129,59 +110,29 @@ func (h *LibraryHandler) GetLibraryByUid(c *gin.Context) {
 }
 
 func (h *LibraryHandler) GetBookInfoByUid(c *gin.Context) {
-	var req GetBookLibRequest
-
-	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	bookUID, ok := bindUID(c, "uid")
+	if !ok {
 		return
 	}
 
-	bookUID, err := uuid.Parse(req.UID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid libraryUid"})
+	var params library.GetBookParams
+	if !bindQuery(c, "libraryUid", &params.LibraryUid) {
 		return
 	}
 
-	resp, err := h.service.GetBookByUID(c, bookUID)
-	if err != nil {
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	// availableCount is of the library asked about, of every library if none is
+	var libraryUID *uuid.UUID
+	if params.LibraryUid != uuid.Nil {
+		libraryUID = &params.LibraryUid
 	}
 
-	c.JSON(http.StatusOK, resp)
-}
-
-func (h *LibraryHandler) UpdateBookCondition(c *gin.Context) {
-	var req struct {
-		Condition string `json:"condition" binding:"required"`
-	}
-	var uriReq struct {
-		UID string `uri:"uid" binding:"required"`
-	}
-
-	if err := c.ShouldBindUri(&uriReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	bookUID, err := uuid.Parse(uriReq.UID)
+	resp, err := h.service.GetBookByUID(c, bookUID, libraryUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bookUid"})
+		writeCatalogError(c, err)
 		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if err := h.service.UpdateBookCondition(c, bookUID, req.Condition); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "condition updated"})
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *LibraryHandler) UpdateBookCount(c *gin.Context) {
@@ -192,28 +143,38 @@ func (h *LibraryHandler) UpdateBookCount(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindUri(&uriReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
 	bookUID, err := uuid.Parse(uriReq.BookUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bookUid"})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid bookUid")
 		return
 	}
 	libraryUID, err := uuid.Parse(uriReq.LibraryUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bookUid"})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid bookUid")
+		return
+	}
+
+	var params library.ChangeBookCountParams
+	if !bindQuery(c, "condition", &params.Condition) {
+		return
+	}
+	if params.Condition != "" && !validCondition(params.Condition) {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid condition")
 		return
 	}
 
-	if err := h.service.UpdateBookCount(c, bookUID, libraryUID, uriReq.Delta); err != nil {
+	if err := h.service.UpdateBookCount(c, bookUID, libraryUID, uriReq.Delta, params.Condition); err != nil {
 		if errors.Is(err, service.CountOfBooksIsZero) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": service.CountOfBooksIsZero})
+			problem.Write(c, http.StatusConflict, problem.BookNotAvailable, err.Error())
+			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeCatalogError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "count updated"})
+	c.JSON(http.StatusOK, library.Message{Message: "count updated"})
 }
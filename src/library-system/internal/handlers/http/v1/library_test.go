@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"contract/contracttest"
+	"contract/library"
+	"errors"
+	"fmt"
+	handlers "lab2-rsoi/library-system/internal/handlers/http/v1"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"platform/problem"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingService answers every lookup by UID with err.
+type failingService struct {
+	service.LibraryServiceIface
+	err error
+}
+
+func (s failingService) GetBookByUID(context.Context, uuid.UUID, *uuid.UUID) (*library.BookResponse, error) {
+	return nil, s.err
+}
+
+func (s failingService) GetLibraryByUID(context.Context, uuid.UUID) (*library.LibraryResponse, error) {
+	return nil, s.err
+}
+
+func TestLookupErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"missing book", "/books/%s/", repo.ErrBookNotFound, http.StatusNotFound},
+		{"missing library", "/libraries/%s/", repo.ErrLibraryNotFound, http.StatusNotFound},
+		{"wrapped", "/books/%s/", fmt.Errorf("lookup: %w", repo.ErrBookNotFound), http.StatusNotFound},
+		{"bad library", "/books/%s/?libraryUid=nope", nil, http.StatusBadRequest},
+		{"book lookup failed", "/books/%s/", errors.New("connection refused"), http.StatusInternalServerError},
+		{"library lookup failed", "/libraries/%s/", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			handlers.New(failingService{err: tt.err}).RegisterRoutes(r.Group(""))
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(tt.path, uuid.New()), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+// catalogService answers listings with one library holding one book whose
+// author and genre are unknown.
+type catalogService struct {
+	service.LibraryServiceIface
+}
+
+func (catalogService) ListLibraries(context.Context, string, int, int) (*library.LibraryPaginationResponse, error) {
+	return &library.LibraryPaginationResponse{Page: 1, PageSize: 1, TotalElements: 1, Items: []library.LibraryResponse{
+		{Id: 1, LibraryUid: uuid.New(), Name: "Library", City: "Moscow", Address: "Street 1"},
+	}}, nil
+}
+
+func (catalogService) ListBooks(context.Context, uuid.UUID, bool, int, int) (*library.BookPaginationResponse, error) {
+	return &library.BookPaginationResponse{Page: 1, PageSize: 1, TotalElements: 1, Items: []library.BookResponse{
+		{Id: 1, BookUid: uuid.New(), Name: "Book", Condition: library.EXCELLENT, AvailableCount: 1},
+	}}, nil
+}
+
+// The gateway passes the listings on as they are, so they must keep to the
+// shapes of the contract.
+func TestListings_Contract(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handlers.New(catalogService{}).RegisterRoutes(r.Group(""))
+
+	tests := []struct {
+		path   string
+		schema string
+	}{
+		{"/libraries?city=Moscow", "LibraryPaginationResponse"},
+		{fmt.Sprintf("/libraries/%s/books/", uuid.New()), "LibraryBookPaginationResponse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			contracttest.Schema(t, tt.schema, w.Body.Bytes())
+		})
+	}
+}
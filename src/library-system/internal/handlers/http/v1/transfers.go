@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"contract/library"
+	"errors"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"net/http"
+	"platform/auth"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TransferHandler struct {
+	service service.TransferServiceIface
+}
+
+func NewTransfer(service service.TransferServiceIface) *TransferHandler {
+	return &TransferHandler{service: service}
+}
+
+func (h *TransferHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	transferRoutes := rg.Group("/transfers", staffOnly)
+	{
+		transferRoutes.POST("", h.RequestTransfer)
+		transferRoutes.GET("", h.ListTransfers)
+		transferRoutes.GET("/:uid", h.GetTransfer)
+		transferRoutes.POST("/:uid/ship", h.ShipTransfer)
+		transferRoutes.POST("/:uid/receive", h.ReceiveTransfer)
+		transferRoutes.POST("/:uid/cancel", h.CancelTransfer)
+	}
+}
+
+func writeTransferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.SameLibraryError):
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+	case errors.Is(err, repo.ErrTransferNotFound),
+		errors.Is(err, repo.ErrLibraryNotFound),
+		errors.Is(err, repo.ErrBookNotFound),
+		errors.Is(err, repo.ErrHoldingNotFound):
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+	case errors.Is(err, repo.ErrNotEnoughCopies):
+		problem.Write(c, http.StatusConflict, problem.BookNotAvailable, err.Error())
+	case errors.Is(err, repo.ErrTransferStateConflict):
+		problem.Write(c, http.StatusConflict, problem.Conflict, err.Error())
+	default:
+		problem.WriteInternal(c, err)
+	}
+}
+
+func (h *TransferHandler) RequestTransfer(c *gin.Context) {
+	var req library.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.RequestTransfer(c, req, auth.Subject(c))
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *TransferHandler) ListTransfers(c *gin.Context) {
+	var params library.ListTransfersParams
+	if !bindQuery(c, "libraryUid", &params.LibraryUid) ||
+		!bindQuery(c, "copyUid", &params.CopyUid) ||
+		!bindQuery(c, "status", &params.Status) {
+		return
+	}
+
+	filter := repo.TransferFilter{Status: string(params.Status)}
+	if params.LibraryUid != uuid.Nil {
+		filter.LibraryUID = &params.LibraryUid
+	}
+	if params.CopyUid != uuid.Nil {
+		filter.CopyUID = &params.CopyUid
+	}
+
+	resp, err := h.service.ListTransfers(c, filter)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	transferUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetTransfer(c, transferUID)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TransferHandler) ShipTransfer(c *gin.Context) {
+	h.move(c, h.service.ShipTransfer)
+}
+
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	h.move(c, h.service.ReceiveTransfer)
+}
+
+func (h *TransferHandler) CancelTransfer(c *gin.Context) {
+	h.move(c, h.service.CancelTransfer)
+}
+
+func (h *TransferHandler) move(c *gin.Context, step func(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error)) {
+	transferUID, ok := bindUID(c, "uid")
+	if !ok {
+		return
+	}
+
+	resp, err := step(c, transferUID)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,3 @@
+DELETE FROM library_books WHERE book_id = 1 AND library_id = 1;
+DELETE FROM books WHERE book_uid = 'f7cdc58f-2caf-4b15-9727-f89dcc629b27';
+DELETE FROM library WHERE library_uid = '83575e12-7ce0-48ee-9931-51919ff3c9ee';
@@ -0,0 +1,2 @@
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_name_author_key;
+ALTER TABLE library DROP CONSTRAINT IF EXISTS library_city_name_key;
@@ -0,0 +1,3 @@
+ALTER TABLE library ADD CONSTRAINT library_city_name_key UNIQUE (city, name);
+-- a book without an author is still one book: two NULL authors collide
+ALTER TABLE books ADD CONSTRAINT books_name_author_key UNIQUE NULLS NOT DISTINCT (name, author);
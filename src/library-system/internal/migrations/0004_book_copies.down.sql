@@ -0,0 +1,14 @@
+ALTER TABLE library_books
+    DROP CONSTRAINT IF EXISTS library_books_book_id_library_id_key,
+    ADD COLUMN IF NOT EXISTS available_count INT NOT NULL DEFAULT 0;
+
+UPDATE library_books lb
+SET available_count = (SELECT COUNT(*)
+                       FROM book_copies c
+                       WHERE c.book_id = lb.book_id
+                         AND c.library_id = lb.library_id
+                         AND c.status = 'AVAILABLE');
+
+ALTER TABLE library_books ALTER COLUMN available_count DROP DEFAULT;
+
+DROP TABLE IF EXISTS book_copies;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS book_copies
+(
+    id         SERIAL PRIMARY KEY,
+    copy_uid   UUID UNIQUE NOT NULL,
+    barcode    VARCHAR(40) UNIQUE NOT NULL,
+    book_id    INT NOT NULL REFERENCES books(id),
+    library_id INT NOT NULL REFERENCES library(id),
+    condition  VARCHAR(20) NOT NULL DEFAULT 'EXCELLENT'
+    CHECK (condition IN ('EXCELLENT', 'GOOD', 'BAD')),
+    status     VARCHAR(20) NOT NULL DEFAULT 'AVAILABLE'
+    CHECK (status IN ('AVAILABLE', 'RESERVED', 'IN_TRANSIT', 'RETIRED'))
+    );
+
+CREATE INDEX IF NOT EXISTS book_copies_holding_idx
+    ON book_copies (book_id, library_id, status);
+
+-- every counted book becomes a copy of its own, barcoded the way AddCopies
+-- does it
+INSERT INTO book_copies (copy_uid, barcode, book_id, library_id, condition)
+SELECT g.uid, 'BC-' || upper(substr(replace(g.uid::text, '-', ''), 1, 12)), lb.book_id, lb.library_id,
+       COALESCE(b.condition, 'EXCELLENT')
+FROM library_books lb
+         JOIN books b ON b.id = lb.book_id
+         CROSS JOIN LATERAL (SELECT gen_random_uuid() AS uid FROM generate_series(1, lb.available_count)) g;
+
+-- a holding may have been listed twice; its copies are all in now
+DELETE FROM library_books a
+    USING library_books b
+WHERE a.book_id = b.book_id
+  AND a.library_id = b.library_id
+  AND a.ctid > b.ctid;
+
+ALTER TABLE library_books
+    DROP COLUMN available_count,
+    ADD CONSTRAINT library_books_book_id_library_id_key UNIQUE (book_id, library_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS transfers
+(
+    id              SERIAL PRIMARY KEY,
+    transfer_uid    UUID UNIQUE NOT NULL,
+    copy_id         INT NOT NULL REFERENCES book_copies(id),
+    from_library_id INT NOT NULL REFERENCES library(id),
+    to_library_id   INT NOT NULL REFERENCES library(id),
+    hold            BOOLEAN NOT NULL DEFAULT FALSE,
+    status          VARCHAR(20) NOT NULL
+    CHECK (status IN ('REQUESTED', 'IN_TRANSIT', 'RECEIVED', 'CANCELLED')),
+    requested_by    VARCHAR(80) NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK (from_library_id <> to_library_id)
+    );
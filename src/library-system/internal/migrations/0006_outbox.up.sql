@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id             BIGSERIAL PRIMARY KEY,
+    event_id       UUID UNIQUE NOT NULL,
+    routing_key    VARCHAR(120) NOT NULL,
+    ordering_key   VARCHAR(80) NOT NULL,
+    sequence       BIGINT NOT NULL,
+    correlation_id VARCHAR(120),
+    payload        JSONB NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT now(),
+    published_at   TIMESTAMP,
+    UNIQUE (ordering_key, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS event_seq BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- The trace context of the request that made the change, so the relay can
+-- publish the event within the same trace.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS trace_context JSONB;
@@ -0,0 +1,15 @@
+package migrations_test
+
+import (
+	"lab2-rsoi/library-system/internal/migrations"
+	"platform/migrate"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+}
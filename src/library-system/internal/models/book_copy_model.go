@@ -0,0 +1,20 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	CopyAvailable = "AVAILABLE"
+	CopyReserved  = "RESERVED"
+	CopyInTransit = "IN_TRANSIT"
+	CopyRetired   = "RETIRED"
+)
+
+type BookCopy struct {
+	ID         uint64    `db:"id"`
+	CopyUID    uuid.UUID `db:"copy_uid"`
+	Barcode    string    `db:"barcode"`
+	BookUID    uuid.UUID `db:"book_uid"`
+	LibraryUID uuid.UUID `db:"library_uid"`
+	Condition  string    `db:"condition"  validate:"oneof=EXCELLENT GOOD BAD"`
+	Status     string    `db:"status"  validate:"oneof=AVAILABLE RESERVED IN_TRANSIT RETIRED"`
+}
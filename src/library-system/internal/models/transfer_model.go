@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TransferRequested = "REQUESTED"
+	TransferInTransit = "IN_TRANSIT"
+	TransferReceived  = "RECEIVED"
+	TransferCancelled = "CANCELLED"
+)
+
+type Transfer struct {
+	ID             uint64    `db:"id"`
+	TransferUID    uuid.UUID `db:"transfer_uid"`
+	CopyUID        uuid.UUID `db:"copy_uid"`
+	BookUID        uuid.UUID `db:"book_uid"`
+	FromLibraryUID uuid.UUID `db:"from_library_uid"`
+	ToLibraryUID   uuid.UUID `db:"to_library_uid"`
+	Hold           bool      `db:"hold"`
+	Status         string    `db:"status"  validate:"oneof=REQUESTED IN_TRANSIT RECEIVED CANCELLED"`
+	RequestedBy    string    `db:"requested_by"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
@@ -0,0 +1,345 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lab2-rsoi/library-system/internal/models"
+	"platform/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+var (
+	ErrLibraryNotFound = errors.New("library not found")
+	ErrBookNotFound    = errors.New("book not found")
+	ErrHoldingNotFound = errors.New("library does not hold this book")
+	ErrAlreadyExists   = errors.New("record already exists")
+	ErrInUse           = errors.New("record is still referenced")
+	ErrNotEnoughCopies = errors.New("not enough available copies")
+)
+
+type CatalogRepository interface {
+	CreateLibrary(ctx context.Context, lib models.Library) (*models.Library, error)
+	UpdateLibrary(ctx context.Context, lib models.Library) (*models.Library, error)
+	DeleteLibrary(ctx context.Context, uid uuid.UUID) error
+	CreateBook(ctx context.Context, book models.Book) (*models.Book, error)
+	UpdateBook(ctx context.Context, book models.Book) (*models.Book, error)
+	DeleteBook(ctx context.Context, uid uuid.UUID) error
+	AddCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int, condition string) (int, error)
+	RetireCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (int, error)
+	RetireCopy(ctx context.Context, copyUID uuid.UUID) error
+}
+
+type catalogRepo struct {
+	conn postgres.Connection
+}
+
+func NewCatalogRepo(client postgres.Client) CatalogRepository {
+	return &catalogRepo{conn: client.Conn()}
+}
+
+// mapWriteError translates constraint violations into catalog errors so
+// handlers can answer with 409 instead of 500.
+func mapWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return fmt.Errorf("%w: %s", ErrAlreadyExists, pgErr.ConstraintName)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %s", ErrInUse, pgErr.ConstraintName)
+		}
+	}
+	return err
+}
+
+func (r *catalogRepo) CreateLibrary(ctx context.Context, lib models.Library) (*models.Library, error) {
+	query := qb.Insert("library").
+		Columns("library_uid", "name", "city", "address").
+		Values(lib.LibraryUID, lib.Name, lib.City, lib.Address).
+		Suffix("RETURNING id, library_uid, name, city, address")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	created, err := pgx.CollectOneRow[models.Library](rows, pgx.RowToStructByName)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	return &created, nil
+}
+
+func (r *catalogRepo) UpdateLibrary(ctx context.Context, lib models.Library) (*models.Library, error) {
+	query := qb.Update("library").
+		Set("name", lib.Name).
+		Set("city", lib.City).
+		Set("address", lib.Address).
+		Where("library_uid = ?", lib.LibraryUID).
+		Suffix("RETURNING id, library_uid, name, city, address")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	updated, err := pgx.CollectOneRow[models.Library](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLibraryNotFound
+	}
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	return &updated, nil
+}
+
+func (r *catalogRepo) DeleteLibrary(ctx context.Context, uid uuid.UUID) error {
+	sql, args, err := qb.Delete("library").Where("library_uid = ?", uid).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return mapWriteError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLibraryNotFound
+	}
+	return nil
+}
+
+func (r *catalogRepo) CreateBook(ctx context.Context, book models.Book) (*models.Book, error) {
+	query := qb.Insert("books").
+		Columns("book_uid", "name", "author", "genre", "condition").
+		Values(book.BookUID, book.Name, book.Author, book.Genre, book.Condition).
+		Suffix("RETURNING id, book_uid, name, author, genre, condition")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	created, err := pgx.CollectOneRow[models.Book](rows, pgx.RowToStructByName)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	return &created, nil
+}
+
+func (r *catalogRepo) UpdateBook(ctx context.Context, book models.Book) (*models.Book, error) {
+	query := qb.Update("books").
+		Set("name", book.Name).
+		Set("author", book.Author).
+		Set("genre", book.Genre).
+		Set("condition", book.Condition).
+		Where("book_uid = ?", book.BookUID).
+		Suffix("RETURNING id, book_uid, name, author, genre, condition")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	updated, err := pgx.CollectOneRow[models.Book](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBookNotFound
+	}
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+	return &updated, nil
+}
+
+func (r *catalogRepo) DeleteBook(ctx context.Context, uid uuid.UUID) error {
+	sql, args, err := qb.Delete("books").Where("book_uid = ?", uid).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return mapWriteError(err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrBookNotFound
+	}
+	return nil
+}
+
+// holdingIDs resolves the internal ids of a library and a book, reporting
+// which of the two is missing.
+func holdingIDs(ctx context.Context, tx pgx.Tx, libraryUID, bookUID uuid.UUID) (libraryID, bookID uint64, err error) {
+	err = tx.QueryRow(ctx, `SELECT id FROM library WHERE library_uid = $1`, libraryUID).Scan(&libraryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, ErrLibraryNotFound
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = tx.QueryRow(ctx, `SELECT id FROM books WHERE book_uid = $1`, bookUID).Scan(&bookID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, ErrBookNotFound
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return libraryID, bookID, nil
+}
+
+func countAvailable(ctx context.Context, tx pgx.Tx, libraryID, bookID uint64) (int, error) {
+	var total int
+	err := tx.QueryRow(ctx, `
+        SELECT COUNT(*) FROM book_copies
+        WHERE book_id = $1 AND library_id = $2 AND status = 'AVAILABLE';
+    `, bookID, libraryID).Scan(&total)
+	return total, err
+}
+
+// AddCopies registers count new physical copies of a title in a library,
+// each with its own copy uid and barcode, and returns the resulting
+// number of available copies. The copies are in condition, or in that of
+// the title when it is empty.
+func (r *catalogRepo) AddCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int, condition string) (int, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	libraryID, bookID, err := holdingIDs(ctx, tx, libraryUID, bookUID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO library_books (book_id, library_id)
+        VALUES ($1, $2)
+        ON CONFLICT (book_id, library_id) DO NOTHING;
+    `, bookID, libraryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO book_copies (copy_uid, barcode, book_id, library_id, condition)
+        SELECT g.uid, 'BC-' || upper(substr(replace(g.uid::text, '-', ''), 1, 12)), b.id, $2,
+               COALESCE(NULLIF($4, ''), b.condition)
+        FROM (SELECT gen_random_uuid() AS uid FROM generate_series(1, $3)) g, books b
+        WHERE b.id = $1;
+    `, bookID, libraryID, count, condition)
+	if err != nil {
+		return 0, mapWriteError(err)
+	}
+
+	total, err := countAvailable(ctx, tx, libraryID, bookID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return total, nil
+}
+
+// RetireCopies withdraws count available copies, worst condition first.
+func (r *catalogRepo) RetireCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (int, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	libraryID, bookID, err := holdingIDs(ctx, tx, libraryUID, bookUID)
+	if err != nil {
+		return 0, err
+	}
+
+	var held bool
+	err = tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM library_books WHERE book_id = $1 AND library_id = $2);
+    `, bookID, libraryID).Scan(&held)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	if !held {
+		return 0, ErrHoldingNotFound
+	}
+
+	result, err := tx.Exec(ctx, `
+        UPDATE book_copies SET status = 'RETIRED'
+        WHERE id IN (
+            SELECT id FROM book_copies
+            WHERE book_id = $1 AND library_id = $2 AND status = 'AVAILABLE'
+            ORDER BY CASE condition WHEN 'BAD' THEN 0 WHEN 'GOOD' THEN 1 ELSE 2 END, id
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        );
+    `, bookID, libraryID, count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	if result.RowsAffected() < int64(count) {
+		return 0, ErrNotEnoughCopies
+	}
+
+	total, err := countAvailable(ctx, tx, libraryID, bookID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return total, nil
+}
+
+// RetireCopy withdraws one specific copy; copies out on loan cannot be
+// retired until they are returned.
+func (r *catalogRepo) RetireCopy(ctx context.Context, copyUID uuid.UUID) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM book_copies WHERE copy_uid = $1 FOR UPDATE`, copyUID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCopyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	if status != models.CopyAvailable {
+		return ErrCopyStateConflict
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE book_copies SET status = 'RETIRED' WHERE copy_uid = $1`, copyUID); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lab2-rsoi/library-system/internal/models"
+	"platform/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrCopyNotFound      = errors.New("copy not found")
+	ErrCopyStateConflict = errors.New("copy is not in the expected status")
+)
+
+type CopyRepository interface {
+	ListCopies(ctx context.Context, libraryUID, bookUID uuid.UUID) ([]models.BookCopy, error)
+	GetCopy(ctx context.Context, copyUID uuid.UUID) (*models.BookCopy, error)
+	ReserveCopy(ctx context.Context, libraryUID, bookUID uuid.UUID) (*models.BookCopy, error)
+	ReleaseCopy(ctx context.Context, copyUID uuid.UUID, condition string) (*models.BookCopy, error)
+	SetCondition(ctx context.Context, copyUID uuid.UUID, condition string) (*models.BookCopy, error)
+}
+
+type copyRepo struct {
+	conn postgres.Connection
+}
+
+func NewCopyRepo(client postgres.Client) CopyRepository {
+	return &copyRepo{conn: client.Conn()}
+}
+
+func selectCopies() squirrel.SelectBuilder {
+	return qb.Select("c.id", "c.copy_uid", "c.barcode", "b.book_uid", "l.library_uid", "c.condition", "c.status").
+		From("book_copies c").
+		Join("books b ON b.id = c.book_id").
+		Join("library l ON l.id = c.library_id")
+}
+
+func (r *copyRepo) ListCopies(ctx context.Context, libraryUID, bookUID uuid.UUID) ([]models.BookCopy, error) {
+	query := selectCopies().
+		Where("l.library_uid = ?", libraryUID).
+		Where("b.book_uid = ?", bookUID).
+		OrderBy("c.barcode ASC")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return pgx.CollectRows[models.BookCopy](rows, pgx.RowToStructByName)
+}
+
+func (r *copyRepo) GetCopy(ctx context.Context, copyUID uuid.UUID) (*models.BookCopy, error) {
+	sql, args, err := selectCopies().Where("c.copy_uid = ?", copyUID).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	bookCopy, err := pgx.CollectOneRow[models.BookCopy](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCopyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+// ReserveCopy takes the best-conditioned available copy of a title off the
+// shelf. Concurrent callers never get the same copy.
+func (r *copyRepo) ReserveCopy(ctx context.Context, libraryUID, bookUID uuid.UUID) (*models.BookCopy, error) {
+	sql := `
+        UPDATE book_copies SET status = 'RESERVED'
+        WHERE id = (
+            SELECT c.id
+            FROM book_copies c
+            JOIN books b ON b.id = c.book_id
+            JOIN library l ON l.id = c.library_id
+            WHERE b.book_uid = $1
+              AND l.library_uid = $2
+              AND c.status = 'AVAILABLE'
+            ORDER BY CASE c.condition WHEN 'EXCELLENT' THEN 0 WHEN 'GOOD' THEN 1 ELSE 2 END, c.id
+            LIMIT 1
+            FOR UPDATE OF c SKIP LOCKED
+        )
+        RETURNING copy_uid;
+    `
+	var copyUID uuid.UUID
+	err := r.conn.QueryRow(ctx, sql, bookUID, libraryUID).Scan(&copyUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotEnoughCopies
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return r.GetCopy(ctx, copyUID)
+}
+
+// ReleaseCopy puts a reserved copy back on the shelf, recording the
+// condition it came back in when one is given.
+func (r *copyRepo) ReleaseCopy(ctx context.Context, copyUID uuid.UUID, condition string) (*models.BookCopy, error) {
+	sql := `
+        UPDATE book_copies
+        SET status = 'AVAILABLE',
+            condition = COALESCE(NULLIF($2, ''), condition)
+        WHERE copy_uid = $1 AND status = 'RESERVED';
+    `
+	result, err := r.conn.Exec(ctx, sql, copyUID, condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	bookCopy, err := r.GetCopy(ctx, copyUID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrCopyStateConflict
+	}
+	return bookCopy, nil
+}
+
+// SetCondition records the condition a copy is in. A retired copy is left
+// as it was withdrawn.
+func (r *copyRepo) SetCondition(ctx context.Context, copyUID uuid.UUID, condition string) (*models.BookCopy, error) {
+	result, err := r.conn.Exec(ctx, `
+        UPDATE book_copies SET condition = $2
+        WHERE copy_uid = $1 AND status <> 'RETIRED';
+    `, copyUID, condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	bookCopy, err := r.GetCopy(ctx, copyUID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrCopyStateConflict
+	}
+	return bookCopy, nil
+}
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"lab2-rsoi/library-system/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectCopyRead(mock pgxmock.PgxPoolIface, uid uuid.UUID, condition, status string) {
+	mock.ExpectQuery("FROM book_copies c").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "copy_uid", "barcode", "book_uid", "library_uid", "condition", "status",
+		}).AddRow(uint64(7), uid, "BC-0000000007", uuid.New(), uuid.New(), condition, status))
+}
+
+func TestSetCondition(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+	r := &copyRepo{conn: mock}
+	uid := uuid.New()
+
+	// the condition is of the copy, the title is left alone
+	mock.ExpectExec("UPDATE book_copies SET condition").
+		WithArgs(uid, "BAD").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	expectCopyRead(mock, uid, "BAD", models.CopyReserved)
+
+	bookCopy, err := r.SetCondition(context.Background(), uid, "BAD")
+	require.NoError(t, err)
+	assert.Equal(t, "BAD", bookCopy.Condition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetCondition_Retired(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+	r := &copyRepo{conn: mock}
+	uid := uuid.New()
+
+	mock.ExpectExec("UPDATE book_copies SET condition").
+		WithArgs(uid, "GOOD").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	expectCopyRead(mock, uid, "BAD", models.CopyRetired)
+
+	_, err = r.SetCondition(context.Background(), uid, "GOOD")
+	assert.ErrorIs(t, err, ErrCopyStateConflict)
+}
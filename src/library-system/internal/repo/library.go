@@ -2,11 +2,12 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	//"fmt"
 	"lab2-rsoi/library-system/internal/models"
-	"lab2-rsoi/library-system/pkg/postgres"
+	"platform/postgres"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -16,10 +17,11 @@ import (
 type LibraryRepository interface {
 	FetchLibrariesByCity(ctx context.Context, city string, page, size int) ([]models.Library, error)
 	FetchBooksByLibrary(ctx context.Context, libraryUID uuid.UUID, showAll bool, page, size int) ([]BookWithCount, error)
-	UpdateCount(ctx context.Context, bookID, libraryID uuid.UUID, count int) error
-	UpdateCondition(ctx context.Context, bookUID uuid.UUID, condition string) error
+	UpdateCount(ctx context.Context, bookID, libraryID uuid.UUID, delta int, condition string) error
 	GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*models.Library, error)
-	GetBookByUID(ctx context.Context, uid uuid.UUID) (*BookWithCount, error)
+	// GetBookByUID counts the available copies in libraryUID, or in every
+	// library when it is nil.
+	GetBookByUID(ctx context.Context, uid uuid.UUID, libraryUID *uuid.UUID) (*BookWithCount, error)
 	CountLibrariesByCity(ctx context.Context, city string) (int, error)
 	CountBooksByLibrary(ctx context.Context, libraryUID uuid.UUID, showAll bool) (int, error)
 	//IncreaseCount(ctx context.Context, i int, i2 int) interface{}
@@ -31,6 +33,11 @@ type libraryRepo struct {
 
 var qb = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
+// availableCopies derives the number of lendable copies of a title in a
+// library from book_copies; the count itself is not stored anywhere.
+const availableCopies = `(SELECT COUNT(*) FROM book_copies c
+    WHERE c.book_id = b.id AND c.library_id = l.id AND c.status = 'AVAILABLE')`
+
 func NewLibraryRepo(client postgres.Client) LibraryRepository {
 	return &libraryRepo{conn: client.Conn()}
 }
@@ -62,7 +69,7 @@ func (r *libraryRepo) CountBooksByLibrary(ctx context.Context, libraryUID uuid.U
 		Join("library_books lb ON lb.book_id = b.id AND lb.library_id = l.id")
 
 	if !showAll {
-		query = query.Where("lb.available_count > 0")
+		query = query.Where(availableCopies + " > 0")
 	}
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -106,14 +113,14 @@ func (r *libraryRepo) FetchBooksByLibrary(ctx context.Context, libraryUID uuid.U
 
 	query := qb.Select(
 		"b.id", "b.book_uid", "b.name", "b.author", "b.genre", "b.condition",
-		"lb.available_count",
+		availableCopies+" AS available_count",
 	).
 		From("books b").
 		Join("library l ON l.library_uid = ?", libraryUID).
 		Join("library_books lb ON lb.book_id = b.id AND lb.library_id = l.id")
 
 	if !showAll {
-		query = query.Where("lb.available_count > 0")
+		query = query.Where(availableCopies + " > 0")
 	}
 
 	query = query.OrderBy("b.name ASC").Limit(uint64(size)).Offset(uint64(offset))
@@ -137,57 +144,34 @@ func (r *libraryRepo) FetchBooksByLibrary(ctx context.Context, libraryUID uuid.U
 	return books, nil
 }
 
-func (r *libraryRepo) UpdateCount(ctx context.Context, bookUID, libraryUID uuid.UUID, count int) error {
-	sql := `
-        UPDATE library_books lb
-        SET available_count = $1
-        FROM books b, library l
-        WHERE b.book_uid = $2
-          AND l.library_uid = $3
-          AND lb.book_id = b.id
-          AND lb.library_id = l.id;
-    `
-	result, err := r.conn.Exec(ctx, sql, count, bookUID, libraryUID)
-	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("no record found for book_uid %s and library_uid %s", bookUID, libraryUID)
-	}
-
-	return nil
+// UpdateCount changes the number of available copies of a title the way
+// the count did before copies were tracked: a positive delta shelves that
+// many new copies, a negative one retires that many available ones. The
+// copies a legacy reservation took were never turned into copies, so giving
+// one back must not release a copy someone else holds. New copies are
+// shelved in condition, or in that of the title when it is empty.
+func (r *libraryRepo) UpdateCount(ctx context.Context, bookUID, libraryUID uuid.UUID, delta int, condition string) error {
+	catalog := &catalogRepo{conn: r.conn}
+	var err error
+	switch {
+	case delta > 0:
+		_, err = catalog.AddCopies(ctx, libraryUID, bookUID, delta, condition)
+	case delta < 0:
+		_, err = catalog.RetireCopies(ctx, libraryUID, bookUID, -delta)
+	}
+	return err
 }
 
-func (r *libraryRepo) UpdateCondition(ctx context.Context, bookUID uuid.UUID, condition string) error {
-	query := qb.Update("books").
-		Set("condition", condition).
-		Where("book_uid = ?", bookUID)
-
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+func (r *libraryRepo) GetBookByUID(ctx context.Context, uid uuid.UUID, libraryUID *uuid.UUID) (*BookWithCount, error) {
+	available := qb.Select("COUNT(*)").
+		From("book_copies c").
+		Where("c.book_id = b.id AND c.status = 'AVAILABLE'")
+	if libraryUID != nil {
+		available = available.Where("c.library_id = (SELECT id FROM library WHERE library_uid = ?)", *libraryUID)
 	}
-
-	result, err := r.conn.Exec(ctx, sql, args...)
-	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("no record found for book_uid %d", bookUID)
-	}
-
-	return nil
-}
-
-func (r *libraryRepo) GetBookByUID(ctx context.Context, uid uuid.UUID) (*BookWithCount, error) {
-	query := qb.Select("b.id, b.book_uid, b.name, b.author, b.genre, b.condition, lb.available_count").
+	query := qb.Select("b.id, b.book_uid, b.name, b.author, b.genre, b.condition").
+		Column(squirrel.Alias(available, "available_count")).
 		From("books b").
-		Join("library_books lb ON lb.book_id = b.id").
-		Join("library l ON l.id = lb.library_id").
 		Where("b.book_uid = ?", uid)
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -200,6 +184,9 @@ func (r *libraryRepo) GetBookByUID(ctx context.Context, uid uuid.UUID) (*BookWit
 	}
 	defer rows.Close()
 	book, err := pgx.CollectOneRow[BookWithCount](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBookNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +208,9 @@ func (r *libraryRepo) GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*mode
 	}
 	defer rows.Close()
 	library, err := pgx.CollectOneRow[models.Library](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLibraryNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,289 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/pkg/events"
+	"platform/postgres"
+	"platform/tracing"
+	"slices"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrTransferNotFound      = errors.New("transfer not found")
+	ErrTransferStateConflict = errors.New("transfer is not in a state that allows this step")
+)
+
+type TransferFilter struct {
+	LibraryUID *uuid.UUID
+	CopyUID    *uuid.UUID
+	Status     string
+}
+
+type TransferRepository interface {
+	CreateTransfer(ctx context.Context, t models.Transfer) (*models.Transfer, error)
+	GetTransfer(ctx context.Context, uid uuid.UUID) (*models.Transfer, error)
+	ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error)
+	UpdateTransferStatus(ctx context.Context, uid uuid.UUID, allowedFrom []string, to string) (*models.Transfer, error)
+}
+
+type transferRepo struct {
+	conn postgres.Connection
+}
+
+func NewTransferRepo(client postgres.Client) TransferRepository {
+	return &transferRepo{conn: client.Conn()}
+}
+
+func selectTransfers() squirrel.SelectBuilder {
+	return qb.Select(
+		"t.id", "t.transfer_uid", "c.copy_uid", "b.book_uid",
+		"lf.library_uid AS from_library_uid", "lt.library_uid AS to_library_uid",
+		"t.hold", "t.status", "t.requested_by", "t.created_at", "t.updated_at",
+	).
+		From("transfers t").
+		Join("book_copies c ON c.id = t.copy_id").
+		Join("books b ON b.id = c.book_id").
+		Join("library lf ON lf.id = t.from_library_id").
+		Join("library lt ON lt.id = t.to_library_id")
+}
+
+func (r *transferRepo) GetTransfer(ctx context.Context, uid uuid.UUID) (*models.Transfer, error) {
+	return getTransfer(ctx, r.conn, uid)
+}
+
+func getTransfer(ctx context.Context, conn postgres.Connection, uid uuid.UUID) (*models.Transfer, error) {
+	sql, args, err := selectTransfers().Where("t.transfer_uid = ?", uid).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	transfer, err := pgx.CollectOneRow[models.Transfer](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepo) ListTransfers(ctx context.Context, filter TransferFilter) ([]models.Transfer, error) {
+	query := selectTransfers().OrderBy("t.created_at DESC")
+	if filter.LibraryUID != nil {
+		query = query.Where(squirrel.Or{
+			squirrel.Eq{"lf.library_uid": *filter.LibraryUID},
+			squirrel.Eq{"lt.library_uid": *filter.LibraryUID},
+		})
+	}
+	if filter.CopyUID != nil {
+		query = query.Where(squirrel.Eq{"c.copy_uid": *filter.CopyUID})
+	}
+	if filter.Status != "" {
+		query = query.Where(squirrel.Eq{"t.status": filter.Status})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return pgx.CollectRows[models.Transfer](rows, pgx.RowToStructByName)
+}
+
+// CreateTransfer picks an available copy of the title in the source library
+// and takes it off the shelf in the same transaction that records the
+// transfer, so the source count drops the moment the request is accepted.
+func (r *transferRepo) CreateTransfer(ctx context.Context, t models.Transfer) (*models.Transfer, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	fromID, bookID, err := holdingIDs(ctx, tx, t.FromLibraryUID, t.BookUID)
+	if err != nil {
+		return nil, err
+	}
+	var toID uint64
+	err = tx.QueryRow(ctx, `SELECT id FROM library WHERE library_uid = $1`, t.ToLibraryUID).Scan(&toID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLibraryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var copyID uint64
+	err = tx.QueryRow(ctx, `
+        UPDATE book_copies SET status = 'IN_TRANSIT'
+        WHERE id = (
+            SELECT id FROM book_copies
+            WHERE book_id = $1 AND library_id = $2 AND status = 'AVAILABLE'
+            ORDER BY CASE condition WHEN 'EXCELLENT' THEN 0 WHEN 'GOOD' THEN 1 ELSE 2 END, id
+            LIMIT 1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id;
+    `, bookID, fromID).Scan(&copyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotEnoughCopies
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO transfers (transfer_uid, copy_id, from_library_id, to_library_id, hold, status, requested_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7);
+    `, t.TransferUID, copyID, fromID, toID, t.Hold, models.TransferRequested, t.RequestedBy)
+	if err != nil {
+		return nil, mapWriteError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetTransfer(ctx, t.TransferUID)
+}
+
+// UpdateTransferStatus moves a transfer to the next state and applies the
+// matching change to the copy: on receipt the copy is rehomed to the target
+// library (held for pickup when requested so) and a transfer.received event
+// is queued, on cancellation it goes back on the source library's shelf. A
+// copy already on its way can't be called back, so cancelling a transfer in
+// transit only drops its hold: the copy is shelved where it arrives.
+func (r *transferRepo) UpdateTransferStatus(ctx context.Context, uid uuid.UUID, allowedFrom []string, to string) (*models.Transfer, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		copyID, toLibraryID uint64
+		hold                bool
+		status              string
+	)
+	err = tx.QueryRow(ctx, `
+        SELECT copy_id, to_library_id, hold, status FROM transfers
+        WHERE transfer_uid = $1
+        FOR UPDATE;
+    `, uid).Scan(&copyID, &toLibraryID, &hold, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	if !slices.Contains(allowedFrom, status) {
+		return nil, fmt.Errorf("%w: %s", ErrTransferStateConflict, status)
+	}
+
+	switch to {
+	case models.TransferReceived:
+		copyStatus := models.CopyAvailable
+		if hold {
+			copyStatus = models.CopyReserved
+		}
+		_, err = tx.Exec(ctx, `
+            INSERT INTO library_books (book_id, library_id)
+            SELECT book_id, $2 FROM book_copies WHERE id = $1
+            ON CONFLICT (book_id, library_id) DO NOTHING;
+        `, copyID, toLibraryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+            UPDATE book_copies SET library_id = $2, status = $3 WHERE id = $1;
+        `, copyID, toLibraryID, copyStatus)
+	case models.TransferCancelled:
+		if status == models.TransferInTransit {
+			to, hold = models.TransferInTransit, false
+			break
+		}
+		_, err = tx.Exec(ctx, `UPDATE book_copies SET status = 'AVAILABLE' WHERE id = $1;`, copyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE transfers SET status = $2, hold = $3, updated_at = now() WHERE transfer_uid = $1;
+    `, uid, to, hold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	transfer, err := getTransfer(ctx, tx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if to == models.TransferReceived {
+		if err := addTransferEvent(ctx, tx, events.TransferReceived, transfer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return transfer, nil
+}
+
+// addTransferEvent queues an event about t in the outbox, numbered after
+// the previous events of the same transfer. The trace of ctx is kept with
+// it for the relay to publish the event in.
+func addTransferEvent(ctx context.Context, tx pgx.Tx, typ events.Type, t *models.Transfer) error {
+	evt, err := events.New(ctx, typ, t.TransferUID.String(), events.TransferV1{
+		TransferUID:    t.TransferUID.String(),
+		CopyUID:        t.CopyUID.String(),
+		BookUID:        t.BookUID.String(),
+		FromLibraryUID: t.FromLibraryUID.String(),
+		ToLibraryUID:   t.ToLibraryUID.String(),
+		Hold:           t.Hold,
+		Status:         t.Status,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+        UPDATE transfers SET event_seq = event_seq + 1
+        WHERE transfer_uid = $1
+        RETURNING event_seq;
+    `, t.TransferUID).Scan(&evt.Sequence)
+	if err != nil {
+		return fmt.Errorf("failed to number event: %w", err)
+	}
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO outbox (event_id, routing_key, ordering_key, sequence, correlation_id, payload, trace_context)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7);
+    `, evt.ID, evt.RoutingKey(), evt.OrderingKey, evt.Sequence, evt.CorrelationID, payload, tracing.Inject(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"lab2-rsoi/library-system/internal/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransferMock(t *testing.T) (*transferRepo, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	return &transferRepo{conn: mock}, mock
+}
+
+func expectTransferLocked(mock pgxmock.PgxPoolIface, uid uuid.UUID, status string) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT copy_id, to_library_id, hold, status FROM transfers").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{"copy_id", "to_library_id", "hold", "status"}).
+			AddRow(uint64(7), uint64(2), true, status))
+}
+
+func expectTransferRead(mock pgxmock.PgxPoolIface, uid uuid.UUID, hold bool, status string) {
+	now := time.Now()
+	mock.ExpectQuery("FROM transfers t").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "transfer_uid", "copy_uid", "book_uid", "from_library_uid", "to_library_uid",
+			"hold", "status", "requested_by", "created_at", "updated_at",
+		}).AddRow(uint64(1), uid, uuid.New(), uuid.New(), uuid.New(), uuid.New(),
+			hold, status, "gateway", now, now))
+}
+
+func TestUpdateTransferStatus_CancelRequested(t *testing.T) {
+	r, mock := newTransferMock(t)
+	uid := uuid.New()
+
+	expectTransferLocked(mock, uid, models.TransferRequested)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE book_copies SET status = 'AVAILABLE' WHERE id = $1;")).
+		WithArgs(uint64(7)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE transfers SET status").
+		WithArgs(uid, models.TransferCancelled, true).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	expectTransferRead(mock, uid, true, models.TransferCancelled)
+	mock.ExpectCommit()
+
+	transfer, err := r.UpdateTransferStatus(context.Background(), uid,
+		[]string{models.TransferRequested, models.TransferInTransit}, models.TransferCancelled)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferCancelled, transfer.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTransferStatus_CancelInTransit(t *testing.T) {
+	r, mock := newTransferMock(t)
+	uid := uuid.New()
+
+	// the copy is on the road: it is not put back on the source shelf, the
+	// transfer goes on without its hold
+	expectTransferLocked(mock, uid, models.TransferInTransit)
+	mock.ExpectExec("UPDATE transfers SET status").
+		WithArgs(uid, models.TransferInTransit, false).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	expectTransferRead(mock, uid, false, models.TransferInTransit)
+	mock.ExpectCommit()
+
+	transfer, err := r.UpdateTransferStatus(context.Background(), uid,
+		[]string{models.TransferRequested, models.TransferInTransit}, models.TransferCancelled)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferInTransit, transfer.Status)
+	assert.False(t, transfer.Hold)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"contract/library"
+	"errors"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/internal/repo"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const defaultCondition = "EXCELLENT"
+
+var (
+	EmptyFieldError = errors.New("field must not be blank")
+)
+
+type CatalogServiceIface interface {
+	CreateLibrary(ctx context.Context, req library.LibraryRequest) (*library.LibraryResponse, error)
+	UpdateLibrary(ctx context.Context, uid uuid.UUID, req library.LibraryRequest) (*library.LibraryResponse, error)
+	DeleteLibrary(ctx context.Context, uid uuid.UUID) error
+	CreateBook(ctx context.Context, req library.BookRequest) (*library.BookResponse, error)
+	UpdateBook(ctx context.Context, uid uuid.UUID, req library.BookRequest) (*library.BookResponse, error)
+	DeleteBook(ctx context.Context, uid uuid.UUID) error
+	AddCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (*library.HoldingResponse, error)
+	RetireCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (*library.HoldingResponse, error)
+	RetireCopy(ctx context.Context, copyUID uuid.UUID) error
+}
+
+type CatalogService struct {
+	repo repo.CatalogRepository
+}
+
+func NewCatalogService(r repo.CatalogRepository) CatalogServiceIface {
+	return &CatalogService{repo: r}
+}
+
+func normalizeLibrary(req library.LibraryRequest) (models.Library, error) {
+	lib := models.Library{
+		Name:    strings.TrimSpace(req.Name),
+		City:    strings.TrimSpace(req.City),
+		Address: strings.TrimSpace(req.Address),
+	}
+	if lib.Name == "" || lib.City == "" || lib.Address == "" {
+		return lib, EmptyFieldError
+	}
+	return lib, nil
+}
+
+func normalizeBook(req library.BookRequest) (models.Book, error) {
+	trim := func(s string) *string {
+		v := strings.TrimSpace(s)
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+
+	book := models.Book{
+		Name:      strings.TrimSpace(req.Name),
+		Author:    trim(req.Author),
+		Genre:     trim(req.Genre),
+		Condition: string(req.Condition),
+	}
+	if book.Name == "" {
+		return book, EmptyFieldError
+	}
+	if book.Condition == "" {
+		book.Condition = defaultCondition
+	}
+	return book, nil
+}
+
+func toLibraryResponse(lib *models.Library) *library.LibraryResponse {
+	return &library.LibraryResponse{
+		LibraryUid: lib.LibraryUID,
+		Name:       lib.Name,
+		City:       lib.City,
+		Address:    lib.Address,
+	}
+}
+
+func toBookResponse(book *models.Book) *library.BookResponse {
+	return &library.BookResponse{
+		BookUid:   book.BookUID,
+		Name:      book.Name,
+		Author:    value(book.Author),
+		Genre:     value(book.Genre),
+		Condition: library.Condition(book.Condition),
+	}
+}
+
+// value is what s points to, or "" when the field is unset.
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (s *CatalogService) CreateLibrary(ctx context.Context, req library.LibraryRequest) (*library.LibraryResponse, error) {
+	lib, err := normalizeLibrary(req)
+	if err != nil {
+		return nil, err
+	}
+	lib.LibraryUID = uuid.New()
+
+	created, err := s.repo.CreateLibrary(ctx, lib)
+	if err != nil {
+		return nil, err
+	}
+	return toLibraryResponse(created), nil
+}
+
+func (s *CatalogService) UpdateLibrary(ctx context.Context, uid uuid.UUID, req library.LibraryRequest) (*library.LibraryResponse, error) {
+	lib, err := normalizeLibrary(req)
+	if err != nil {
+		return nil, err
+	}
+	lib.LibraryUID = uid
+
+	updated, err := s.repo.UpdateLibrary(ctx, lib)
+	if err != nil {
+		return nil, err
+	}
+	return toLibraryResponse(updated), nil
+}
+
+func (s *CatalogService) DeleteLibrary(ctx context.Context, uid uuid.UUID) error {
+	return s.repo.DeleteLibrary(ctx, uid)
+}
+
+func (s *CatalogService) CreateBook(ctx context.Context, req library.BookRequest) (*library.BookResponse, error) {
+	book, err := normalizeBook(req)
+	if err != nil {
+		return nil, err
+	}
+	book.BookUID = uuid.New()
+
+	created, err := s.repo.CreateBook(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	return toBookResponse(created), nil
+}
+
+func (s *CatalogService) UpdateBook(ctx context.Context, uid uuid.UUID, req library.BookRequest) (*library.BookResponse, error) {
+	book, err := normalizeBook(req)
+	if err != nil {
+		return nil, err
+	}
+	book.BookUID = uid
+
+	updated, err := s.repo.UpdateBook(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	return toBookResponse(updated), nil
+}
+
+func (s *CatalogService) DeleteBook(ctx context.Context, uid uuid.UUID) error {
+	return s.repo.DeleteBook(ctx, uid)
+}
+
+func (s *CatalogService) AddCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (*library.HoldingResponse, error) {
+	total, err := s.repo.AddCopies(ctx, libraryUID, bookUID, count, "")
+	if err != nil {
+		return nil, err
+	}
+	return &library.HoldingResponse{LibraryUid: libraryUID, BookUid: bookUID, AvailableCount: total}, nil
+}
+
+func (s *CatalogService) RetireCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (*library.HoldingResponse, error) {
+	total, err := s.repo.RetireCopies(ctx, libraryUID, bookUID, count)
+	if err != nil {
+		return nil, err
+	}
+	return &library.HoldingResponse{LibraryUid: libraryUID, BookUid: bookUID, AvailableCount: total}, nil
+}
+
+func (s *CatalogService) RetireCopy(ctx context.Context, copyUID uuid.UUID) error {
+	return s.repo.RetireCopy(ctx, copyUID)
+}
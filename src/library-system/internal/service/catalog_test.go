@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"contract/library"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCatalogRepo struct {
+	mock.Mock
+}
+
+func (m *MockCatalogRepo) CreateLibrary(ctx context.Context, lib models.Library) (*models.Library, error) {
+	args := m.Called(ctx, lib)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Library), args.Error(1)
+}
+
+func (m *MockCatalogRepo) UpdateLibrary(ctx context.Context, lib models.Library) (*models.Library, error) {
+	args := m.Called(ctx, lib)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Library), args.Error(1)
+}
+
+func (m *MockCatalogRepo) DeleteLibrary(ctx context.Context, uid uuid.UUID) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepo) CreateBook(ctx context.Context, book models.Book) (*models.Book, error) {
+	args := m.Called(ctx, book)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Book), args.Error(1)
+}
+
+func (m *MockCatalogRepo) UpdateBook(ctx context.Context, book models.Book) (*models.Book, error) {
+	args := m.Called(ctx, book)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Book), args.Error(1)
+}
+
+func (m *MockCatalogRepo) DeleteBook(ctx context.Context, uid uuid.UUID) error {
+	args := m.Called(ctx, uid)
+	return args.Error(0)
+}
+
+func (m *MockCatalogRepo) AddCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int, condition string) (int, error) {
+	args := m.Called(ctx, libraryUID, bookUID, count, condition)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCatalogRepo) RetireCopies(ctx context.Context, libraryUID, bookUID uuid.UUID, count int) (int, error) {
+	args := m.Called(ctx, libraryUID, bookUID, count)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCatalogRepo) RetireCopy(ctx context.Context, copyUID uuid.UUID) error {
+	args := m.Called(ctx, copyUID)
+	return args.Error(0)
+}
+
+func TestCreateLibrary_TrimsAndAssignsUID(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	mockRepo.On("CreateLibrary", mock.Anything, mock.MatchedBy(func(l models.Library) bool {
+		return l.LibraryUID != uuid.Nil && l.Name == "Библиотека" && l.City == "Москва"
+	})).Return(&models.Library{LibraryUID: uuid.New(), Name: "Библиотека", City: "Москва", Address: "Адрес"}, nil)
+
+	resp, err := svc.CreateLibrary(context.Background(), library.LibraryRequest{Name: "  Библиотека ", City: "Москва", Address: "Адрес"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Библиотека", resp.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateLibrary_BlankName(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	resp, err := svc.CreateLibrary(context.Background(), library.LibraryRequest{Name: "   ", City: "Москва", Address: "Адрес"})
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, service.EmptyFieldError)
+	mockRepo.AssertNotCalled(t, "CreateLibrary", mock.Anything, mock.Anything)
+}
+
+func TestCreateBook_DefaultCondition(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	mockRepo.On("CreateBook", mock.Anything, mock.MatchedBy(func(b models.Book) bool {
+		return b.Condition == "EXCELLENT" && b.Author == nil
+	})).Return(&models.Book{BookUID: uuid.New(), Name: "Книга", Condition: "EXCELLENT"}, nil)
+
+	resp, err := svc.CreateBook(context.Background(), library.BookRequest{Name: "Книга", Author: " "})
+	assert.NoError(t, err)
+	assert.Equal(t, library.EXCELLENT, resp.Condition)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBook_Duplicate(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	mockRepo.On("CreateBook", mock.Anything, mock.Anything).Return(nil, repo.ErrAlreadyExists)
+
+	resp, err := svc.CreateBook(context.Background(), library.BookRequest{Name: "Книга"})
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, repo.ErrAlreadyExists)
+}
+
+func TestRetireCopies_NotEnough(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	libUID, bookUID := uuid.New(), uuid.New()
+	mockRepo.On("RetireCopies", mock.Anything, libUID, bookUID, 5).Return(0, repo.ErrNotEnoughCopies)
+
+	resp, err := svc.RetireCopies(context.Background(), libUID, bookUID, 5)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, repo.ErrNotEnoughCopies)
+}
+
+func TestAddCopies(t *testing.T) {
+	mockRepo := new(MockCatalogRepo)
+	svc := service.NewCatalogService(mockRepo)
+
+	libUID, bookUID := uuid.New(), uuid.New()
+	mockRepo.On("AddCopies", mock.Anything, libUID, bookUID, 3, "").Return(4, nil)
+
+	resp, err := svc.AddCopies(context.Background(), libUID, bookUID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resp.AvailableCount)
+	mockRepo.AssertExpectations(t)
+}
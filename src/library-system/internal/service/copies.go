@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"contract/library"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+type CopyServiceIface interface {
+	ListCopies(ctx context.Context, libraryUID, bookUID uuid.UUID) ([]library.CopyResponse, error)
+	GetCopy(ctx context.Context, copyUID uuid.UUID) (*library.CopyResponse, error)
+	ReserveCopy(ctx context.Context, libraryUID, bookUID uuid.UUID) (*library.CopyResponse, error)
+	ReleaseCopy(ctx context.Context, copyUID uuid.UUID, condition library.Condition) (*library.CopyResponse, error)
+	SetCondition(ctx context.Context, copyUID uuid.UUID, condition library.Condition) (*library.CopyResponse, error)
+}
+
+type CopyService struct {
+	repo repo.CopyRepository
+}
+
+func NewCopyService(r repo.CopyRepository) CopyServiceIface {
+	return &CopyService{repo: r}
+}
+
+func toCopyResponse(c *models.BookCopy) *library.CopyResponse {
+	return &library.CopyResponse{
+		CopyUid:    c.CopyUID,
+		Barcode:    c.Barcode,
+		BookUid:    c.BookUID,
+		LibraryUid: c.LibraryUID,
+		Condition:  library.Condition(c.Condition),
+		Status:     library.CopyStatus(c.Status),
+	}
+}
+
+func (s *CopyService) ListCopies(ctx context.Context, libraryUID, bookUID uuid.UUID) ([]library.CopyResponse, error) {
+	copies, err := s.repo.ListCopies(ctx, libraryUID, bookUID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]library.CopyResponse, len(copies))
+	for i := range copies {
+		items[i] = *toCopyResponse(&copies[i])
+	}
+	return items, nil
+}
+
+func (s *CopyService) GetCopy(ctx context.Context, copyUID uuid.UUID) (*library.CopyResponse, error) {
+	c, err := s.repo.GetCopy(ctx, copyUID)
+	if err != nil {
+		return nil, err
+	}
+	return toCopyResponse(c), nil
+}
+
+func (s *CopyService) ReserveCopy(ctx context.Context, libraryUID, bookUID uuid.UUID) (*library.CopyResponse, error) {
+	c, err := s.repo.ReserveCopy(ctx, libraryUID, bookUID)
+	countUnavailable("reserve", err)
+	if err != nil {
+		return nil, err
+	}
+	return toCopyResponse(c), nil
+}
+
+func (s *CopyService) ReleaseCopy(ctx context.Context, copyUID uuid.UUID, condition library.Condition) (*library.CopyResponse, error) {
+	c, err := s.repo.ReleaseCopy(ctx, copyUID, string(condition))
+	if err != nil {
+		return nil, err
+	}
+	return toCopyResponse(c), nil
+}
+
+func (s *CopyService) SetCondition(ctx context.Context, copyUID uuid.UUID, condition library.Condition) (*library.CopyResponse, error) {
+	c, err := s.repo.SetCondition(ctx, copyUID, string(condition))
+	if err != nil {
+		return nil, err
+	}
+	return toCopyResponse(c), nil
+}
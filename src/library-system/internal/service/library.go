@@ -2,9 +2,8 @@ package service
 
 import (
 	"context"
+	"contract/library"
 	"errors"
-	"lab2-rsoi/library-system/internal/dto"
-	//"lab2-rsoi/library-system/internal/models"
 	"lab2-rsoi/library-system/internal/repo"
 
 	"github.com/google/uuid"
@@ -15,12 +14,13 @@ var (
 )
 
 type LibraryServiceIface interface {
-	ListLibraries(ctx context.Context, city string, page, size int) (*dto.LibraryPaginationResponse, error)
-	ListBooks(ctx context.Context, libraryUID uuid.UUID, showAll bool, page, size int) (*dto.BookPaginationResponse, error)
-	GetBookByUID(ctx context.Context, uid uuid.UUID) (*dto.BookResponse, error)
-	GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*dto.LibraryResponse, error)
-	UpdateBookCount(ctx context.Context, bookUID, libraryUID uuid.UUID, inc int) error
-	UpdateBookCondition(ctx context.Context, bookUID uuid.UUID, condition string) error
+	ListLibraries(ctx context.Context, city string, page, size int) (*library.LibraryPaginationResponse, error)
+	ListBooks(ctx context.Context, libraryUID uuid.UUID, showAll bool, page, size int) (*library.BookPaginationResponse, error)
+	GetBookByUID(ctx context.Context, uid uuid.UUID, libraryUID *uuid.UUID) (*library.BookResponse, error)
+	GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*library.LibraryResponse, error)
+	// UpdateBookCount shelves new copies in condition, or in that of the
+	// title when it is empty.
+	UpdateBookCount(ctx context.Context, bookUID, libraryUID uuid.UUID, inc int, condition library.Condition) error
 }
 
 type LibraryService struct {
@@ -31,7 +31,7 @@ func NewLibraryService(r repo.LibraryRepository) LibraryServiceIface {
 	return &LibraryService{repo: r}
 }
 
-func (s *LibraryService) ListLibraries(ctx context.Context, city string, page, size int) (*dto.LibraryPaginationResponse, error) {
+func (s *LibraryService) ListLibraries(ctx context.Context, city string, page, size int) (*library.LibraryPaginationResponse, error) {
 	libraries, err := s.repo.FetchLibrariesByCity(ctx, city, page, size)
 	if err != nil {
 		return nil, err
@@ -42,17 +42,12 @@ func (s *LibraryService) ListLibraries(ctx context.Context, city string, page, s
 		return nil, err
 	}
 
-	items := make([]dto.LibraryResponse, len(libraries))
+	items := make([]library.LibraryResponse, len(libraries))
 	for i, l := range libraries {
-		items[i] = dto.LibraryResponse{
-			LibraryUID: l.LibraryUID,
-			Name:       l.Name,
-			City:       l.City,
-			Address:    l.Address,
-		}
+		items[i] = *toLibraryResponse(&l)
 	}
 
-	resp := &dto.LibraryPaginationResponse{
+	resp := &library.LibraryPaginationResponse{
 		Page:          page,
 		PageSize:      len(items),
 		TotalElements: total,
@@ -62,7 +57,7 @@ func (s *LibraryService) ListLibraries(ctx context.Context, city string, page, s
 	return resp, nil
 }
 
-func (s *LibraryService) ListBooks(ctx context.Context, libraryUID uuid.UUID, showAll bool, page, size int) (*dto.BookPaginationResponse, error) {
+func (s *LibraryService) ListBooks(ctx context.Context, libraryUID uuid.UUID, showAll bool, page, size int) (*library.BookPaginationResponse, error) {
 	books, err := s.repo.FetchBooksByLibrary(ctx, libraryUID, showAll, page, size)
 	if err != nil {
 		return nil, err
@@ -73,19 +68,13 @@ func (s *LibraryService) ListBooks(ctx context.Context, libraryUID uuid.UUID, sh
 		return nil, err
 	}
 
-	items := make([]dto.BookResponse, len(books))
+	items := make([]library.BookResponse, len(books))
 	for i, b := range books {
-		items[i] = dto.BookResponse{
-			BookUID:        b.BookUID,
-			Name:           b.Name,
-			Author:         b.Author,
-			Genre:          b.Genre,
-			Condition:      b.Condition,
-			AvailableCount: b.AvailableCount,
-		}
+		items[i] = *toBookResponse(&b.Book)
+		items[i].AvailableCount = b.AvailableCount
 	}
 
-	resp := &dto.BookPaginationResponse{
+	resp := &library.BookPaginationResponse{
 		Page:          page,
 		PageSize:      len(items),
 		TotalElements: total,
@@ -95,57 +84,36 @@ func (s *LibraryService) ListBooks(ctx context.Context, libraryUID uuid.UUID, sh
 	return resp, nil
 }
 
-func (s *LibraryService) GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*dto.LibraryResponse, error) {
+func (s *LibraryService) GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*library.LibraryResponse, error) {
 	lib, err := s.repo.GetLibraryByUID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := &dto.LibraryResponse{
-		ID:         lib.ID,
-		LibraryUID: lib.LibraryUID,
-		Name:       lib.Name,
-		City:       lib.City,
-		Address:    lib.Address,
-	}
+	resp := toLibraryResponse(lib)
+	resp.Id = int64(lib.ID)
 
 	return resp, nil
 }
 
-func (s *LibraryService) GetBookByUID(ctx context.Context, uid uuid.UUID) (*dto.BookResponse, error) {
-	book, err := s.repo.GetBookByUID(ctx, uid)
+func (s *LibraryService) GetBookByUID(ctx context.Context, uid uuid.UUID, libraryUID *uuid.UUID) (*library.BookResponse, error) {
+	book, err := s.repo.GetBookByUID(ctx, uid, libraryUID)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := &dto.BookResponse{
-		ID:             book.ID,
-		BookUID:        book.BookUID,
-		Name:           book.Name,
-		Author:         book.Author,
-		Genre:          book.Genre,
-		Condition:      book.Condition,
-		AvailableCount: book.AvailableCount,
-	}
+	resp := toBookResponse(&book.Book)
+	resp.Id = int64(book.ID)
+	resp.AvailableCount = book.AvailableCount
 
 	return resp, nil
 }
 
-func (s *LibraryService) UpdateBookCondition(ctx context.Context, bookUID uuid.UUID, condition string) error {
-	return s.repo.UpdateCondition(ctx, bookUID, condition)
-}
-
-func (s *LibraryService) UpdateBookCount(ctx context.Context, bookUID, libraryUID uuid.UUID, inc int) error {
-	book, err := s.repo.GetBookByUID(ctx, bookUID)
-	if err != nil {
-		return err
-	}
-
-	newCount := book.AvailableCount + inc
-	if newCount < 0 {
-		newCount = 0
+func (s *LibraryService) UpdateBookCount(ctx context.Context, bookUID, libraryUID uuid.UUID, inc int, condition library.Condition) error {
+	err := s.repo.UpdateCount(ctx, bookUID, libraryUID, inc, string(condition))
+	countUnavailable("count", err)
+	if errors.Is(err, repo.ErrNotEnoughCopies) {
 		return CountOfBooksIsZero
 	}
-
-	return s.repo.UpdateCount(ctx, bookUID, libraryUID, newCount)
+	return err
 }
@@ -41,18 +41,13 @@ func (m *MockLibraryRepo) GetLibraryByUID(ctx context.Context, uid uuid.UUID) (*
 	return args.Get(0).(*models.Library), args.Error(1)
 }
 
-func (m *MockLibraryRepo) GetBookByUID(ctx context.Context, uid uuid.UUID) (*repo.BookWithCount, error) {
-	args := m.Called(ctx, uid)
+func (m *MockLibraryRepo) GetBookByUID(ctx context.Context, uid uuid.UUID, libraryUID *uuid.UUID) (*repo.BookWithCount, error) {
+	args := m.Called(ctx, uid, libraryUID)
 	return args.Get(0).(*repo.BookWithCount), args.Error(1)
 }
 
-func (m *MockLibraryRepo) UpdateCondition(ctx context.Context, bookUID uuid.UUID, condition string) error {
-	args := m.Called(ctx, bookUID, condition)
-	return args.Error(0)
-}
-
-func (m *MockLibraryRepo) UpdateCount(ctx context.Context, bookUID, libraryUID uuid.UUID, newCount int) error {
-	args := m.Called(ctx, bookUID, libraryUID, newCount)
+func (m *MockLibraryRepo) UpdateCount(ctx context.Context, bookUID, libraryUID uuid.UUID, newCount int, condition string) error {
+	args := m.Called(ctx, bookUID, libraryUID, newCount, condition)
 	return args.Error(0)
 }
 
@@ -106,7 +101,7 @@ func TestGetLibraryByUID(t *testing.T) {
 
 	resp, err := svc.GetLibraryByUID(context.Background(), libUID)
 	assert.NoError(t, err)
-	assert.Equal(t, libUID, resp.LibraryUID)
+	assert.Equal(t, libUID, resp.LibraryUid)
 	assert.Equal(t, "Библиотека", resp.Name)
 	mockRepo.AssertExpectations(t)
 }
@@ -120,11 +115,25 @@ func TestGetBookByUID(t *testing.T) {
 	genre := "Жанр"
 
 	book := &repo.BookWithCount{Book: models.Book{BookUID: bookUID, Name: "Книга", Author: &author, Genre: &genre, Condition: "EXCELLENT"}, AvailableCount: 3}
-	mockRepo.On("GetBookByUID", mock.Anything, bookUID).Return(book, nil)
+	libUID := uuid.New()
+	mockRepo.On("GetBookByUID", mock.Anything, bookUID, &libUID).Return(book, nil)
 
-	resp, err := svc.GetBookByUID(context.Background(), bookUID)
+	resp, err := svc.GetBookByUID(context.Background(), bookUID, &libUID)
 	assert.NoError(t, err)
-	assert.Equal(t, bookUID, resp.BookUID)
+	assert.Equal(t, bookUID, resp.BookUid)
 	assert.Equal(t, "Книга", resp.Name)
+	assert.Equal(t, 3, resp.AvailableCount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateBookCount_NoAvailableCopies(t *testing.T) {
+	mockRepo := new(MockLibraryRepo)
+	svc := service.NewLibraryService(mockRepo)
+
+	bookUID, libUID := uuid.New(), uuid.New()
+	mockRepo.On("UpdateCount", mock.Anything, bookUID, libUID, -1, "").Return(repo.ErrNotEnoughCopies)
+
+	err := svc.UpdateBookCount(context.Background(), bookUID, libUID, -1, "")
+	assert.ErrorIs(t, err, service.CountOfBooksIsZero)
 	mockRepo.AssertExpectations(t)
 }
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"errors"
+	"lab2-rsoi/library-system/internal/repo"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var bookUnavailable = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "book_unavailable_rejections_total",
+	Help: "Requests turned down because no copy of the book was left, by what was asked for.",
+}, []string{"operation"})
+
+// countUnavailable records err if it turned the request down for lack of
+// copies.
+func countUnavailable(operation string, err error) {
+	if errors.Is(err, repo.ErrNotEnoughCopies) {
+		bookUnavailable.WithLabelValues(operation).Inc()
+	}
+}
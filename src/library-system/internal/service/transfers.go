@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"contract/library"
+	"errors"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+var (
+	SameLibraryError = errors.New("source and destination libraries must differ")
+)
+
+type TransferServiceIface interface {
+	RequestTransfer(ctx context.Context, req library.TransferRequest, requestedBy string) (*library.TransferResponse, error)
+	GetTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error)
+	ListTransfers(ctx context.Context, filter repo.TransferFilter) ([]library.TransferResponse, error)
+	ShipTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error)
+	ReceiveTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error)
+	CancelTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error)
+}
+
+type TransferService struct {
+	repo repo.TransferRepository
+}
+
+func NewTransferService(r repo.TransferRepository) TransferServiceIface {
+	return &TransferService{repo: r}
+}
+
+func toTransferResponse(t *models.Transfer) *library.TransferResponse {
+	return &library.TransferResponse{
+		TransferUid:    t.TransferUID,
+		CopyUid:        t.CopyUID,
+		BookUid:        t.BookUID,
+		FromLibraryUid: t.FromLibraryUID,
+		ToLibraryUid:   t.ToLibraryUID,
+		Hold:           t.Hold,
+		Status:         library.TransferStatus(t.Status),
+		RequestedBy:    t.RequestedBy,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func (s *TransferService) RequestTransfer(ctx context.Context, req library.TransferRequest, requestedBy string) (*library.TransferResponse, error) {
+	if req.FromLibraryUid == req.ToLibraryUid {
+		return nil, SameLibraryError
+	}
+
+	created, err := s.repo.CreateTransfer(ctx, models.Transfer{
+		TransferUID:    uuid.New(),
+		BookUID:        req.BookUid,
+		FromLibraryUID: req.FromLibraryUid,
+		ToLibraryUID:   req.ToLibraryUid,
+		Hold:           req.Hold,
+		RequestedBy:    requestedBy,
+	})
+	countUnavailable("transfer", err)
+	if err != nil {
+		return nil, err
+	}
+	return toTransferResponse(created), nil
+}
+
+func (s *TransferService) GetTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error) {
+	transfer, err := s.repo.GetTransfer(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return toTransferResponse(transfer), nil
+}
+
+func (s *TransferService) ListTransfers(ctx context.Context, filter repo.TransferFilter) ([]library.TransferResponse, error) {
+	transfers, err := s.repo.ListTransfers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]library.TransferResponse, 0, len(transfers))
+	for i := range transfers {
+		result = append(result, *toTransferResponse(&transfers[i]))
+	}
+	return result, nil
+}
+
+func (s *TransferService) ShipTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error) {
+	return s.move(ctx, uid, []string{models.TransferRequested}, models.TransferInTransit)
+}
+
+func (s *TransferService) ReceiveTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error) {
+	return s.move(ctx, uid, []string{models.TransferInTransit}, models.TransferReceived)
+}
+
+func (s *TransferService) CancelTransfer(ctx context.Context, uid uuid.UUID) (*library.TransferResponse, error) {
+	return s.move(ctx, uid, []string{models.TransferRequested, models.TransferInTransit}, models.TransferCancelled)
+}
+
+func (s *TransferService) move(ctx context.Context, uid uuid.UUID, from []string, to string) (*library.TransferResponse, error) {
+	transfer, err := s.repo.UpdateTransferStatus(ctx, uid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return toTransferResponse(transfer), nil
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"contract/library"
+	"lab2-rsoi/library-system/internal/models"
+	"lab2-rsoi/library-system/internal/repo"
+	"lab2-rsoi/library-system/internal/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferRepo struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepo) CreateTransfer(ctx context.Context, t models.Transfer) (*models.Transfer, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepo) GetTransfer(ctx context.Context, uid uuid.UUID) (*models.Transfer, error) {
+	args := m.Called(ctx, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepo) ListTransfers(ctx context.Context, filter repo.TransferFilter) ([]models.Transfer, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepo) UpdateTransferStatus(ctx context.Context, uid uuid.UUID, allowedFrom []string, to string) (*models.Transfer, error) {
+	args := m.Called(ctx, uid, allowedFrom, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func TestRequestTransfer_SameLibrary(t *testing.T) {
+	mockRepo := new(MockTransferRepo)
+	svc := service.NewTransferService(mockRepo)
+
+	libUID := uuid.New()
+	resp, err := svc.RequestTransfer(context.Background(), library.TransferRequest{
+		BookUid:        uuid.New(),
+		FromLibraryUid: libUID,
+		ToLibraryUid:   libUID,
+	}, "user")
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, service.SameLibraryError)
+	mockRepo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything)
+}
+
+func TestRequestTransfer(t *testing.T) {
+	mockRepo := new(MockTransferRepo)
+	svc := service.NewTransferService(mockRepo)
+
+	req := library.TransferRequest{BookUid: uuid.New(), FromLibraryUid: uuid.New(), ToLibraryUid: uuid.New(), Hold: true}
+	mockRepo.On("CreateTransfer", mock.Anything, mock.MatchedBy(func(tr models.Transfer) bool {
+		return tr.TransferUID != uuid.Nil && tr.Hold && tr.RequestedBy == "user"
+	})).Return(&models.Transfer{TransferUID: uuid.New(), Status: models.TransferRequested, Hold: true}, nil)
+
+	resp, err := svc.RequestTransfer(context.Background(), req, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, library.TransferStatusREQUESTED, resp.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReceiveTransfer_OnlyFromInTransit(t *testing.T) {
+	mockRepo := new(MockTransferRepo)
+	svc := service.NewTransferService(mockRepo)
+
+	uid := uuid.New()
+	mockRepo.On("UpdateTransferStatus", mock.Anything, uid, []string{models.TransferInTransit}, models.TransferReceived).
+		Return(nil, repo.ErrTransferStateConflict)
+
+	resp, err := svc.ReceiveTransfer(context.Background(), uid)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, repo.ErrTransferStateConflict)
+	mockRepo.AssertExpectations(t)
+}
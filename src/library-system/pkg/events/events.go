@@ -0,0 +1,39 @@
+// Package events defines the domain events library-system publishes. They
+// travel in the envelope of platform/events.
+package events
+
+import (
+	"context"
+	"platform/events"
+)
+
+const (
+	Exchange = events.Exchange
+	Source   = "library-system"
+)
+
+type (
+	Type     = events.Type
+	Envelope = events.Envelope
+)
+
+// TransferReceived reports a copy arriving at its target library. When the
+// transfer was a hold the copy now waits there for its patron.
+const TransferReceived Type = "transfer.received"
+
+// TransferV1 is the payload of transfer events at version 1.
+type TransferV1 struct {
+	TransferUID    string `json:"transferUid"`
+	CopyUID        string `json:"copyUid"`
+	BookUID        string `json:"bookUid"`
+	FromLibraryUID string `json:"fromLibraryUid"`
+	ToLibraryUID   string `json:"toLibraryUid"`
+	Hold           bool   `json:"hold"`
+	Status         string `json:"status"`
+}
+
+// New wraps data at version 1. The sequence is assigned when the event is
+// stored.
+func New(ctx context.Context, typ Type, orderingKey string, data any) (Envelope, error) {
+	return events.New(ctx, Source, typ, orderingKey, data)
+}
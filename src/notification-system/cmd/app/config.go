@@ -0,0 +1,21 @@
+package main
+
+import (
+	"notification-system/internal/channels"
+	"notification-system/internal/server"
+	"platform/auth"
+	"platform/logging"
+	"platform/postgres"
+	"platform/tracing"
+)
+
+type Config struct {
+	Server server.Server   `envconfig:"SERVER"`
+	DB     postgres.Config `envconfig:"DB"`
+	Auth   auth.Config     `envconfig:"AUTH"`
+	// RabbitMQ is where reservation and transfer events are consumed from.
+	RabbitMQ string          `envconfig:"RABBITMQ"`
+	Channels channels.Config `envconfig:"CHANNELS"`
+	Tracing  tracing.Config  `envconfig:"TRACING"`
+	Log      logging.Config  `envconfig:"LOG"`
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"notification-system/internal/channels"
+	"notification-system/internal/consumer"
+	"notification-system/internal/migrations"
+	"notification-system/internal/repo"
+	"notification-system/internal/server"
+	"notification-system/internal/service"
+	"notification-system/internal/templates"
+	"os"
+	"os/signal"
+	"platform/auth"
+	"platform/logging"
+	"platform/migrate"
+	"platform/postgres"
+	"platform/tracing"
+	"slices"
+	"syscall"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var cfg Config
+	if err := envconfig.Process("", &cfg); err != nil {
+		panic(err)
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, "notification-system", cfg.Tracing)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Warn("failed to flush traces")
+		}
+	}()
+
+	db, err := postgres.Connect(ctx, cfg.DB)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to database")
+	}
+	if err := db.Ping(ctx); err != nil {
+		log.WithError(err).Fatal("Failed to ping database")
+	}
+	log.Info("Successfully connected to database notifications")
+	defer db.Close()
+	prometheus.MustRegister(db.Collector())
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Command(ctx, db.Conn(), migrations.FS, os.Args[2:], os.Stdout); err != nil {
+			log.WithError(err).Fatal("migration failed")
+		}
+		return
+	}
+	if err := migrate.OnStart(ctx, db.Conn(), migrations.FS); err != nil {
+		log.WithError(err).Fatal("failed to apply migrations")
+	}
+
+	verifier, err := auth.NewVerifier(cfg.Auth)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up token verification")
+	}
+
+	render, err := templates.New()
+	if err != nil {
+		log.WithError(err).Fatal("failed to load templates")
+	}
+	enabled, err := channels.FromConfig(cfg.Channels)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up delivery channels")
+	}
+	names := slices.Sorted(maps.Keys(enabled))
+	log.Infof("delivery channels: %v", names)
+
+	srv, err := server.New(db, verifier, names, cfg.Server.Host, cfg.Server.Port, cfg.Server.Shutdown)
+	if err != nil {
+		log.WithError(err).Fatal("failed to initialize server")
+	}
+
+	consumerDone := make(chan struct{})
+	if cfg.RabbitMQ != "" {
+		notifier := service.NewNotifier(repo.NewNotificationRepo(db), render, enabled)
+		events := consumer.New(cfg.RabbitMQ, notifier.Handle)
+		srv.Health().Optional("events", events.Check())
+		go func() {
+			defer close(consumerDone)
+			events.Run(ctx)
+		}()
+	} else {
+		log.Warn("RABBITMQ is not set, no notifications are sent")
+		close(consumerDone)
+	}
+
+	// The deferred pool close runs only after Run has drained the requests
+	// that may still be using it.
+	if err := srv.Run(ctx); err != nil {
+		log.WithError(err).Error("server shutdown")
+	}
+	<-consumerDone
+}
@@ -0,0 +1,53 @@
+// Package channels delivers notifications outside the in-app inbox. A user
+// picks the channels by name in their preferences.
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notification-system/internal/models"
+	"time"
+)
+
+// ErrNoAddress is returned when the user picked a channel but left its
+// address empty.
+var ErrNoAddress = errors.New("no address set for this channel")
+
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, to models.Preferences, n models.Notification) error
+}
+
+type Config struct {
+	// EmailTransport is smtp, file or empty to disable email.
+	EmailTransport string `envconfig:"EMAIL_TRANSPORT"`
+	EmailFrom      string `envconfig:"EMAIL_FROM" default:"library@localhost"`
+	SMTPAddr       string `envconfig:"SMTP_ADDR" default:"localhost:1025"`
+	SMTPUser       string `envconfig:"SMTP_USER"`
+	SMTPPassword   string `envconfig:"SMTP_PASSWORD"`
+	// FileDir is where the file transport drops messages, one per file.
+	FileDir        string        `envconfig:"FILE_DIR" default:"/tmp/notifications"`
+	WebhookTimeout time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"5s"`
+}
+
+// FromConfig returns the channels cfg enables, by name.
+func FromConfig(cfg Config) (map[string]Channel, error) {
+	enabled := []Channel{NewWebhook(cfg.WebhookTimeout)}
+
+	switch cfg.EmailTransport {
+	case "":
+	case "smtp":
+		enabled = append(enabled, NewEmail(cfg.EmailFrom, NewSMTPTransport(cfg.SMTPAddr, cfg.SMTPUser, cfg.SMTPPassword)))
+	case "file":
+		enabled = append(enabled, NewEmail(cfg.EmailFrom, NewFileTransport(cfg.FileDir)))
+	default:
+		return nil, fmt.Errorf("unknown email transport %q", cfg.EmailTransport)
+	}
+
+	byName := make(map[string]Channel, len(enabled))
+	for _, ch := range enabled {
+		byName[ch.Name()] = ch
+	}
+	return byName, nil
+}
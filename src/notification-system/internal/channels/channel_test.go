@@ -0,0 +1,84 @@
+package channels_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification-system/internal/channels"
+	"notification-system/internal/models"
+	"os"
+	"path/filepath"
+	"platform/egress"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func notification() models.Notification {
+	return models.Notification{
+		NotificationUID: uuid.New(),
+		Username:        "alice",
+		Kind:            models.KindDueSoon,
+		Subject:         "Your book is due on 2025-03-11",
+		Body:            "Hello alice,\n",
+		CreatedAt:       time.Now(),
+	}
+}
+
+func TestEmail_FileTransport(t *testing.T) {
+	dir := t.TempDir()
+	email := channels.NewEmail("library@localhost", channels.NewFileTransport(dir))
+	addr := "alice@example.com"
+
+	require.NoError(t, email.Send(context.Background(), models.Preferences{Email: &addr}, notification()))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	msg, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(msg), "To: alice@example.com\r\n")
+	assert.Contains(t, string(msg), "Subject: Your book is due on 2025-03-11\r\n")
+
+	assert.ErrorIs(t, email.Send(context.Background(), models.Preferences{}, notification()), channels.ErrNoAddress)
+}
+
+func TestWebhook(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	hook := channels.NewWebhookClient(srv.Client())
+	url := srv.URL
+	require.NoError(t, hook.Send(context.Background(), models.Preferences{WebhookURL: &url}, notification()))
+	assert.Equal(t, "due_soon", got["kind"])
+
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	url = failing.URL
+	assert.Error(t, channels.NewWebhookClient(failing.Client()).Send(context.Background(), models.Preferences{WebhookURL: &url}, notification()))
+}
+
+func TestWebhook_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	hook := channels.NewWebhook(time.Second)
+	for _, url := range []string{srv.URL, "http://example.com/hook"} {
+		err := hook.Send(context.Background(), models.Preferences{WebhookURL: &url}, notification())
+		assert.ErrorIs(t, err, egress.ErrForbiddenURL, url)
+	}
+	assert.False(t, called)
+}
@@ -0,0 +1,105 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"notification-system/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transport hands a complete message over for delivery.
+type Transport interface {
+	Deliver(ctx context.Context, from, to string, msg []byte) error
+}
+
+type Email struct {
+	from      string
+	transport Transport
+}
+
+func NewEmail(from string, transport Transport) *Email {
+	return &Email{from: from, transport: transport}
+}
+
+func (e *Email) Name() string { return "email" }
+
+func (e *Email) Send(ctx context.Context, to models.Preferences, n models.Notification) error {
+	if to.Email == nil || *to.Email == "" {
+		return ErrNoAddress
+	}
+	return e.transport.Deliver(ctx, e.from, *to.Email, e.message(*to.Email, n))
+}
+
+func (e *Email) message(to string, n models.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.CreatedAt.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@notification-system>\r\n", n.NotificationUID)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+type SMTPTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPTransport sends through the server at addr, authenticating only
+// when a user is given.
+func NewSMTPTransport(addr, user, password string) *SMTPTransport {
+	t := &SMTPTransport{addr: addr}
+	if user != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		t.auth = smtp.PlainAuth("", user, password, host)
+	}
+	return t
+}
+
+func (t *SMTPTransport) Deliver(_ context.Context, from, to string, msg []byte) error {
+	if err := smtp.SendMail(t.addr, t.auth, from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", to, err)
+	}
+	return nil
+}
+
+// FileTransport writes each message into dir as an .eml file. It stands in
+// for a mail server in development and tests.
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{dir: dir}
+}
+
+func (t *FileTransport) Deliver(_ context.Context, _, to string, msg []byte) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", t.dir, err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), sanitize(to))
+	if err := os.WriteFile(filepath.Join(t.dir, name), msg, 0o644); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
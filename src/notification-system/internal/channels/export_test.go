@@ -0,0 +1,9 @@
+package channels
+
+import "net/http"
+
+// NewWebhookClient sends over client, which may reach test servers on
+// loopback.
+func NewWebhookClient(client *http.Client) *Webhook {
+	return &Webhook{client: client}
+}
@@ -0,0 +1,68 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"notification-system/internal/models"
+	"platform/egress"
+	"time"
+)
+
+// Webhook posts the notification as JSON to the user's URL.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{client: egress.Client(timeout)}
+}
+
+func (w *Webhook) Name() string { return "webhook" }
+
+type webhookPayload struct {
+	NotificationUID string    `json:"notificationUid"`
+	Username        string    `json:"username"`
+	Kind            string    `json:"kind"`
+	Subject         string    `json:"subject"`
+	Body            string    `json:"body"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func (w *Webhook) Send(ctx context.Context, to models.Preferences, n models.Notification) error {
+	if to.WebhookURL == nil || *to.WebhookURL == "" {
+		return ErrNoAddress
+	}
+	body, err := json.Marshal(webhookPayload{
+		NotificationUID: n.NotificationUID.String(),
+		Username:        n.Username,
+		Kind:            n.Kind,
+		Subject:         n.Subject,
+		Body:            n.Body,
+		CreatedAt:       n.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *to.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if err := egress.CheckScheme(req.URL); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
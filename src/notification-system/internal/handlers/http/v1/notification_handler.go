@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"contract/notification"
+	"errors"
+	"net/http"
+	"notification-system/internal/repo"
+	"notification-system/internal/service"
+	"platform/auth"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	service service.InboxServiceIFace
+}
+
+func writeNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repo.ErrNotificationNotFound):
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+	case errors.Is(err, service.ErrUnknownChannel), errors.Is(err, service.ErrInvalidWebhook):
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+	default:
+		problem.WriteInternal(c, err)
+	}
+}
+
+func New(service service.InboxServiceIFace) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	notificationRoutes := rg.Group("/notifications")
+	{
+		notificationRoutes.GET("/", h.ListHandler)
+		notificationRoutes.POST("/:uid/read", h.MarkReadHandler)
+		notificationRoutes.GET("/preferences", h.GetPreferencesHandler)
+		notificationRoutes.PUT("/preferences", h.UpdatePreferencesHandler)
+	}
+}
+
+// GET /api/v1/notifications/?unread=true
+func (h *NotificationHandler) ListHandler(c *gin.Context) {
+	var req notification.ListNotificationsParams
+	if err := c.ShouldBindQuery(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.List(c, auth.Username(c), req.Unread)
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /api/v1/notifications/:uid/read
+func (h *NotificationHandler) MarkReadHandler(c *gin.Context) {
+	var req struct {
+		UID string `uri:"uid" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.MarkRead(c, uuid.MustParse(req.UID), auth.Username(c)); err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GET /api/v1/notifications/preferences
+func (h *NotificationHandler) GetPreferencesHandler(c *gin.Context) {
+	resp, err := h.service.GetPreferences(c, auth.Username(c))
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PUT /api/v1/notifications/preferences
+func (h *NotificationHandler) UpdatePreferencesHandler(c *gin.Context) {
+	var req notification.Preferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.UpdatePreferences(c, auth.Username(c), req)
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
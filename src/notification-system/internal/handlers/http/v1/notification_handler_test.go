@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"context"
+	"contract/notification"
+	"errors"
+	"net/http"
+	handlers "notification-system/internal/handlers/http/v1"
+	"notification-system/internal/repo"
+	"notification-system/internal/service"
+	"platform/auth/authtest"
+	"platform/problem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingService struct {
+	err error
+}
+
+func (s failingService) List(context.Context, string, bool) ([]notification.NotificationResponse, error) {
+	return nil, s.err
+}
+
+func (s failingService) MarkRead(context.Context, uuid.UUID, string) error {
+	return s.err
+}
+
+func (s failingService) GetPreferences(context.Context, string) (*notification.Preferences, error) {
+	return nil, s.err
+}
+
+func (s failingService) UpdatePreferences(context.Context, string, notification.Preferences) (*notification.Preferences, error) {
+	return nil, s.err
+}
+
+func TestNotificationErrorStatus(t *testing.T) {
+	minter := authtest.NewMinter()
+	token := minter.Token(jwt.MapClaims{"sub": "user", "roles": []string{"patron"}})
+
+	tests := []struct {
+		name   string
+		err    error
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"not yours", repo.ErrNotificationNotFound, http.MethodPost, "/api/v1/notifications/" + uuid.NewString() + "/read", "", http.StatusNotFound},
+		{"not a uid", nil, http.MethodPost, "/api/v1/notifications/42/read", "", http.StatusBadRequest},
+		{"unknown channel", service.ErrUnknownChannel, http.MethodPut, "/api/v1/notifications/preferences", `{"channels":["pigeon"]}`, http.StatusBadRequest},
+		{"private webhook", service.ErrInvalidWebhook, http.MethodPut, "/api/v1/notifications/preferences", `{"webhookUrl":"https://10.0.0.7/"}`, http.StatusBadRequest},
+		{"bad email", nil, http.MethodPut, "/api/v1/notifications/preferences", `{"email":"nope"}`, http.StatusBadRequest},
+		{"database down", errors.New("connection refused"), http.MethodGet, "/api/v1/notifications/", "", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, v1 := minter.Router(t, "/api/v1")
+			handlers.New(failingService{err: tt.err}).RegisterRoutes(v1)
+
+			w := authtest.Do(r, tt.method, tt.path, token, tt.body)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS pending_holds;
+DROP TABLE IF EXISTS deliveries;
+DROP TABLE IF EXISTS preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,55 @@
+-- The in-app inbox: every notification a user was sent.
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id               SERIAL PRIMARY KEY,
+    notification_uid UUID UNIQUE NOT NULL,
+    username         VARCHAR(80) NOT NULL,
+    kind             VARCHAR(40) NOT NULL,
+    subject          VARCHAR(255) NOT NULL,
+    body             TEXT NOT NULL,
+    event_id         UUID NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT now(),
+    read_at          TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_username_idx ON notifications (username, created_at DESC);
+
+-- Where else a user wants to be told. The inbox is always on.
+CREATE TABLE IF NOT EXISTS preferences
+(
+    username    VARCHAR(80) PRIMARY KEY,
+    email       VARCHAR(255),
+    webhook_url VARCHAR(2048),
+    channels    TEXT[] NOT NULL DEFAULT '{}'
+);
+
+-- Outcome of each delivery of a notification over an external channel.
+CREATE TABLE IF NOT EXISTS deliveries
+(
+    id               SERIAL PRIMARY KEY,
+    notification_uid UUID NOT NULL REFERENCES notifications (notification_uid) ON DELETE CASCADE,
+    channel          VARCHAR(40) NOT NULL,
+    status           VARCHAR(20) NOT NULL
+        CHECK (status IN ('SENT', 'FAILED')),
+    error            TEXT,
+    attempted_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Reservations whose copy is on its way to the pickup library, so that the
+-- patron can be told when the transfer arrives.
+CREATE TABLE IF NOT EXISTS pending_holds
+(
+    copy_uid        UUID PRIMARY KEY,
+    reservation_uid UUID UNIQUE NOT NULL,
+    username        VARCHAR(80) NOT NULL,
+    book_uid        UUID NOT NULL,
+    library_uid     UUID NOT NULL
+);
+
+-- Events already handled, so that redeliveries don't notify twice.
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    event_id     UUID PRIMARY KEY,
+    event_type   VARCHAR(80) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT now()
+);
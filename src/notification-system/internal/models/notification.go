@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of notification, each rendered from the template of the same name.
+const (
+	KindDueSoon   = "due_soon"
+	KindOverdue   = "overdue"
+	KindHoldReady = "hold_ready"
+)
+
+type Notification struct {
+	ID              int64      `db:"id"`
+	NotificationUID uuid.UUID  `db:"notification_uid"`
+	Username        string     `db:"username"`
+	Kind            string     `db:"kind"`
+	Subject         string     `db:"subject"`
+	Body            string     `db:"body"`
+	EventID         uuid.UUID  `db:"event_id"`
+	CreatedAt       time.Time  `db:"created_at"`
+	ReadAt          *time.Time `db:"read_at"`
+}
+
+// Preferences says where a user wants notifications delivered besides the
+// inbox.
+type Preferences struct {
+	Username   string   `db:"username"`
+	Email      *string  `db:"email"`
+	WebhookURL *string  `db:"webhook_url"`
+	Channels   []string `db:"channels"`
+}
+
+// Hold is a reservation whose copy is being moved to the pickup library.
+type Hold struct {
+	CopyUID        uuid.UUID `db:"copy_uid"`
+	ReservationUID uuid.UUID `db:"reservation_uid"`
+	Username       string    `db:"username"`
+	BookUID        uuid.UUID `db:"book_uid"`
+	LibraryUID     uuid.UUID `db:"library_uid"`
+}
@@ -0,0 +1,223 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notification-system/internal/models"
+	"platform/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrHoldNotFound         = errors.New("no reservation waits for this copy")
+)
+
+type NotificationRepo interface {
+	// ClaimEvent records the event as handled and reports false when it
+	// already was.
+	ClaimEvent(ctx context.Context, eventID uuid.UUID, eventType string) (bool, error)
+	AddHold(ctx context.Context, hold models.Hold) error
+	// TakeHold removes and returns the hold waiting for copyUID.
+	TakeHold(ctx context.Context, copyUID uuid.UUID) (*models.Hold, error)
+	DropHold(ctx context.Context, reservationUID uuid.UUID) error
+
+	CreateNotification(ctx context.Context, n models.Notification) (*models.Notification, error)
+	ListNotifications(ctx context.Context, username string, unreadOnly bool) ([]models.Notification, error)
+	MarkRead(ctx context.Context, uid uuid.UUID, username string) error
+	RecordDelivery(ctx context.Context, notificationUID uuid.UUID, channel string, deliveryErr error) error
+
+	// GetPreferences returns the stored preferences or the defaults for a
+	// user who never set any.
+	GetPreferences(ctx context.Context, username string) (*models.Preferences, error)
+	SavePreferences(ctx context.Context, p models.Preferences) error
+
+	// InTx runs fn against a repo bound to one transaction and commits
+	// when fn succeeds.
+	InTx(ctx context.Context, fn func(tx NotificationRepo) error) error
+}
+
+type notificationRepo struct {
+	conn postgres.Connection
+}
+
+var qb = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+func NewNotificationRepo(client postgres.Client) NotificationRepo {
+	return &notificationRepo{conn: client.Conn()}
+}
+
+func (r *notificationRepo) ClaimEvent(ctx context.Context, eventID uuid.UUID, eventType string) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+        INSERT INTO processed_events (event_id, event_type)
+        VALUES ($1, $2)
+        ON CONFLICT (event_id) DO NOTHING;
+    `, eventID, eventType)
+	if err != nil {
+		return false, fmt.Errorf("failed to record event: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *notificationRepo) AddHold(ctx context.Context, hold models.Hold) error {
+	_, err := r.conn.Exec(ctx, `
+        INSERT INTO pending_holds (copy_uid, reservation_uid, username, book_uid, library_uid)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT DO NOTHING;
+    `, hold.CopyUID, hold.ReservationUID, hold.Username, hold.BookUID, hold.LibraryUID)
+	if err != nil {
+		return fmt.Errorf("failed to store hold: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) TakeHold(ctx context.Context, copyUID uuid.UUID) (*models.Hold, error) {
+	rows, err := r.conn.Query(ctx, `
+        DELETE FROM pending_holds WHERE copy_uid = $1
+        RETURNING copy_uid, reservation_uid, username, book_uid, library_uid;
+    `, copyUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	hold, err := pgx.CollectOneRow[models.Hold](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *notificationRepo) DropHold(ctx context.Context, reservationUID uuid.UUID) error {
+	_, err := r.conn.Exec(ctx, `DELETE FROM pending_holds WHERE reservation_uid = $1;`, reservationUID)
+	if err != nil {
+		return fmt.Errorf("failed to drop hold: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) CreateNotification(ctx context.Context, n models.Notification) (*models.Notification, error) {
+	sql, args, err := qb.Insert("notifications").
+		Columns("notification_uid", "username", "kind", "subject", "body", "event_id").
+		Values(n.NotificationUID, n.Username, n.Kind, n.Subject, n.Body, n.EventID).
+		Suffix("RETURNING *").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	created, err := pgx.CollectOneRow[models.Notification](rows, pgx.RowToStructByName)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *notificationRepo) ListNotifications(ctx context.Context, username string, unreadOnly bool) ([]models.Notification, error) {
+	query := qb.Select("id", "notification_uid", "username", "kind", "subject", "body", "event_id", "created_at", "read_at").
+		From("notifications").
+		Where(squirrel.Eq{"username": username}).
+		OrderBy("created_at DESC", "id DESC")
+	if unreadOnly {
+		query = query.Where(squirrel.Eq{"read_at": nil})
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows[models.Notification](rows, pgx.RowToStructByName)
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, uid uuid.UUID, username string) error {
+	sql, args, err := qb.Update("notifications").
+		Set("read_at", squirrel.Expr("COALESCE(read_at, now())")).
+		Where(squirrel.Eq{"notification_uid": uid, "username": username}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := r.conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepo) RecordDelivery(ctx context.Context, notificationUID uuid.UUID, channel string, deliveryErr error) error {
+	status, message := "SENT", ""
+	if deliveryErr != nil {
+		status, message = "FAILED", deliveryErr.Error()
+	}
+	_, err := r.conn.Exec(ctx, `
+        INSERT INTO deliveries (notification_uid, channel, status, error)
+        VALUES ($1, $2, $3, NULLIF($4, ''));
+    `, notificationUID, channel, status, message)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, username string) (*models.Preferences, error) {
+	rows, err := r.conn.Query(ctx, `
+        SELECT username, email, webhook_url, channels FROM preferences WHERE username = $1;
+    `, username)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := pgx.CollectOneRow[models.Preferences](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.Preferences{Username: username, Channels: []string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, p models.Preferences) error {
+	if p.Channels == nil {
+		p.Channels = []string{}
+	}
+	_, err := r.conn.Exec(ctx, `
+        INSERT INTO preferences (username, email, webhook_url, channels)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (username) DO UPDATE
+        SET email = EXCLUDED.email, webhook_url = EXCLUDED.webhook_url, channels = EXCLUDED.channels;
+    `, p.Username, p.Email, p.WebhookURL, p.Channels)
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) InTx(ctx context.Context, fn func(tx NotificationRepo) error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&notificationRepo{conn: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"sync/atomic"
+
+	"notification-system/internal/handlers/http/v1"
+	"notification-system/internal/repo"
+	"notification-system/internal/service"
+	"platform/postgres"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+type Server struct {
+	Host      string             `envconfig:"HOST"`
+	Port      int                `envconfig:"PORT" required:"true"`
+	Shutdown  lifecycle.Shutdown `envconfig:"SHUTDOWN"`
+	DB        postgres.Client
+	Verifier  *auth.Verifier `ignored:"true"`
+	GinRouter *gin.Engine
+	// Channels are the delivery channels users may pick.
+	Channels []string `ignored:"true"`
+	ready    atomic.Bool
+	health   *health.Checker
+}
+
+func New(dbc postgres.Client, verifier *auth.Verifier, channels []string, host string, port int, shutdown lifecycle.Shutdown) (*Server, error) {
+	s := &Server{
+		Host:      host,
+		Port:      port,
+		Shutdown:  shutdown,
+		DB:        dbc,
+		Verifier:  verifier,
+		GinRouter: gin.New(),
+		Channels:  channels,
+	}
+	// Handlers pass the gin context on as a context.Context; fall back to
+	// the request context so the trace span reaches the repositories.
+	s.GinRouter.ContextWithFallback = true
+	s.GinRouter.Use(logging.Middleware(), metrics.Middleware(), gin.Recovery(), otelgin.Middleware("notification-system"))
+
+	if err := s.initRoutes(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Server) initRoutes() error {
+	s.GinRouter.GET("/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"msg": "pong"})
+	})
+	s.initHealthRoutes()
+	s.GinRouter.GET(metrics.Path, metrics.Handler())
+	if err := s.initDocsRoutes(); err != nil {
+		return err
+	}
+
+	authMiddleware := auth.AuthMiddleware(s.Verifier)
+	v1 := s.GinRouter.Group("/api/v1")
+	v1.Use(authMiddleware, auth.Require(auth.Patron, auth.Service), auth.Identity())
+
+	notificationRepo := repo.NewNotificationRepo(s.DB)
+	inboxService := service.NewInboxService(notificationRepo, s.Channels)
+	notificationHandler := handlers.New(inboxService)
+	notificationHandler.RegisterRoutes(v1)
+
+	return nil
+}
+
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
+// Run serves until ctx is cancelled, then drains as lifecycle.Run does.
+func (s *Server) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, fmt.Sprintf("%s:%d", s.Host, s.Port), s.GinRouter, s.Shutdown, &s.ready)
+}
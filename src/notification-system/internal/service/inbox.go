@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"contract/notification"
+	"errors"
+	"fmt"
+	"notification-system/internal/models"
+	"notification-system/internal/repo"
+	"platform/egress"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownChannel is returned when preferences name a channel this
+// deployment doesn't offer.
+var ErrUnknownChannel = errors.New("unknown channel")
+
+// ErrInvalidWebhook is returned when preferences set a webhook URL that the
+// webhook channel would refuse to call.
+var ErrInvalidWebhook = errors.New("invalid webhook url")
+
+type InboxServiceIFace interface {
+	List(ctx context.Context, username string, unreadOnly bool) ([]notification.NotificationResponse, error)
+	MarkRead(ctx context.Context, uid uuid.UUID, username string) error
+	GetPreferences(ctx context.Context, username string) (*notification.Preferences, error)
+	UpdatePreferences(ctx context.Context, username string, req notification.Preferences) (*notification.Preferences, error)
+}
+
+type inboxService struct {
+	repo     repo.NotificationRepo
+	channels []string
+}
+
+// NewInboxService serves the inbox of each user. channels are the names a
+// user may pick in their preferences.
+func NewInboxService(r repo.NotificationRepo, channels []string) InboxServiceIFace {
+	return &inboxService{repo: r, channels: channels}
+}
+
+func (s *inboxService) List(ctx context.Context, username string, unreadOnly bool) ([]notification.NotificationResponse, error) {
+	list, err := s.repo.ListNotifications(ctx, username, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]notification.NotificationResponse, 0, len(list))
+	for _, n := range list {
+		item := notification.NotificationResponse{
+			NotificationUid: n.NotificationUID,
+			Kind:            notification.NotificationKind(n.Kind),
+			Subject:         n.Subject,
+			Body:            n.Body,
+			CreatedAt:       n.CreatedAt,
+		}
+		if n.ReadAt != nil {
+			item.ReadAt = *n.ReadAt
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+func (s *inboxService) MarkRead(ctx context.Context, uid uuid.UUID, username string) error {
+	return s.repo.MarkRead(ctx, uid, username)
+}
+
+func (s *inboxService) GetPreferences(ctx context.Context, username string) (*notification.Preferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return preferencesResponse(prefs), nil
+}
+
+func (s *inboxService) UpdatePreferences(ctx context.Context, username string, req notification.Preferences) (*notification.Preferences, error) {
+	names := make([]string, 0, len(req.Channels))
+	for _, ch := range req.Channels {
+		name := string(ch)
+		if !slices.Contains(s.channels, name) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, name)
+		}
+		names = append(names, name)
+	}
+	if req.WebhookUrl != nil && *req.WebhookUrl != "" {
+		if err := egress.CheckURL(ctx, *req.WebhookUrl); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+		}
+	}
+	prefs := models.Preferences{
+		Username:   username,
+		Email:      req.Email,
+		WebhookURL: req.WebhookUrl,
+		Channels:   slices.Compact(slices.Sorted(slices.Values(names))),
+	}
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return preferencesResponse(&prefs), nil
+}
+
+func preferencesResponse(p *models.Preferences) *notification.Preferences {
+	names := make([]notification.Channel, 0, len(p.Channels))
+	for _, name := range p.Channels {
+		names = append(names, notification.Channel(name))
+	}
+	return &notification.Preferences{Email: p.Email, WebhookUrl: p.WebhookURL, Channels: names}
+}
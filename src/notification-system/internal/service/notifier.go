@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notification-system/internal/channels"
+	"notification-system/internal/models"
+	"notification-system/internal/repo"
+	"notification-system/internal/templates"
+	"notification-system/pkg/events"
+	"platform/logging"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrMalformedEvent marks an event that will never apply, however often it
+// is redelivered.
+var ErrMalformedEvent = errors.New("malformed event")
+
+// Notifier turns reservation and transfer events into notifications. Each
+// notification lands in the user's inbox and is then sent over the channels
+// the user picked.
+type Notifier struct {
+	repo     repo.NotificationRepo
+	render   *templates.Renderer
+	channels map[string]channels.Channel
+}
+
+func NewNotifier(r repo.NotificationRepo, render *templates.Renderer, chs map[string]channels.Channel) *Notifier {
+	return &Notifier{repo: r, render: render, channels: chs}
+}
+
+// Handle applies one event. An event seen before is skipped.
+func (n *Notifier) Handle(ctx context.Context, evt events.Envelope) error {
+	if evt.Version != 1 {
+		return fmt.Errorf("%w: %s has unsupported version %d", ErrMalformedEvent, evt.Type, evt.Version)
+	}
+
+	var created []models.Notification
+	err := n.repo.InTx(ctx, func(tx repo.NotificationRepo) error {
+		claimed, err := tx.ClaimEvent(ctx, evt.ID, string(evt.Type))
+		if err != nil || !claimed {
+			return err
+		}
+		created, err = n.apply(ctx, tx, evt)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Delivery happens after commit so that a redelivered event never
+	// sends twice. A failed delivery is recorded, not retried: the
+	// notification is still in the inbox.
+	for _, notification := range created {
+		n.deliver(ctx, notification)
+	}
+	return nil
+}
+
+func (n *Notifier) apply(ctx context.Context, tx repo.NotificationRepo, evt events.Envelope) ([]models.Notification, error) {
+	switch evt.Type {
+	case events.ReservationCreated:
+		data, err := reservation(evt)
+		if err != nil {
+			return nil, err
+		}
+		// The copy may be on its way to the pickup library; the hold is
+		// announced if a held transfer of it arrives and dropped when the
+		// reservation ends.
+		if data.CopyUID == "" {
+			return nil, nil
+		}
+		hold, err := holdFrom(data)
+		if err != nil {
+			return nil, err
+		}
+		return nil, tx.AddHold(ctx, hold)
+
+	case events.ReservationCancelled, events.ReservationReturned, events.ReservationExpired:
+		data, err := reservation(evt)
+		if err != nil {
+			return nil, err
+		}
+		uid, err := uuid.Parse(data.ReservationUID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformedEvent, evt.ID, err)
+		}
+		return nil, tx.DropHold(ctx, uid)
+
+	case events.ReservationDueSoon, events.ReservationOverdue:
+		data, err := reservation(evt)
+		if err != nil {
+			return nil, err
+		}
+		kind := models.KindDueSoon
+		if evt.Type == events.ReservationOverdue {
+			kind = models.KindOverdue
+		}
+		created, err := n.create(ctx, tx, evt.ID, kind, templates.Data{
+			Username:       data.Username,
+			ReservationUID: data.ReservationUID,
+			BookUID:        data.BookUID,
+			LibraryUID:     data.LibraryUID,
+			StartDate:      data.StartDate,
+			TillDate:       data.TillDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []models.Notification{*created}, nil
+
+	case events.TransferReceived:
+		var data events.TransferV1
+		if err := json.Unmarshal(evt.Data, &data); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformedEvent, evt.ID, err)
+		}
+		if !data.Hold {
+			return nil, nil
+		}
+		copyUID, err := uuid.Parse(data.CopyUID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformedEvent, evt.ID, err)
+		}
+		hold, err := tx.TakeHold(ctx, copyUID)
+		if errors.Is(err, repo.ErrHoldNotFound) {
+			// the reservation was cancelled while the copy was on its way
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		created, err := n.create(ctx, tx, evt.ID, models.KindHoldReady, templates.Data{
+			Username:       hold.Username,
+			ReservationUID: hold.ReservationUID.String(),
+			BookUID:        hold.BookUID.String(),
+			LibraryUID:     hold.LibraryUID.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []models.Notification{*created}, nil
+	}
+	return nil, nil
+}
+
+func (n *Notifier) create(ctx context.Context, tx repo.NotificationRepo, eventID uuid.UUID, kind string, data templates.Data) (*models.Notification, error) {
+	subject, body, err := n.render.Render(kind, data)
+	if err != nil {
+		return nil, err
+	}
+	return tx.CreateNotification(ctx, models.Notification{
+		NotificationUID: uuid.New(),
+		Username:        data.Username,
+		Kind:            kind,
+		Subject:         subject,
+		Body:            body,
+		EventID:         eventID,
+	})
+}
+
+func (n *Notifier) deliver(ctx context.Context, notification models.Notification) {
+	entry := logging.FromContext(ctx).WithFields(log.Fields{
+		"notification": notification.NotificationUID,
+		"username":     notification.Username,
+		"kind":         notification.Kind,
+	})
+	prefs, err := n.repo.GetPreferences(ctx, notification.Username)
+	if err != nil {
+		entry.WithError(err).Error("failed to load preferences, notification stays in the inbox only")
+		return
+	}
+	for _, name := range prefs.Channels {
+		ch, ok := n.channels[name]
+		if !ok {
+			entry.Warnf("channel %s is not enabled", name)
+			continue
+		}
+		sendErr := ch.Send(ctx, *prefs, notification)
+		if sendErr != nil {
+			entry.WithError(sendErr).Warnf("failed to deliver over %s", name)
+		}
+		if err := n.repo.RecordDelivery(ctx, notification.NotificationUID, name, sendErr); err != nil {
+			entry.WithError(err).Error("failed to record delivery")
+		}
+	}
+}
+
+func reservation(evt events.Envelope) (events.ReservationV1, error) {
+	var data events.ReservationV1
+	if err := json.Unmarshal(evt.Data, &data); err != nil {
+		return data, fmt.Errorf("%w: %s: %v", ErrMalformedEvent, evt.ID, err)
+	}
+	if data.Username == "" {
+		return data, fmt.Errorf("%w: %s has no username", ErrMalformedEvent, evt.ID)
+	}
+	return data, nil
+}
+
+func holdFrom(data events.ReservationV1) (models.Hold, error) {
+	var hold models.Hold
+	var err error
+	for _, f := range []struct {
+		dst *uuid.UUID
+		src string
+	}{
+		{&hold.CopyUID, data.CopyUID},
+		{&hold.ReservationUID, data.ReservationUID},
+		{&hold.BookUID, data.BookUID},
+		{&hold.LibraryUID, data.LibraryUID},
+	} {
+		if *f.dst, err = uuid.Parse(f.src); err != nil {
+			return hold, fmt.Errorf("%w: reservation %s: %v", ErrMalformedEvent, data.ReservationUID, err)
+		}
+	}
+	hold.Username = data.Username
+	return hold, nil
+}
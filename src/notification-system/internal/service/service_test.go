@@ -0,0 +1,239 @@
+package service_test
+
+import (
+	"context"
+	"contract/notification"
+	"encoding/json"
+	"errors"
+	"notification-system/internal/channels"
+	"notification-system/internal/models"
+	"notification-system/internal/repo"
+	"notification-system/internal/service"
+	"notification-system/internal/templates"
+	"notification-system/pkg/events"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) ClaimEvent(ctx context.Context, eventID uuid.UUID, eventType string) (bool, error) {
+	args := m.Called(ctx, eventID, eventType)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) AddHold(ctx context.Context, hold models.Hold) error {
+	return m.Called(ctx, hold).Error(0)
+}
+
+func (m *MockNotificationRepo) TakeHold(ctx context.Context, copyUID uuid.UUID) (*models.Hold, error) {
+	args := m.Called(ctx, copyUID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Hold), args.Error(1)
+}
+
+func (m *MockNotificationRepo) DropHold(ctx context.Context, reservationUID uuid.UUID) error {
+	return m.Called(ctx, reservationUID).Error(0)
+}
+
+// CreateNotification returns n as stored when the expectation returns no
+// notification and no error.
+func (m *MockNotificationRepo) CreateNotification(ctx context.Context, n models.Notification) (*models.Notification, error) {
+	args := m.Called(ctx, n)
+	if args.Get(0) == nil {
+		if args.Error(1) == nil {
+			return &n, nil
+		}
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) ListNotifications(ctx context.Context, username string, unreadOnly bool) ([]models.Notification, error) {
+	args := m.Called(ctx, username, unreadOnly)
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, uid uuid.UUID, username string) error {
+	return m.Called(ctx, uid, username).Error(0)
+}
+
+func (m *MockNotificationRepo) RecordDelivery(ctx context.Context, notificationUID uuid.UUID, channel string, deliveryErr error) error {
+	return m.Called(ctx, notificationUID, channel, deliveryErr).Error(0)
+}
+
+func (m *MockNotificationRepo) GetPreferences(ctx context.Context, username string) (*models.Preferences, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Preferences), args.Error(1)
+}
+
+func (m *MockNotificationRepo) SavePreferences(ctx context.Context, p models.Preferences) error {
+	return m.Called(ctx, p).Error(0)
+}
+
+// InTx runs fn against the mock itself, there is no transaction to fake.
+func (m *MockNotificationRepo) InTx(_ context.Context, fn func(tx repo.NotificationRepo) error) error {
+	return fn(m)
+}
+
+type fakeChannel struct {
+	err  error
+	sent []models.Notification
+}
+
+func (c *fakeChannel) Name() string { return "fake" }
+
+func (c *fakeChannel) Send(_ context.Context, _ models.Preferences, n models.Notification) error {
+	c.sent = append(c.sent, n)
+	return c.err
+}
+
+func envelope(t *testing.T, typ events.Type, data any) events.Envelope {
+	body, err := json.Marshal(data)
+	require.NoError(t, err)
+	return events.Envelope{ID: uuid.New(), Type: typ, Version: 1, Data: body}
+}
+
+func notifier(t *testing.T, r repo.NotificationRepo, ch channels.Channel) *service.Notifier {
+	render, err := templates.New()
+	require.NoError(t, err)
+	return service.NewNotifier(r, render, map[string]channels.Channel{ch.Name(): ch})
+}
+
+func TestNotifier_DueSoon(t *testing.T) {
+	mockRepo := new(MockNotificationRepo)
+	ch := &fakeChannel{err: errors.New("mailbox full")}
+	evt := envelope(t, events.ReservationDueSoon, events.ReservationV1{
+		ReservationUID: uuid.NewString(), Username: "alice", TillDate: "2025-03-11",
+	})
+
+	mockRepo.On("ClaimEvent", mock.Anything, evt.ID, "reservation.due_soon").Return(true, nil).Once()
+	mockRepo.On("ClaimEvent", mock.Anything, evt.ID, "reservation.due_soon").Return(false, nil).Once()
+	var stored *models.Notification
+	mockRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n models.Notification) bool {
+		return n.Username == "alice" && n.Kind == models.KindDueSoon && n.EventID == evt.ID
+	})).Run(func(args mock.Arguments) {
+		n := args.Get(1).(models.Notification)
+		stored = &n
+	}).Return(nil, nil).Once()
+	mockRepo.On("GetPreferences", mock.Anything, "alice").
+		Return(&models.Preferences{Username: "alice", Channels: []string{"fake", "pigeon"}}, nil)
+	mockRepo.On("RecordDelivery", mock.Anything, mock.Anything, "fake", ch.err).Return(nil).Once()
+
+	n := notifier(t, mockRepo, ch)
+	require.NoError(t, n.Handle(context.Background(), evt), "a failed delivery doesn't fail the event")
+	require.NoError(t, n.Handle(context.Background(), evt), "a redelivery is not an error")
+
+	require.NotNil(t, stored)
+	assert.Contains(t, stored.Subject, "2025-03-11")
+	assert.Len(t, ch.sent, 1, "the redelivery sends nothing")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotifier_HoldReady(t *testing.T) {
+	mockRepo := new(MockNotificationRepo)
+	ch := &fakeChannel{}
+	hold := models.Hold{
+		CopyUID:        uuid.New(),
+		ReservationUID: uuid.New(),
+		Username:       "bob",
+		BookUID:        uuid.New(),
+		LibraryUID:     uuid.New(),
+	}
+	reserved := envelope(t, events.ReservationCreated, events.ReservationV1{
+		ReservationUID: hold.ReservationUID.String(),
+		Username:       hold.Username,
+		BookUID:        hold.BookUID.String(),
+		LibraryUID:     hold.LibraryUID.String(),
+		CopyUID:        hold.CopyUID.String(),
+		Status:         "RENTED",
+	})
+	arrived := envelope(t, events.TransferReceived, events.TransferV1{CopyUID: hold.CopyUID.String(), Hold: true})
+
+	mockRepo.On("ClaimEvent", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockRepo.On("AddHold", mock.Anything, hold).Return(nil).Once()
+	mockRepo.On("TakeHold", mock.Anything, hold.CopyUID).Return(&hold, nil).Once()
+	mockRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n models.Notification) bool {
+		return n.Username == "bob" && n.Kind == models.KindHoldReady
+	})).Return(nil, nil).Once()
+	mockRepo.On("GetPreferences", mock.Anything, "bob").Return(&models.Preferences{Username: "bob"}, nil)
+
+	n := notifier(t, mockRepo, ch)
+	require.NoError(t, n.Handle(context.Background(), reserved))
+	require.NoError(t, n.Handle(context.Background(), arrived))
+
+	assert.Empty(t, ch.sent, "bob only wants the inbox")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotifier_TransferWithoutHold(t *testing.T) {
+	mockRepo := new(MockNotificationRepo)
+	evt := envelope(t, events.TransferReceived, events.TransferV1{CopyUID: uuid.NewString(), Hold: true})
+
+	mockRepo.On("ClaimEvent", mock.Anything, evt.ID, mock.Anything).Return(true, nil)
+	mockRepo.On("TakeHold", mock.Anything, mock.Anything).Return(nil, repo.ErrHoldNotFound)
+
+	assert.NoError(t, notifier(t, mockRepo, &fakeChannel{}).Handle(context.Background(), evt))
+	mockRepo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+}
+
+func TestNotifier_Malformed(t *testing.T) {
+	valid := envelope(t, events.ReservationOverdue, events.ReservationV1{Username: "alice"})
+
+	tests := []struct {
+		name   string
+		mutate func(evt *events.Envelope)
+	}{
+		{"unknown version", func(evt *events.Envelope) { evt.Version = 2 }},
+		{"bad payload", func(evt *events.Envelope) { evt.Data = json.RawMessage(`"nope"`) }},
+		{"no username", func(evt *events.Envelope) { evt.Data = json.RawMessage(`{}`) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockNotificationRepo)
+			mockRepo.On("ClaimEvent", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			evt := valid
+			tt.mutate(&evt)
+
+			err := notifier(t, mockRepo, &fakeChannel{}).Handle(context.Background(), evt)
+			assert.ErrorIs(t, err, service.ErrMalformedEvent)
+			mockRepo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	mockRepo := new(MockNotificationRepo)
+	svc := service.NewInboxService(mockRepo, []string{"email", "webhook"})
+
+	_, err := svc.UpdatePreferences(context.Background(), "alice", notification.Preferences{Channels: []notification.Channel{"pigeon"}})
+	assert.ErrorIs(t, err, service.ErrUnknownChannel)
+
+	for _, url := range []string{"http://93.184.216.34/hook", "https://169.254.169.254/latest", "https://localhost:8080/admin"} {
+		_, err = svc.UpdatePreferences(context.Background(), "alice", notification.Preferences{WebhookUrl: &url})
+		assert.ErrorIs(t, err, service.ErrInvalidWebhook, url)
+	}
+	mockRepo.AssertNotCalled(t, "SavePreferences", mock.Anything, mock.Anything)
+
+	mockRepo.On("SavePreferences", mock.Anything, models.Preferences{
+		Username: "alice",
+		Channels: []string{"email", "webhook"},
+	}).Return(nil).Once()
+	resp, err := svc.UpdatePreferences(context.Background(), "alice", notification.Preferences{
+		Channels: []notification.Channel{notification.Webhook, notification.Email, notification.Webhook},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []notification.Channel{notification.Email, notification.Webhook}, resp.Channels)
+	mockRepo.AssertExpectations(t)
+}
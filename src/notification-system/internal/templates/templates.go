@@ -0,0 +1,60 @@
+// Package templates renders notifications. Every kind has a file
+// <kind>.tmpl defining the <kind>.subject and <kind>.body templates.
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//go:embed *.tmpl
+var files embed.FS
+
+// Data is what a template can refer to. Fields that don't apply to a kind
+// are left empty.
+type Data struct {
+	Username       string
+	ReservationUID string
+	BookUID        string
+	LibraryUID     string
+	StartDate      string
+	TillDate       string
+}
+
+type Renderer struct {
+	tmpl *template.Template
+}
+
+func New() (*Renderer, error) {
+	tmpl, err := template.New("notifications").Option("missingkey=error").ParseFS(files, "*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+	return &Renderer{tmpl: tmpl}, nil
+}
+
+// Render returns the subject and body of a notification of kind.
+func (r *Renderer) Render(kind string, data Data) (string, string, error) {
+	subject, err := r.execute(kind+".subject", data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := r.execute(kind+".body", data)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject), strings.TrimSpace(body) + "\n", nil
+}
+
+func (r *Renderer) execute(name string, data Data) (string, error) {
+	if r.tmpl.Lookup(name) == nil {
+		return "", fmt.Errorf("no template %s", name)
+	}
+	var out strings.Builder
+	if err := r.tmpl.ExecuteTemplate(&out, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return out.String(), nil
+}
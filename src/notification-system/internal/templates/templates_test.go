@@ -0,0 +1,30 @@
+package templates_test
+
+import (
+	"notification-system/internal/models"
+	"notification-system/internal/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderEveryKind(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	data := templates.Data{Username: "alice", ReservationUID: "r-1", BookUID: "b-1", LibraryUID: "l-1", TillDate: "2025-03-11"}
+	for _, kind := range []string{models.KindDueSoon, models.KindOverdue, models.KindHoldReady} {
+		t.Run(kind, func(t *testing.T) {
+			subject, body, err := r.Render(kind, data)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, subject)
+			assert.NotContains(t, subject, "\n")
+			assert.Contains(t, body, "alice")
+			assert.Contains(t, body, "r-1")
+		})
+	}
+
+	_, _, err = r.Render("unknown", data)
+	assert.Error(t, err)
+}
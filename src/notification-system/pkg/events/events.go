@@ -0,0 +1,49 @@
+// Package events describes the domain events notification-system consumes:
+// reservation events from reservation-system and transfer events from
+// library-system. Both publish to the Exchange topic exchange with the
+// routing key <type>.v<version>.
+package events
+
+import "platform/events"
+
+const Exchange = events.Exchange
+
+type (
+	Type     = events.Type
+	Envelope = events.Envelope
+)
+
+const (
+	ReservationCreated   Type = "reservation.created"
+	ReservationReturned  Type = "reservation.returned"
+	ReservationExpired   Type = "reservation.expired"
+	ReservationCancelled Type = "reservation.cancelled"
+	ReservationDueSoon   Type = "reservation.due_soon"
+	ReservationOverdue   Type = "reservation.overdue"
+	TransferReceived     Type = "transfer.received"
+)
+
+// ReservationV1 is the payload of every reservation event at version 1.
+type ReservationV1 struct {
+	ReservationUID string `json:"reservationUid"`
+	Username       string `json:"username"`
+	BookUID        string `json:"bookUid"`
+	LibraryUID     string `json:"libraryUid"`
+	CopyUID        string `json:"copyUid,omitempty"`
+	Status         string `json:"status"`
+	StartDate      string `json:"startDate"`
+	TillDate       string `json:"tillDate"`
+	ReturnDate     string `json:"returnDate,omitempty"`
+	Condition      string `json:"condition,omitempty"`
+}
+
+// TransferV1 is the payload of transfer events at version 1.
+type TransferV1 struct {
+	TransferUID    string `json:"transferUid"`
+	CopyUID        string `json:"copyUid"`
+	BookUID        string `json:"bookUid"`
+	FromLibraryUID string `json:"fromLibraryUid"`
+	ToLibraryUID   string `json:"toLibraryUid"`
+	Hold           bool   `json:"hold"`
+	Status         string `json:"status"`
+}
@@ -0,0 +1,261 @@
+// Package amqptest runs a stand-in for RabbitMQ that speaks just enough AMQP
+// 0-9-1 for the services' clients: the handshake, channels, queue and
+// exchange declarations, qos, publishes and publisher confirms. Nothing is
+// routed or delivered; the broker records what it was sent.
+package amqptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Message is a publish the broker took.
+type Message struct {
+	Exchange   string
+	RoutingKey string
+	Body       []byte
+}
+
+type Broker struct {
+	ln net.Listener
+
+	// Reject makes the broker nack every publish on a confirming channel.
+	Reject atomic.Bool
+
+	mu        sync.Mutex
+	conns     []net.Conn
+	declared  []string
+	published []Message
+}
+
+// NewBroker listens on a local port until the test ends.
+func NewBroker(t testing.TB) *Broker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &Broker{ln: ln}
+	t.Cleanup(b.Close)
+	go b.accept()
+	return b
+}
+
+func (b *Broker) URL() string {
+	return "amqp://guest:guest@" + b.ln.Addr().String() + "/"
+}
+
+// Drop cuts every connection, as a broker restart would.
+func (b *Broker) Drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+// Close stops listening and drops every connection: the broker is gone.
+func (b *Broker) Close() {
+	b.ln.Close()
+	b.Drop()
+}
+
+// Declared returns the queues and exchanges declared so far, in order.
+func (b *Broker) Declared() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.declared)
+}
+
+// Published returns the messages taken so far, in order.
+func (b *Broker) Published() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.published)
+}
+
+func (b *Broker) accept() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.mu.Unlock()
+		go b.serve(conn)
+	}
+}
+
+const (
+	frameMethod = 1
+	frameHeader = 2
+	frameBody   = 3
+	frameEnd    = 0xCE
+)
+
+// channel is the state of an open channel: whether it confirms publishes
+// and the publish whose content is still arriving.
+type channel struct {
+	confirming bool
+	sent       uint64
+	pending    *Message
+	size       int
+}
+
+func (b *Broker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	if _, err := io.ReadFull(r, make([]byte, 8)); err != nil { // protocol header
+		return
+	}
+	// version 0-9, no server properties, PLAIN only
+	writeMethod(conn, 0, 10, 10, []byte{0, 9}, u32(0), longstr("PLAIN"), longstr("en_US"))
+
+	channels := make(map[uint16]*channel)
+	for {
+		typ, id, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		ch := channels[id]
+		switch typ {
+		case frameHeader:
+			if ch != nil && ch.pending != nil {
+				ch.size = int(binary.BigEndian.Uint64(payload[4:]))
+				b.complete(conn, id, ch)
+			}
+			continue
+		case frameBody:
+			if ch != nil && ch.pending != nil {
+				ch.pending.Body = append(ch.pending.Body, payload...)
+				b.complete(conn, id, ch)
+			}
+			continue
+		case frameMethod:
+		default: // heartbeats
+			continue
+		}
+
+		class, method, args := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:]), payload[4:]
+		switch [2]uint16{class, method} {
+		case [2]uint16{10, 11}: // connection.start-ok
+			writeMethod(conn, 0, 10, 30, u16(0), u32(131072), u16(0))
+		case [2]uint16{10, 40}: // connection.open
+			writeMethod(conn, 0, 10, 41, shortstr(""))
+		case [2]uint16{10, 50}: // connection.close
+			writeMethod(conn, 0, 10, 51)
+			return
+		case [2]uint16{20, 10}: // channel.open
+			channels[id] = &channel{}
+			writeMethod(conn, id, 20, 11, longstr(""))
+		case [2]uint16{20, 40}: // channel.close
+			delete(channels, id)
+			writeMethod(conn, id, 20, 41)
+		case [2]uint16{40, 10}: // exchange.declare
+			exchange, _ := readShortstr(args[2:])
+			b.declare(exchange)
+			writeMethod(conn, id, 40, 11)
+		case [2]uint16{50, 10}: // queue.declare
+			queue, _ := readShortstr(args[2:])
+			b.declare(queue)
+			writeMethod(conn, id, 50, 11, shortstr(queue), u32(0), u32(0))
+		case [2]uint16{60, 10}: // basic.qos
+			writeMethod(conn, id, 60, 11)
+		case [2]uint16{60, 40}: // basic.publish
+			exchange, rest := readShortstr(args[2:])
+			key, _ := readShortstr(rest)
+			ch.pending = &Message{Exchange: exchange, RoutingKey: key}
+		case [2]uint16{85, 10}: // confirm.select
+			ch.confirming = true
+			writeMethod(conn, id, 85, 11)
+		}
+	}
+}
+
+// complete takes the publish in hand once its body has arrived.
+func (b *Broker) complete(conn net.Conn, id uint16, ch *channel) {
+	if len(ch.pending.Body) < ch.size {
+		return
+	}
+	msg := *ch.pending
+	ch.pending, ch.size = nil, 0
+	if !ch.confirming {
+		b.take(msg)
+		return
+	}
+
+	ch.sent++
+	if b.Reject.Load() {
+		writeMethod(conn, id, 60, 120, u64(ch.sent), []byte{0}) // basic.nack
+		return
+	}
+	b.take(msg)
+	writeMethod(conn, id, 60, 80, u64(ch.sent), []byte{0}) // basic.ack
+}
+
+func (b *Broker) declare(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.declared = append(b.declared, name)
+}
+
+func (b *Broker) take(msg Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, msg)
+}
+
+func readFrame(r *bufio.Reader) (typ byte, channel uint16, payload []byte, err error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, nil, err
+	}
+	payload = make([]byte, binary.BigEndian.Uint32(header[3:])+1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, 0, nil, err
+	}
+	if payload[len(payload)-1] != frameEnd {
+		return 0, 0, nil, errors.New("bad frame end")
+	}
+	return header[0], binary.BigEndian.Uint16(header[1:]), payload[:len(payload)-1], nil
+}
+
+func writeMethod(w io.Writer, channel, class, method uint16, args ...[]byte) {
+	var payload bytes.Buffer
+	payload.Write(u16(class))
+	payload.Write(u16(method))
+	for _, arg := range args {
+		payload.Write(arg)
+	}
+	var frame bytes.Buffer
+	frame.WriteByte(frameMethod)
+	frame.Write(u16(channel))
+	frame.Write(u32(uint32(payload.Len())))
+	frame.Write(payload.Bytes())
+	frame.WriteByte(frameEnd)
+	w.Write(frame.Bytes())
+}
+
+func u16(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+
+func u32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+func u64(v uint64) []byte { return binary.BigEndian.AppendUint64(nil, v) }
+
+func shortstr(s string) []byte { return append([]byte{byte(len(s))}, s...) }
+
+func longstr(s string) []byte { return append(u32(uint32(len(s))), s...) }
+
+func readShortstr(b []byte) (string, []byte) {
+	n := int(b[0])
+	return string(b[1 : 1+n]), b[1+n:]
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"net/http"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	userHeader  = "X-User-Name"
+	usernameKey = "username"
+)
+
+// Identity must run after AuthMiddleware: it resolves the user the request
+// acts for. That is the sub claim of the token; only callers with the
+// service role may act for someone else by naming them in X-User-Name. Any
+// other caller naming a different user gets 403.
+func Identity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.MustGet("claims").(jwt.MapClaims)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token claims")
+			return
+		}
+
+		sub, _ := claims["sub"].(string)
+		header := c.GetHeader(userHeader)
+
+		username := sub
+		switch {
+		case header == "" || header == sub:
+		case Roles(claims)[Service]:
+			username = header
+		default:
+			problem.Abort(c, http.StatusForbidden, problem.Forbidden, userHeader+" does not match the token subject")
+			return
+		}
+
+		if username == "" {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "sub claim missing")
+			return
+		}
+
+		c.Set(usernameKey, username)
+		c.Next()
+	}
+}
+
+// Username returns the user resolved by Identity.
+func Username(c *gin.Context) string {
+	return c.GetString(usernameKey)
+}
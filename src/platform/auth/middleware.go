@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+	"platform/problem"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func AuthMiddleware(v *Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "authorization header required")
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "authorization header format must be Bearer {token}")
+			return
+		}
+
+		claims, err := v.Verify(parts[1])
+		if err != nil {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token: "+err.Error())
+			return
+		}
+
+		c.Set("token", authHeader)
+		c.Set("claims", claims)
+
+		c.Next()
+	}
+}
+
+// Subject returns the sub claim of the token that authenticated the request.
+func Subject(c *gin.Context) string {
+	claims, ok := c.MustGet("claims").(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return sub
+}
@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"platform/auth"
+	"platform/auth/authtest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(t *testing.T, minter *authtest.Minter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := auth.NewVerifier(minter.Config())
+	require.NoError(t, err)
+
+	r := gin.New()
+	g := r.Group("", auth.AuthMiddleware(verifier))
+	g.GET("/open", func(c *gin.Context) { c.Status(http.StatusOK) })
+	g.PUT("/staff", auth.Require(auth.Librarian, auth.Service), func(c *gin.Context) { c.Status(http.StatusOK) })
+	g.PUT("/patrons", auth.Require(auth.Patron), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func do(r *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	minter := authtest.NewMinter()
+	r := newRouter(t, minter)
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"valid", minter.Token(jwt.MapClaims{"sub": "user"}), http.StatusOK},
+		{"expired", minter.Token(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}), http.StatusUnauthorized},
+		{"wrong issuer", minter.Token(jwt.MapClaims{"iss": "https://evil.test/"}), http.StatusUnauthorized},
+		{"wrong audience", minter.Token(jwt.MapClaims{"aud": "other"}), http.StatusUnauthorized},
+		{"no audience", minter.Token(jwt.MapClaims{"aud": nil}), http.StatusUnauthorized},
+		{"foreign key", authtest.NewMinter().Token(jwt.MapClaims{}), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, do(r, http.MethodGet, "/open", tt.token))
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	minter := authtest.NewMinter()
+	r := newRouter(t, minter)
+
+	patron := minter.Token(jwt.MapClaims{"sub": "user"})
+	librarian := minter.Token(jwt.MapClaims{"sub": "staff", "roles": []string{"librarian"}})
+	service := minter.Token(jwt.MapClaims{"sub": "gateway@clients", "gty": "client-credentials", "permissions": []string{"service"}})
+
+	assert.Equal(t, http.StatusForbidden, do(r, http.MethodPut, "/staff", patron))
+	assert.Equal(t, http.StatusOK, do(r, http.MethodPut, "/staff", librarian))
+	assert.Equal(t, http.StatusOK, do(r, http.MethodPut, "/staff", service))
+}
+
+func TestIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	minter := authtest.NewMinter()
+	verifier, err := auth.NewVerifier(minter.Config())
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.GET("/me", auth.AuthMiddleware(verifier), auth.Identity(), func(c *gin.Context) {
+		c.String(http.StatusOK, auth.Username(c))
+	})
+
+	call := func(token, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", token)
+		if header != "" {
+			req.Header.Set("X-User-Name", header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	patron := minter.Token(jwt.MapClaims{"sub": "alice"})
+	service := minter.Token(jwt.MapClaims{"sub": "gateway@clients", "gty": "client-credentials", "permissions": []string{"service"}})
+
+	w := call(patron, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", w.Body.String())
+
+	w = call(patron, "alice")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = call(patron, "bob")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = call(service, "bob")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bob", w.Body.String())
+
+	// a client of the tenant without the service role acts for nobody else
+	client := minter.Token(jwt.MapClaims{"sub": "other@clients", "gty": "client-credentials"})
+	w = call(client, "bob")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"net/http"
+	"platform/problem"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Role string
+
+const (
+	Patron    Role = "patron"
+	Librarian Role = "librarian"
+	Admin     Role = "admin"
+	// Service is held by other services of the system calling on behalf of
+	// a user, e.g. the gateway. It lets the caller act as any user, so it is
+	// only ever granted explicitly.
+	Service Role = "service"
+	// Integrator is held by partner systems that subscribe to webhooks. They
+	// call with client credentials as well but are not services of the
+	// system.
+	Integrator Role = "integrator"
+)
+
+const (
+	rolesClaim       = "roles"
+	permissionsClaim = "permissions"
+	grantTypeClaim   = "gty"
+	clientCredsGrant = "client-credentials"
+)
+
+// implied lists the roles a role includes on top of itself.
+var implied = map[Role][]Role{
+	Admin:     {Librarian, Patron},
+	Librarian: {Patron},
+}
+
+// Roles collects the roles carried by the token. Role names are accepted
+// both in the roles claim and as entries of the permissions claim, as the
+// latter is what Auth0 RBAC emits. A user token with no roles is a patron;
+// a token obtained with client credentials holds only the roles it carries,
+// so a client registered for something else holds none.
+func Roles(claims jwt.MapClaims) map[Role]bool {
+	held := make(map[Role]bool)
+	add := func(r Role) {
+		held[r] = true
+		for _, sub := range implied[r] {
+			held[sub] = true
+		}
+	}
+
+	for _, claim := range []string{rolesClaim, permissionsClaim} {
+		values, _ := claims[claim].([]interface{})
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				add(Role(strings.ToLower(s)))
+			}
+		}
+	}
+	if gty, _ := claims[grantTypeClaim].(string); len(held) == 0 && gty != clientCredsGrant {
+		add(Patron)
+	}
+	return held
+}
+
+// Require must run after AuthMiddleware: it lets the request through when
+// the token holds at least one of the given roles and answers 403 otherwise.
+func Require(roles ...Role) gin.HandlerFunc {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, string(r))
+	}
+	reason := "requires one of roles: " + strings.Join(names, ", ")
+
+	return func(c *gin.Context) {
+		claims, ok := c.MustGet("claims").(jwt.MapClaims)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, problem.Unauthorized, "invalid token claims")
+			return
+		}
+
+		held := Roles(claims)
+		for _, r := range roles {
+			if held[r] {
+				c.Next()
+				return
+			}
+		}
+
+		problem.Abort(c, http.StatusForbidden, problem.Forbidden, reason)
+	}
+}
+
+// HasRole reports whether the token that authenticated the request holds
+// the role.
+func HasRole(c *gin.Context, role Role) bool {
+	claims, ok := c.MustGet("claims").(jwt.MapClaims)
+	return ok && Roles(claims)[role]
+}
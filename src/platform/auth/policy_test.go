@@ -0,0 +1,57 @@
+package auth_test
+
+import (
+	"net/http"
+	"platform/auth"
+	"platform/auth/authtest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   []auth.Role
+	}{
+		{"no roles", jwt.MapClaims{"sub": "user"}, []auth.Role{auth.Patron}},
+		{"roles claim", jwt.MapClaims{"roles": []interface{}{"Librarian"}}, []auth.Role{auth.Librarian, auth.Patron}},
+		{"permissions claim", jwt.MapClaims{"permissions": []interface{}{"admin"}}, []auth.Role{auth.Admin, auth.Librarian, auth.Patron}},
+		{"service", jwt.MapClaims{"gty": "client-credentials", "permissions": []interface{}{"service"}}, []auth.Role{auth.Service}},
+		{"client credentials", jwt.MapClaims{"gty": "client-credentials"}, nil},
+		{"integrator", jwt.MapClaims{"gty": "client-credentials", "permissions": []interface{}{"integrator"}}, []auth.Role{auth.Integrator}},
+		{"unknown role", jwt.MapClaims{"roles": []interface{}{"auditor"}}, []auth.Role{"auditor"}},
+		{"not a list", jwt.MapClaims{"roles": "admin"}, []auth.Role{auth.Patron}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[auth.Role]bool)
+			for _, r := range tt.want {
+				want[r] = true
+			}
+			assert.Equal(t, want, auth.Roles(tt.claims))
+		})
+	}
+}
+
+func TestRequire_Implied(t *testing.T) {
+	minter := authtest.NewMinter()
+	r := newRouter(t, minter)
+
+	admin := minter.Token(jwt.MapClaims{"sub": "boss", "permissions": []string{"admin"}})
+	service := minter.Token(jwt.MapClaims{"sub": "gateway@clients", "gty": "client-credentials", "permissions": []string{"service"}})
+	client := minter.Token(jwt.MapClaims{"sub": "other@clients", "gty": "client-credentials"})
+	integrator := minter.Token(jwt.MapClaims{"sub": "portal@clients", "gty": "client-credentials", "permissions": []string{"integrator"}})
+
+	// admins are librarians too, services are not patrons and integrators
+	// are not services
+	assert.Equal(t, http.StatusOK, do(r, http.MethodPut, "/staff", admin))
+	assert.Equal(t, http.StatusOK, do(r, http.MethodGet, "/open", service))
+	assert.Equal(t, http.StatusForbidden, do(r, http.MethodPut, "/patrons", service))
+	assert.Equal(t, http.StatusForbidden, do(r, http.MethodPut, "/staff", integrator))
+
+	// other clients of the tenant are nobody
+	assert.Equal(t, http.StatusForbidden, do(r, http.MethodPut, "/patrons", client))
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// expiryLeeway makes the cached token be renewed a bit before it expires so
+// that it does not run out in flight.
+const expiryLeeway = 30 * time.Second
+
+// ServiceToken obtains a token for the gateway itself with the OAuth2 client
+// credentials grant. The client must be granted the service permission in
+// the tenant, which the backends require for the calls that move stock,
+// reservations and rating.
+type ServiceToken struct {
+	TokenURL     string `envconfig:"TOKEN_URL" required:"true"`
+	ClientID     string `envconfig:"CLIENT_ID" required:"true"`
+	ClientSecret string `envconfig:"CLIENT_SECRET" required:"true"`
+	Audience     string `envconfig:"AUDIENCE"`
+
+	httpClient *http.Client
+	mu         sync.Mutex
+	token      string
+	expiresAt  time.Time
+}
+
+var ErrNoServiceCredentials = errors.New("no service client credentials configured")
+
+// Validate reports whether the client credentials are there at all, so that
+// a service relying on them fails at startup rather than on the first call.
+func (t *ServiceToken) Validate() error {
+	if t == nil || t.TokenURL == "" || t.ClientID == "" || t.ClientSecret == "" {
+		return ErrNoServiceCredentials
+	}
+	return nil
+}
+
+// Authorization returns the Authorization header value to use for a
+// privileged call. It fails when no token can be had: the caller's own
+// token is never sent in its place.
+func (t *ServiceToken) Authorization() (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" || time.Now().After(t.expiresAt) {
+		if err := t.refresh(); err != nil {
+			return "", fmt.Errorf("failed to obtain service token: %w", err)
+		}
+	}
+	return "Bearer " + t.token, nil
+}
+
+func (t *ServiceToken) refresh() error {
+	if t.httpClient == nil {
+		t.httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {t.ClientID},
+		"client_secret": {t.ClientSecret},
+	}
+	if t.Audience != "" {
+		form.Set("audience", t.Audience)
+	}
+
+	resp, err := t.httpClient.PostForm(t.TokenURL, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if body.AccessToken == "" {
+		return fmt.Errorf("token endpoint returned no access token")
+	}
+
+	t.token = body.AccessToken
+	t.expiresAt = time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - expiryLeeway)
+	return nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenEndpoint(t *testing.T, status int, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "gateway", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		assert.Equal(t, "api", r.PostForm.Get("audience"))
+
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestServiceToken_Authorization(t *testing.T) {
+	srv, calls := tokenEndpoint(t, http.StatusOK, 3600)
+	st := &ServiceToken{TokenURL: srv.URL, ClientID: "gateway", ClientSecret: "secret", Audience: "api"}
+
+	header, err := st.Authorization()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", header)
+
+	// the token is kept until shortly before it expires
+	header, err = st.Authorization()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", header)
+	assert.EqualValues(t, 1, calls.Load())
+
+	st.expiresAt = time.Now().Add(-time.Second)
+	header, err = st.Authorization()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", header)
+}
+
+func TestServiceToken_Errors(t *testing.T) {
+	failing, _ := tokenEndpoint(t, http.StatusUnauthorized, 3600)
+
+	tests := []struct {
+		name string
+		st   *ServiceToken
+		want error
+	}{
+		{"nil", nil, ErrNoServiceCredentials},
+		{"no client id", &ServiceToken{TokenURL: failing.URL, ClientSecret: "secret"}, ErrNoServiceCredentials},
+		{"no secret", &ServiceToken{TokenURL: failing.URL, ClientID: "gateway"}, ErrNoServiceCredentials},
+		{"refused", &ServiceToken{TokenURL: failing.URL, ClientID: "gateway", ClientSecret: "secret", Audience: "api"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := tt.st.Authorization()
+			require.Error(t, err)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+			}
+			assert.Empty(t, header)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config selects where the token signing keys come from and what the
+// tokens must be issued for. The issuer and the audience are required. The
+// first of PublicKey, JWKSFile and JWKSURL that is set is used.
+type Config struct {
+	Issuer          string        `envconfig:"ISSUER" required:"true"`
+	Audience        string        `envconfig:"AUDIENCE" required:"true"`
+	JWKSURL         string        `envconfig:"JWKS_URL"`
+	JWKSFile        string        `envconfig:"JWKS_FILE"`
+	PublicKey       string        `envconfig:"PUBLIC_KEY"`
+	RefreshInterval time.Duration `envconfig:"JWKS_REFRESH_INTERVAL" default:"1h"`
+}
+
+var (
+	ErrNoKeySource = errors.New("no token key source configured")
+	ErrNoIssuer    = errors.New("no token issuer configured")
+	ErrNoAudience  = errors.New("no token audience configured")
+	ErrMissingExp  = errors.New("token has no expiration")
+	ErrBadIssuer   = errors.New("token issuer mismatch")
+	ErrBadAudience = errors.New("token audience mismatch")
+)
+
+type Verifier struct {
+	keyfunc  jwt.Keyfunc
+	parser   *jwt.Parser
+	issuer   string
+	audience string
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.Issuer == "" {
+		return nil, ErrNoIssuer
+	}
+	if cfg.Audience == "" {
+		return nil, ErrNoAudience
+	}
+
+	keyfunc, err := newKeyfunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{
+		keyfunc:  keyfunc,
+		parser:   jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"})),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}, nil
+}
+
+func newKeyfunc(cfg Config) (jwt.Keyfunc, error) {
+	switch {
+	case cfg.PublicKey != "":
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return func(*jwt.Token) (interface{}, error) { return key, nil }, nil
+	case cfg.JWKSFile != "":
+		raw, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		jwks, err := keyfunc.NewJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+		}
+		return jwks.Keyfunc, nil
+	case cfg.JWKSURL != "":
+		jwks, err := keyfunc.Get(cfg.JWKSURL, keyfunc.Options{
+			RefreshInterval: cfg.RefreshInterval,
+			RefreshErrorHandler: func(err error) {
+				log.WithError(err).Warn("failed to refresh JWKS")
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get JWKS from %s: %w", cfg.JWKSURL, err)
+		}
+		return jwks.Keyfunc, nil
+	default:
+		return nil, ErrNoKeySource
+	}
+}
+
+// Verify checks the signature and the exp, iss and aud claims of a raw
+// token and returns its claims.
+func (v *Verifier) Verify(raw string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(raw, claims, v.keyfunc); err != nil {
+		return nil, err
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, ErrMissingExp
+	}
+	if !claims.VerifyIssuer(v.issuer, true) {
+		return nil, ErrBadIssuer
+	}
+	if !claims.VerifyAudience(v.audience, true) {
+		return nil, ErrBadAudience
+	}
+	return claims, nil
+}
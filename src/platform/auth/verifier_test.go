@@ -0,0 +1,30 @@
+package auth_test
+
+import (
+	"platform/auth"
+	"platform/auth/authtest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	key := authtest.NewMinter().PublicKeyPEM()
+
+	tests := []struct {
+		name string
+		cfg  auth.Config
+		want error
+	}{
+		{"no issuer", auth.Config{Audience: "api", PublicKey: key}, auth.ErrNoIssuer},
+		{"no audience", auth.Config{Issuer: "https://issuer.test/", PublicKey: key}, auth.ErrNoAudience},
+		{"no key source", auth.Config{Issuer: "https://issuer.test/", Audience: "api"}, auth.ErrNoKeySource},
+		{"complete", auth.Config{Issuer: "https://issuer.test/", Audience: "api", PublicKey: key}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.NewVerifier(tt.cfg)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
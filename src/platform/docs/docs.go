@@ -0,0 +1,119 @@
+// Package docs serves the OpenAPI spec of a service on /docs/swagger.json
+// and a Swagger UI for it on /docs/. Both come from the binary, so the docs
+// work without the source tree or a CDN at hand.
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerui "github.com/swaggo/files/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const Path = "/docs"
+
+//go:embed index.html
+var index []byte
+
+// assets are the files of Swagger UI that index.html loads.
+var assets = []string{
+	"swagger-ui.css",
+	"swagger-ui-bundle.js",
+	"swagger-ui-standalone-preset.js",
+	"favicon-16x16.png",
+	"favicon-32x32.png",
+}
+
+// JSON turns a spec written in YAML, or in JSON already, into JSON.
+func JSON(spec []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	return json.Marshal(stringKeys(doc))
+}
+
+// stringKeys turns the maps YAML decodes with non-string keys, such as
+// unquoted status codes, into maps JSON can hold.
+func stringKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = stringKeys(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Static serves spec, which must be JSON, as it is.
+func Static(spec []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+// Register serves the spec with spec and Swagger UI next to it.
+func Register(r gin.IRouter, spec gin.HandlerFunc) {
+	routes := r.Group(Path)
+	routes.GET("/swagger.json", spec)
+	routes.GET("/", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", index)
+	})
+	for _, name := range assets {
+		routes.StaticFileFS("/"+name, name, http.FS(swaggerui.FS))
+	}
+}
+
+// Undocumented lists the routes under prefix that spec, in JSON, has no
+// operation for. Tests use it to keep a spec in step with the router.
+func Undocumented(spec []byte, routes gin.RoutesInfo, prefix string) ([]string, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("decode spec: %w", err)
+	}
+
+	var missing []string
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix) {
+			continue
+		}
+		if _, ok := doc.Paths[template(route.Path)][strings.ToLower(route.Method)]; !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// template writes a gin path the way OpenAPI does: /books/:uid becomes
+// /books/{uid}.
+func template(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
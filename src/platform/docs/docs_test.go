@@ -0,0 +1,65 @@
+package docs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"platform/docs"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.1
+paths:
+  /api/v1/books/{uid}:
+    get:
+      responses:
+        200:
+          description: The book
+`
+
+func get(r http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body, err := docs.JSON([]byte(spec))
+	require.NoError(t, err)
+
+	r := gin.New()
+	docs.Register(r, docs.Static(body))
+
+	w := get(r, docs.Path+"/swagger.json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"openapi":"3.0.1","paths":{"/api/v1/books/{uid}":{"get":{"responses":{"200":{"description":"The book"}}}}}}`, w.Body.String())
+
+	w = get(r, docs.Path+"/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `src="swagger-ui-bundle.js"`)
+
+	for _, asset := range []string{"/swagger-ui-bundle.js", "/swagger-ui.css"} {
+		w = get(r, docs.Path+asset)
+		assert.Equal(t, http.StatusOK, w.Code, asset)
+		assert.NotZero(t, w.Body.Len(), asset)
+	}
+}
+
+func TestUndocumented(t *testing.T) {
+	body, err := docs.JSON([]byte(spec))
+	require.NoError(t, err)
+
+	missing, err := docs.Undocumented(body, gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/api/v1/books/:uid"},
+		{Method: http.MethodPut, Path: "/api/v1/books/:uid"},
+		{Method: http.MethodGet, Path: "/api/v1/copies/:uid"},
+		{Method: http.MethodGet, Path: "/manage/health"},
+	}, "/api/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /api/v1/copies/:uid", "PUT /api/v1/books/:uid"}, missing)
+}
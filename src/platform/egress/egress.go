@@ -0,0 +1,84 @@
+// Package egress guards the requests sent to URLs users choose, such as
+// webhooks, so that they cannot reach the services next to the sender.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenURL is returned for a URL that isn't https or points into a
+// private, loopback or link-local network.
+var ErrForbiddenURL = errors.New("url not allowed")
+
+// Client returns a client that only dials public addresses and only
+// follows redirects to https.
+func Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+	return &http.Client{
+		Timeout: timeout,
+		// no proxy: the address checked is the address dialled
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: timeout},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return CheckScheme(req.URL)
+		},
+	}
+}
+
+// CheckURL resolves the host of raw and fails with ErrForbiddenURL unless
+// raw is https and every address of the host is public. Client repeats the
+// check at dial time, so a host that resolves elsewhere later is caught
+// too.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenURL, err)
+	}
+	if err := CheckScheme(u); err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrForbiddenURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if err := checkAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckScheme fails with ErrForbiddenURL unless u is an https URL with a
+// host.
+func CheckScheme(u *url.URL) error {
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: only https urls are accepted", ErrForbiddenURL)
+	}
+	return nil
+}
+
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenURL, addr)
+	}
+	return nil
+}
+
+// dialPublic refuses connections to anything but public addresses, whatever
+// the host name resolved to.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenURL, err)
+	}
+	return checkAddr(ap.Addr())
+}
@@ -0,0 +1,56 @@
+package egress_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"platform/egress"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"https://[2606:2800:220:1:248:1893:25c8:1946]/hook", true},
+		{"http://93.184.216.34/hook", false},
+		{"ftp://93.184.216.34/hook", false},
+		{"https:///hook", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://localhost/hook", false},
+		{"https://10.0.0.7/hook", false},
+		{"https://192.168.1.1/hook", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://[::1]/hook", false},
+		{"https://[fe80::1]/hook", false},
+		{"https://[::ffff:127.0.0.1]/hook", false},
+		{"https://0.0.0.0/hook", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := egress.CheckURL(context.Background(), tt.url)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, egress.ErrForbiddenURL)
+			}
+		})
+	}
+}
+
+func TestClient_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	_, err := egress.Client(time.Second).Get(srv.URL)
+	assert.ErrorIs(t, err, egress.ErrForbiddenURL)
+	assert.False(t, called)
+}
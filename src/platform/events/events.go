@@ -0,0 +1,70 @@
+// Package events defines the envelope every domain event travels in.
+//
+// Events go to the Exchange topic exchange with the routing key
+// <type>.v<version>, e.g. reservation.returned.v1, so a consumer binds to the
+// versions it understands. A breaking change to a payload gets a new version
+// published next to the old one until its consumers move over. The event
+// types and their payloads belong to the service that publishes them.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const Exchange = "library.events"
+
+type Type string
+
+// Envelope is the schema every event shares. OrderingKey names the entity
+// the event is about and Sequence grows by one with each event for that key,
+// so a consumer can tell a stale or duplicated delivery from a new one.
+type Envelope struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          Type            `json:"type"`
+	Version       int             `json:"version"`
+	Source        string          `json:"source"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	CorrelationID string          `json:"correlationId,omitempty"`
+	OrderingKey   string          `json:"orderingKey"`
+	Sequence      int64           `json:"sequence"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func (e Envelope) RoutingKey() string {
+	return fmt.Sprintf("%s.v%d", e.Type, e.Version)
+}
+
+// New wraps data from source at version 1. The sequence is assigned when the
+// event is stored.
+func New(ctx context.Context, source string, typ Type, orderingKey string, data any) (Envelope, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to encode %s: %w", typ, err)
+	}
+	return Envelope{
+		ID:            uuid.New(),
+		Type:          typ,
+		Version:       1,
+		Source:        source,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: CorrelationID(ctx),
+		OrderingKey:   orderingKey,
+		Data:          body,
+	}, nil
+}
+
+type correlationKey struct{}
+
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
@@ -0,0 +1,61 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"platform/events"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	ctx := events.WithCorrelationID(context.Background(), "corr-1")
+	before := time.Now().UTC()
+
+	evt, err := events.New(ctx, "reservation-system", "reservation.created", "r1", map[string]string{"username": "alice"})
+	require.NoError(t, err)
+
+	assert.NotZero(t, evt.ID)
+	assert.Equal(t, events.Type("reservation.created"), evt.Type)
+	assert.Equal(t, 1, evt.Version)
+	assert.Equal(t, "reservation-system", evt.Source)
+	assert.Equal(t, "corr-1", evt.CorrelationID)
+	assert.Equal(t, "r1", evt.OrderingKey)
+	assert.Zero(t, evt.Sequence, "the sequence is assigned when the event is stored")
+	assert.False(t, evt.OccurredAt.Before(before))
+	assert.JSONEq(t, `{"username":"alice"}`, string(evt.Data))
+	assert.Equal(t, "reservation.created.v1", evt.RoutingKey())
+
+	other, err := events.New(ctx, "reservation-system", "reservation.created", "r1", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, evt.ID, other.ID)
+}
+
+func TestNew_Unencodable(t *testing.T) {
+	_, err := events.New(context.Background(), "test", "reservation.created", "r1", make(chan int))
+	assert.ErrorContains(t, err, "reservation.created")
+}
+
+func TestEnvelope_JSON(t *testing.T) {
+	evt, err := events.New(context.Background(), "test", "transfer.received", "c1", map[string]bool{"hold": true})
+	require.NoError(t, err)
+	evt.Version, evt.Sequence = 2, 7
+
+	body, err := json.Marshal(evt)
+	require.NoError(t, err)
+	var decoded events.Envelope
+	require.NoError(t, json.Unmarshal(body, &decoded))
+
+	assert.Equal(t, evt.ID, decoded.ID)
+	assert.Equal(t, evt.Sequence, decoded.Sequence)
+	assert.True(t, evt.OccurredAt.Equal(decoded.OccurredAt))
+	assert.Equal(t, "transfer.received.v2", decoded.RoutingKey())
+	assert.NotContains(t, string(body), "correlationId", "an empty correlation id is left out")
+}
+
+func TestCorrelationID_Unset(t *testing.T) {
+	assert.Empty(t, events.CorrelationID(context.Background()))
+}
@@ -0,0 +1,81 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"platform/health"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func pass(context.Context) error { return nil }
+
+func fail(context.Context) error { return errors.New("boom") }
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical health.Check
+		optional health.Check
+		want     string
+	}{
+		{"all up", pass, pass, health.StatusUp},
+		{"optional down", pass, fail, health.StatusDegraded},
+		{"critical down", fail, pass, health.StatusDown},
+		{"critical times out", hang, pass, health.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := health.New(50 * time.Millisecond)
+			h.Critical("db", tt.critical)
+			h.Optional("downstream", tt.optional)
+
+			report := h.Run(context.Background())
+			assert.Equal(t, tt.want, report.Status)
+			assert.Len(t, report.Checks, 2)
+		})
+	}
+}
+
+func TestChecker_ReportsError(t *testing.T) {
+	h := health.New(time.Second)
+	h.Critical("db", fail)
+
+	report := h.Run(context.Background())
+	assert.Equal(t, health.StatusDown, report.Checks["db"].Status)
+	assert.Equal(t, "boom", report.Checks["db"].Error)
+}
+
+func TestRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := health.New(time.Second)
+	h.Critical("db", fail)
+	r := gin.New()
+	h.Register(r)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{health.LivePath, http.StatusOK},
+		{health.ReadyPath, http.StatusServiceUnavailable},
+		{health.Path, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	LivePath  = "/manage/health/live"
+	ReadyPath = "/manage/health/ready"
+	// Path served readiness before live and ready were split, and callers
+	// still probe it.
+	Path = "/manage/health"
+)
+
+// Register serves the probes on r: live answers as long as the process
+// does, ready runs the checks and answers 503 when the report is DOWN.
+func (h *Checker) Register(r gin.IRoutes) {
+	r.GET(LivePath, live)
+	r.GET(ReadyPath, h.ready)
+	r.GET(Path, h.ready)
+}
+
+func live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusUp})
+}
+
+func (h *Checker) ready(c *gin.Context) {
+	report := h.Run(c)
+	status := http.StatusOK
+	if report.Status == StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,78 @@
+// Package lifecycle runs the HTTP server of a service and stops it without
+// dropping the requests in flight.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Shutdown tunes how the server stops once it is asked to.
+type Shutdown struct {
+	// DrainDelay is how long the server keeps serving while reporting not
+	// ready, so that load balancers stop routing to it first.
+	DrainDelay time.Duration `envconfig:"DRAIN_DELAY" default:"5s"`
+	// Timeout bounds the wait for in-flight requests.
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"15s"`
+}
+
+var ErrDraining = errors.New("server is shutting down")
+
+// Serving is the readiness check of a server run with ready: it fails once
+// the server is draining.
+func Serving(ready *atomic.Bool) func(context.Context) error {
+	return func(context.Context) error {
+		if !ready.Load() {
+			return ErrDraining
+		}
+		return nil
+	}
+}
+
+// Run serves handler on addr until ctx is cancelled, then drains: ready
+// turns false, so the health check answers 503, new requests are still
+// accepted for DrainDelay, and finally the listener is closed and in-flight
+// requests get Timeout to finish.
+func Run(ctx context.Context, addr string, handler http.Handler, shutdown Shutdown, ready *atomic.Bool) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	ready.Store(true)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	ready.Store(false)
+	log.Infof("shutting down, draining for %s", shutdown.DrainDelay)
+	select {
+	case err := <-serveErr:
+		return err
+	case <-time.After(shutdown.DrainDelay):
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdown.Timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to finish in-flight requests: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Info("server stopped")
+	return nil
+}
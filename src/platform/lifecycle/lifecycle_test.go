@@ -0,0 +1,108 @@
+package lifecycle_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"platform/health"
+	"platform/lifecycle"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func get(url string) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRun_DrainsBeforeStopping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var ready atomic.Bool
+	h := health.New(time.Second)
+	h.Critical("server", lifecycle.Serving(&ready))
+	r := gin.New()
+	h.Register(r)
+
+	addr := freeAddr(t)
+	url := fmt.Sprintf("http://%s%s", addr, health.ReadyPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- lifecycle.Run(ctx, addr, r, lifecycle.Shutdown{DrainDelay: 300 * time.Millisecond, Timeout: time.Second}, &ready)
+	}()
+	require.Eventually(t, func() bool { return get(url) == http.StatusOK }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.Eventually(t, func() bool { return get(url) == http.StatusServiceUnavailable }, 200*time.Millisecond, 10*time.Millisecond)
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return")
+	}
+	assert.Zero(t, get(url))
+}
+
+func TestRun_FinishesInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	var ready atomic.Bool
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- lifecycle.Run(ctx, addr, mux, lifecycle.Shutdown{Timeout: time.Second}, &ready)
+	}()
+	require.Eventually(t, func() bool { return get("http://"+addr+"/ping") == http.StatusOK }, time.Second, 10*time.Millisecond)
+
+	status := make(chan int, 1)
+	go func() { status <- get("http://" + addr + "/slow") }()
+	<-started
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, http.StatusAccepted, <-status)
+	assert.NoError(t, <-stopped)
+	assert.False(t, ready.Load())
+}
+
+func TestRun_ListenFails(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	var ready atomic.Bool
+	err = lifecycle.Run(context.Background(), l.Addr().String(), http.NotFoundHandler(), lifecycle.Shutdown{}, &ready)
+	assert.Error(t, err)
+}
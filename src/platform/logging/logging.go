@@ -0,0 +1,81 @@
+// Package logging configures logrus and ties log lines to the request that
+// caused them.
+//
+// Every request carries an id in X-Request-ID: taken from the caller when it
+// sent a usable one and made up otherwise. The id travels in the context, on
+// to the services called and, as the correlation id, in the messages
+// published, so one id finds a request's lines in every service.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type Config struct {
+	Level string `envconfig:"LEVEL" default:"info"`
+	// Format is text or json.
+	Format string `envconfig:"FORMAT" default:"text"`
+}
+
+// Setup applies cfg to the standard logrus logger.
+func Setup(cfg Config) error {
+	level, err := log.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	log.SetLevel(level)
+
+	switch cfg.Format {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	return nil
+}
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validID bounds what a caller may put into our logs.
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestID returns the id sent by the caller, or a new one when it sent
+// none or one not fit for logging.
+func requestID(sent string) string {
+	if validID.MatchString(sent) {
+		return sent
+	}
+	return uuid.NewString()
+}
+
+// FromContext returns an entry carrying the request id and the trace id
+// of ctx, when it has them.
+func FromContext(ctx context.Context) *log.Entry {
+	entry := log.NewEntry(log.StandardLogger())
+	if id := RequestID(ctx); id != "" {
+		entry = entry.WithField("request_id", id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		entry = entry.WithField("trace_id", sc.TraceID().String())
+	}
+	return entry
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// Middleware assigns the request its id and logs one line once it is
+// served: method, route, status, latency and user. Nothing else of the
+// request is logged; headers, tokens and claims in particular stay out.
+// Health probes and metric scrapes are logged at debug level only.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := requestID(c.GetHeader(RequestIDHeader))
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		entry := FromContext(c.Request.Context()).WithFields(log.Fields{
+			"method":     c.Request.Method,
+			"route":      route,
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+		})
+		if user := user(c); user != "" {
+			entry = entry.WithField("user", user)
+		}
+
+		switch {
+		case status >= 500:
+			entry.Error("request served")
+		case strings.HasPrefix(route, "/manage/"), route == "/metrics":
+			entry.Debug("request served")
+		case status >= 400:
+			entry.Warn("request served")
+		default:
+			entry.Info("request served")
+		}
+	}
+}
+
+// user names who made the request: the user a service acted for, when the
+// route resolved it, or the token subject.
+func user(c *gin.Context) string {
+	if username := c.GetString("username"); username != "" {
+		return username
+	}
+	raw, _ := c.Get("claims")
+	claims, _ := raw.(jwt.MapClaims)
+	sub, _ := claims["sub"].(string)
+	return sub
+}
@@ -0,0 +1,77 @@
+package logging_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"platform/logging"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, req *http.Request, handler gin.HandlerFunc) (*httptest.ResponseRecorder, *test.Hook) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	hook := test.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	r := gin.New()
+	r.Use(logging.Middleware())
+	r.GET("/items/:uid", handler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, hook
+}
+
+func TestMiddleware_KeepsCallerRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(logging.RequestIDHeader, "abc-123")
+
+	var seen string
+	w, hook := serve(t, req, func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, "abc-123", seen)
+	assert.Equal(t, "abc-123", w.Header().Get(logging.RequestIDHeader))
+	assert.Equal(t, "abc-123", hook.LastEntry().Data["request_id"])
+}
+
+func TestMiddleware_ReplacesUnfitRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(logging.RequestIDHeader, "forged\nline "+strings.Repeat("x", 200))
+
+	w, _ := serve(t, req, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	id := w.Header().Get(logging.RequestIDHeader)
+	assert.Len(t, id, 36)
+}
+
+func TestMiddleware_LogsRequestWithoutSecrets(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items/1?email=someone@example.com", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	_, hook := serve(t, req, func(c *gin.Context) {
+		c.Set("claims", jwt.MapClaims{"sub": "auth0|42", "email": "someone@example.com"})
+		c.Status(http.StatusNotFound)
+	})
+
+	entry := hook.LastEntry()
+	assert.Equal(t, log.WarnLevel, entry.Level)
+	assert.Equal(t, "GET", entry.Data["method"])
+	assert.Equal(t, "/items/:uid", entry.Data["route"])
+	assert.Equal(t, http.StatusNotFound, entry.Data["status"])
+	assert.Equal(t, "auth0|42", entry.Data["user"])
+
+	line, err := entry.String()
+	assert.NoError(t, err)
+	assert.NotContains(t, line, "secret-token")
+	assert.NotContains(t, line, "someone@example.com")
+}
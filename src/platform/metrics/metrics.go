@@ -0,0 +1,117 @@
+// Package metrics exposes Prometheus metrics on /metrics: rate, errors and
+// duration of every route, plus counters for the messages a service
+// exchanges over RabbitMQ. Domain packages register their own counters on
+// the default registry, which is what Handler serves.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const Path = "/metrics"
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by route and status.",
+	}, []string{"method", "route", "status"})
+
+	duration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	published = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_published_total",
+		Help: "Messages published to RabbitMQ, by destination and result.",
+	}, []string{"destination", "result"})
+
+	consumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_consumed_total",
+		Help: "Messages consumed from RabbitMQ, by queue and how they were settled.",
+	}, []string{"queue", "result"})
+)
+
+// How a consumed message was settled.
+const (
+	Acked    = "ack"
+	Requeued = "requeue"
+	Dropped  = "drop"
+)
+
+// Middleware counts and times requests by route. Requests matching no route
+// share one label value so that random paths can't blow up the series.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		requests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the default registry.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Config places the metrics of a service that must not serve them next to
+// its API, e.g. the gateway, on a port of their own.
+type Config struct {
+	Host string `envconfig:"HOST"`
+	Port int    `envconfig:"PORT" default:"9100"`
+}
+
+// Serve serves the default registry on Path until ctx is cancelled.
+func Serve(ctx context.Context, cfg Config) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Published counts a message sent to destination, a queue or an exchange.
+func Published(destination string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	published.WithLabelValues(destination, result).Inc()
+}
+
+// Consumed counts a message taken from queue and settled as result.
+func Consumed(queue, result string) {
+	consumed.WithLabelValues(queue, result).Inc()
+}
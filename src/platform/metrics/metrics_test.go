@@ -0,0 +1,79 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"platform/metrics"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_CountsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(metrics.Middleware())
+	r.GET("/items/:uid", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+	r.GET(metrics.Path, metrics.Handler())
+
+	for _, path := range []string{"/items/1", "/items/2", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/items/:uid",status="418"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/items/:uid"} 2`)
+}
+
+func TestConsumed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET(metrics.Path, metrics.Handler())
+
+	metrics.Consumed("test-queue", metrics.Requeued)
+	metrics.Published("test-queue", nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	assert.Contains(t, w.Body.String(), `rabbitmq_consumed_total{queue="test-queue",result="requeue"} 1`)
+	assert.Contains(t, w.Body.String(), `rabbitmq_published_total{destination="test-queue",result="ok"} 1`)
+}
+
+func TestServe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- metrics.Serve(ctx, metrics.Config{Host: "127.0.0.1", Port: port}) }()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d%s", port, metrics.Path)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(url)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+}
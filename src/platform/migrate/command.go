@@ -0,0 +1,59 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"platform/postgres"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = "usage: app migrate up|down|status"
+
+// Command handles `app migrate up|down|status` for the migrations in fsys.
+// The status is written to out.
+func Command(ctx context.Context, conn postgres.Connection, fsys fs.FS, args []string, out io.Writer) error {
+	m, err := New(conn, fsys)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return errors.New(usage)
+	}
+}
+
+// OnStart brings the schema up to date before the server starts.
+func OnStart(ctx context.Context, conn postgres.Connection, fsys fs.FS) error {
+	m, err := New(conn, fsys)
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
@@ -0,0 +1,231 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"platform/migrate"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := migrate.Load(fstest.MapFS{
+		"0010_add_index.up.sql":   file("CREATE INDEX i ON t (a);"),
+		"0010_add_index.down.sql": file("DROP INDEX i;"),
+		"0002_init.up.sql":        file("CREATE TABLE t (a INT);"),
+		"0002_init.down.sql":      file("DROP TABLE t;"),
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, int64(2), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE t;", migrations[0].Down)
+	assert.Equal(t, int64(10), migrations[1].Version)
+	assert.Equal(t, "CREATE INDEX i ON t (a);", migrations[1].Up)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{"0001_init.up.sql": file("SELECT 1;")}},
+		{"bad name", fstest.MapFS{"init.sql": file("SELECT 1;")}},
+		{"two names", fstest.MapFS{
+			"0001_init.up.sql":    file("SELECT 1;"),
+			"0001_other.down.sql": file("SELECT 1;"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrate.Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+var migrations = fstest.MapFS{
+	"0001_init.up.sql":        file("CREATE TABLE t (a INT);"),
+	"0001_init.down.sql":      file("DROP TABLE t;"),
+	"0002_add_index.up.sql":   file("CREATE INDEX i ON t (a);"),
+	"0002_add_index.down.sql": file("DROP INDEX i;"),
+}
+
+func newMigrator(t *testing.T) (*migrate.Migrator, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	m, err := migrate.New(mock, migrations)
+	require.NoError(t, err)
+	return m, mock
+}
+
+// expectLocked expects the transaction every run starts with: it holds the
+// migration lock and makes sure the bookkeeping table exists.
+func expectLocked(mock pgxmock.PgxPoolIface) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WithArgs(7305_2024).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+}
+
+func expectApplied(mock pgxmock.PgxPoolIface, version int64, done bool) {
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(version).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(done))
+}
+
+func TestUp(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	// 0001 is in already, only 0002 runs
+	expectLocked(mock)
+	expectApplied(mock, 1, true)
+	mock.ExpectRollback()
+
+	expectLocked(mock)
+	expectApplied(mock, 2, false)
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX i ON t (a);")).
+		WillReturnResult(pgxmock.NewResult("CREATE INDEX", 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(int64(2), "add_index").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, m.Up(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUp_Fails(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	expectLocked(mock)
+	expectApplied(mock, 1, false)
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE t (a INT);")).
+		WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+
+	err := m.Up(context.Background())
+	assert.ErrorContains(t, err, "migration 1_init")
+	assert.NoError(t, mock.ExpectationsWereMet(), "nothing is recorded and 0002 is not tried")
+}
+
+func TestUp_LockFails(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+		WithArgs(7305_2024).
+		WillReturnError(errors.New("canceling statement due to lock timeout"))
+	mock.ExpectRollback()
+
+	assert.ErrorContains(t, m.Up(context.Background()), "failed to take migration lock")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(int64(2)))
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX i;")).
+		WillReturnResult(pgxmock.NewResult("DROP INDEX", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").
+		WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, m.Down(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown_Nothing(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version"}))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, m.Down(context.Background()), migrate.ErrNothingToRollBack)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown_Unknown(t *testing.T) {
+	m, mock := newMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(int64(7)))
+	mock.ExpectRollback()
+
+	assert.ErrorContains(t, m.Down(context.Background()), "not known to this build")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatus(t *testing.T) {
+	m, mock := newMigrator(t)
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version", "applied_at"}).AddRow(int64(1), at))
+	mock.ExpectRollback()
+
+	statuses, err := m.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "init", statuses[0].Name)
+	if assert.NotNil(t, statuses[0].AppliedAt) {
+		assert.Equal(t, at, *statuses[0].AppliedAt)
+	}
+	assert.Equal(t, "add_index", statuses[1].Name)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommand_Status(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version", "applied_at"}).
+			AddRow(int64(1), time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)))
+	mock.ExpectRollback()
+
+	var out strings.Builder
+	require.NoError(t, migrate.Command(context.Background(), mock, migrations, []string{"status"}, &out))
+	assert.Contains(t, out.String(), "2026-10-01T12:00:00Z")
+	assert.Contains(t, out.String(), "add_index  pending")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommand_Usage(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	for _, args := range [][]string{nil, {"sideways"}, {"up", "down"}} {
+		err := migrate.Command(context.Background(), mock, migrations, args, io.Discard)
+		assert.ErrorContains(t, err, "usage", args)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+// Package outbox relays the events a service stores next to its changes to
+// RabbitMQ.
+//
+// Events are written to the outbox table in the transaction that makes the
+// change, so none is lost or published for a change that was rolled back.
+// The relay then publishes them in outbox order with publisher confirms and
+// marks them published; a crash between the two means an event is delivered
+// again, never skipped, and consumers dedupe by event id.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"platform/events"
+	"platform/health"
+	"platform/metrics"
+	"platform/postgres"
+	"platform/tracing"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+// lockKey serializes relays across replicas so events leave in order. It
+// must differ from the migration lock.
+const lockKey = 7305_2025
+
+const confirmTimeout = 5 * time.Second
+
+var errNotRelayed = errors.New("outbox has not been relayed yet")
+
+type Config struct {
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"1s"`
+	BatchSize    int           `envconfig:"BATCH_SIZE" default:"100"`
+	// Retention is how long published events are kept for inspection.
+	Retention time.Duration `envconfig:"RETENTION" default:"168h"`
+}
+
+type Relay struct {
+	url string
+	cfg Config
+	db  postgres.Connection
+
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+func NewRelay(url string, cfg Config, db postgres.Connection) *Relay {
+	return &Relay{url: url, cfg: cfg, db: db, lastErr: errNotRelayed}
+}
+
+// Check fails while the last relay round did. The service keeps working
+// meanwhile, its events just wait in the outbox.
+func (r *Relay) Check() health.Check {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.lastErr
+	}
+}
+
+// Run relays the outbox every PollInterval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	defer r.disconnect()
+
+	for {
+		err := r.drain(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("failed to relay outbox")
+			r.disconnect()
+		}
+		r.mu.Lock()
+		r.lastErr = err
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain relays batches until the outbox has no pending events.
+func (r *Relay) drain(ctx context.Context) error {
+	for {
+		n, err := r.relay(ctx)
+		if err != nil || n < r.cfg.BatchSize {
+			return err
+		}
+	}
+}
+
+type pending struct {
+	id           int64
+	routingKey   string
+	payload      []byte
+	traceContext map[string]string
+}
+
+// relay publishes one batch and returns how many events it published.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, lockKey).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to take relay lock: %w", err)
+	}
+	if !locked {
+		// another replica is relaying
+		return 0, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT id, routing_key, payload, trace_context FROM outbox
+        WHERE published_at IS NULL
+        ORDER BY id
+        LIMIT $1;
+    `, r.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	batch, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (pending, error) {
+		var p pending
+		err := row.Scan(&p.id, &p.routingKey, &p.payload, &p.traceContext)
+		return p, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	// Events published before a failure are still marked, so that the next
+	// round resumes after them instead of sending them again.
+	published := make([]int64, 0, len(batch))
+	var publishErr error
+	for _, p := range batch {
+		if publishErr = r.publish(ctx, p); publishErr != nil {
+			break
+		}
+		published = append(published, p.id)
+	}
+
+	if len(published) > 0 {
+		_, err = tx.Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = ANY($1);`, published)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark events published: %w", err)
+		}
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM outbox WHERE published_at < now() - make_interval(secs => $1);`, r.cfg.Retention.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(published), publishErr
+}
+
+func (r *Relay) publish(ctx context.Context, p pending) error {
+	var evt events.Envelope
+	if err := json.Unmarshal(p.payload, &evt); err != nil {
+		return fmt.Errorf("event %d is not an envelope: %w", p.id, err)
+	}
+	if err := r.connect(); err != nil {
+		return err
+	}
+
+	// the event is sent within the trace of the request that caused it
+	_, span, headers := tracing.StartPublish(tracing.Extract(ctx, p.traceContext), events.Exchange, amqp.Table{
+		"x-ordering-key":   evt.OrderingKey,
+		"x-sequence":       evt.Sequence,
+		"x-schema-version": int32(evt.Version),
+	})
+	err := r.confirm(ctx, evt, headers, p)
+	tracing.End(span, err)
+	metrics.Published(events.Exchange, err)
+	return err
+}
+
+// confirm publishes p and waits for the broker to take it.
+func (r *Relay) confirm(ctx context.Context, evt events.Envelope, headers amqp.Table, p pending) error {
+	err := r.ch.Publish(events.Exchange, p.routingKey, false, false, amqp.Publishing{
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     evt.ID.String(),
+		CorrelationId: evt.CorrelationID,
+		Type:          string(evt.Type),
+		Timestamp:     evt.OccurredAt,
+		AppId:         evt.Source,
+		Headers:       headers,
+		Body:          p.payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", evt.ID, err)
+	}
+
+	select {
+	case confirm, ok := <-r.confirms:
+		if !ok {
+			return fmt.Errorf("channel closed before event %s was confirmed", evt.ID)
+		}
+		if !confirm.Ack {
+			return fmt.Errorf("broker rejected event %s", evt.ID)
+		}
+		return nil
+	case <-time.After(confirmTimeout):
+		return fmt.Errorf("event %s was not confirmed in %s", evt.ID, confirmTimeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// connect dials RabbitMQ unless already connected and declares the exchange.
+func (r *Relay) connect() error {
+	if r.ch != nil {
+		return nil
+	}
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err == nil {
+		err = ch.ExchangeDeclare(events.Exchange, amqp.ExchangeTopic, true, false, false, false, nil)
+	}
+	if err == nil {
+		err = ch.Confirm(false)
+	}
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set up publishing: %w", err)
+	}
+	r.conn, r.ch = conn, ch
+	r.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	return nil
+}
+
+func (r *Relay) disconnect() {
+	if r.conn != nil && !r.conn.IsClosed() {
+		if err := r.conn.Close(); err != nil {
+			log.WithError(err).Warn("failed to close RabbitMQ connection")
+		}
+	}
+	r.conn, r.ch, r.confirms = nil, nil, nil
+}
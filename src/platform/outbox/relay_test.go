@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"platform/amqptest"
+	"platform/events"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cfg = Config{PollInterval: time.Second, BatchSize: 10, Retention: time.Hour}
+
+func newRelay(t *testing.T, url string) (*Relay, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	r := NewRelay(url, cfg, mock)
+	t.Cleanup(r.disconnect)
+	return r, mock
+}
+
+func envelope(t *testing.T, typ events.Type, key string) []byte {
+	evt, err := events.New(context.Background(), "test", typ, key, map[string]string{"key": key})
+	require.NoError(t, err)
+	body, err := json.Marshal(evt)
+	require.NoError(t, err)
+	return body
+}
+
+// expectBatch expects a relay round that takes the lock and reads rows.
+func expectBatch(mock pgxmock.PgxPoolIface, rows *pgxmock.Rows) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_xact_lock($1)")).
+		WithArgs(lockKey).
+		WillReturnRows(pgxmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectQuery("SELECT id, routing_key, payload, trace_context FROM outbox").
+		WithArgs(cfg.BatchSize).
+		WillReturnRows(rows)
+}
+
+func expectPrune(mock pgxmock.PgxPoolIface) {
+	mock.ExpectExec("DELETE FROM outbox WHERE published_at").
+		WithArgs(cfg.Retention.Seconds()).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectCommit()
+}
+
+func outboxRows() *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"id", "routing_key", "payload", "trace_context"})
+}
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	r, mock := newRelay(t, b.URL())
+
+	first, second := envelope(t, "reservation.created", "r1"), envelope(t, "reservation.returned", "r1")
+	expectBatch(mock, outboxRows().
+		AddRow(int64(1), "reservation.created.v1", first, map[string]string(nil)).
+		AddRow(int64(2), "reservation.returned.v1", second, map[string]string(nil)))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE outbox SET published_at = now() WHERE id = ANY($1);")).
+		WithArgs([]int64{1, 2}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	expectPrune(mock)
+
+	n, err := r.relay(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	published := b.Published()
+	require.Len(t, published, 2)
+	assert.Equal(t, amqptest.Message{Exchange: events.Exchange, RoutingKey: "reservation.created.v1", Body: first}, published[0])
+	assert.Equal(t, "reservation.returned.v1", published[1].RoutingKey)
+	assert.Equal(t, []string{events.Exchange}, b.Declared())
+}
+
+func TestRelay_MarksWhatWasConfirmed(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	r, mock := newRelay(t, b.URL())
+
+	expectBatch(mock, outboxRows().
+		AddRow(int64(1), "reservation.created.v1", envelope(t, "reservation.created", "r1"), map[string]string(nil)).
+		AddRow(int64(2), "reservation.returned.v1", []byte(`"not an envelope"`), map[string]string(nil)).
+		AddRow(int64(3), "reservation.created.v1", envelope(t, "reservation.created", "r2"), map[string]string(nil)))
+	mock.ExpectExec("UPDATE outbox SET published_at").
+		WithArgs([]int64{1}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	expectPrune(mock)
+
+	n, err := r.relay(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, n, "the round stops at the event it can't send")
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, b.Published(), 1)
+}
+
+func TestRelay_BrokerRejects(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	b.Reject.Store(true)
+	r, mock := newRelay(t, b.URL())
+
+	expectBatch(mock, outboxRows().
+		AddRow(int64(1), "reservation.created.v1", envelope(t, "reservation.created", "r1"), map[string]string(nil)))
+	expectPrune(mock)
+
+	_, err := r.relay(context.Background())
+	assert.ErrorContains(t, err, "rejected")
+	assert.NoError(t, mock.ExpectationsWereMet(), "nothing is marked published")
+}
+
+func TestRelay_BrokerDown(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	b.Close()
+	r, mock := newRelay(t, b.URL())
+
+	expectBatch(mock, outboxRows().
+		AddRow(int64(1), "reservation.created.v1", envelope(t, "reservation.created", "r1"), map[string]string(nil)))
+	expectPrune(mock)
+
+	_, err := r.relay(context.Background())
+	assert.ErrorContains(t, err, "failed to connect")
+	assert.NoError(t, mock.ExpectationsWereMet(), "the events wait in the outbox")
+}
+
+func TestRelay_AnotherReplicaRelays(t *testing.T) {
+	r, mock := newRelay(t, "amqp://127.0.0.1:1/")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_xact_lock($1)")).
+		WithArgs(lockKey).
+		WillReturnRows(pgxmock.NewRows([]string{"locked"}).AddRow(false))
+	mock.ExpectRollback()
+
+	n, err := r.relay(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelay_Check(t *testing.T) {
+	b := amqptest.NewBroker(t)
+	r, mock := newRelay(t, b.URL())
+	assert.ErrorIs(t, r.Check()(context.Background()), errNotRelayed, "not ready before the first round")
+
+	expectBatch(mock, outboxRows())
+	expectPrune(mock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return r.Check()(context.Background()) == nil }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	acquiredDesc = prometheus.NewDesc("db_pool_acquired_connections",
+		"Connections currently in use.", nil, nil)
+	idleDesc = prometheus.NewDesc("db_pool_idle_connections",
+		"Connections open and waiting to be used.", nil, nil)
+	totalDesc = prometheus.NewDesc("db_pool_total_connections",
+		"Connections open, in use or idle.", nil, nil)
+	maxDesc = prometheus.NewDesc("db_pool_max_connections",
+		"Connections the pool may open.", nil, nil)
+	acquiresDesc = prometheus.NewDesc("db_pool_acquires_total",
+		"Connections taken from the pool.", nil, nil)
+	waitsDesc = prometheus.NewDesc("db_pool_empty_acquires_total",
+		"Acquires that had to wait for a connection.", nil, nil)
+	waitDesc = prometheus.NewDesc("db_pool_acquire_wait_seconds_total",
+		"Time spent waiting for a connection by acquires that had to wait.", nil, nil)
+)
+
+// poolCollector reads the pool statistics at scrape time.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// Collector reports the connection pool to Prometheus.
+func (c *dbClient) Collector() prometheus.Collector {
+	return poolCollector{pool: c.conn}
+}
+
+func (p poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{acquiredDesc, idleDesc, totalDesc, maxDesc, acquiresDesc, waitsDesc, waitDesc} {
+		ch <- d
+	}
+}
+
+func (p poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := p.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(acquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(idleDesc, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(totalDesc, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(maxDesc, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(acquiresDesc, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(waitsDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(waitDesc, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"platform/tracing"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -34,7 +35,12 @@ func (c *dbClient) Ping(ctx context.Context) error {
 }
 
 func Connect(ctx context.Context, cfg Config) (*dbClient, error) {
-	pool, err := pgxpool.New(ctx, cfg.ConnStr)
+	poolCfg, err := pgxpool.ParseConfig(cfg.ConnStr)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig.Tracer = tracing.QueryTracer{}
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, err
 	}
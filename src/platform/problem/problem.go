@@ -0,0 +1,130 @@
+// Package problem writes errors as RFC 7807 problem details. Every problem
+// carries a stable code that clients can branch on instead of the
+// human-readable detail.
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"platform/logging"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+type Code string
+
+const (
+	BadRequest              Code = "BAD_REQUEST"
+	Unauthorized            Code = "UNAUTHORIZED"
+	Forbidden               Code = "FORBIDDEN"
+	NotFound                Code = "NOT_FOUND"
+	Conflict                Code = "CONFLICT"
+	BookNotAvailable        Code = "BOOK_NOT_AVAILABLE"
+	ReservationLimitReached Code = "RESERVATION_LIMIT_REACHED"
+	ReservationClosed       Code = "RESERVATION_CLOSED"
+	TooManyRequests         Code = "TOO_MANY_REQUESTS"
+	ServiceUnavailable      Code = "SERVICE_UNAVAILABLE"
+	Internal                Code = "INTERNAL_ERROR"
+)
+
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     Code   `json:"code"`
+}
+
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   "urn:problem-type:" + strings.ToLower(strings.ReplaceAll(string(code), "_", "-")),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// Write sends the problem as the response body.
+func Write(c *gin.Context, status int, code Code, detail string) {
+	render(c, New(status, code, detail), false)
+}
+
+// WriteInternal answers 500 for a failure of the service itself. The cause
+// may carry SQL or addresses inside the deployment, so it is logged with
+// the request rather than sent.
+func WriteInternal(c *gin.Context, err error) {
+	logging.FromContext(c.Request.Context()).WithError(err).Error("request failed")
+	Write(c, http.StatusInternalServerError, Internal, http.StatusText(http.StatusInternalServerError))
+}
+
+// Abort sends the problem and stops the handler chain.
+func Abort(c *gin.Context, status int, code Code, detail string) {
+	render(c, New(status, code, detail), true)
+}
+
+func render(c *gin.Context, p *Problem, abort bool) {
+	p.Instance = c.Request.URL.Path
+	c.Header("Content-Type", ContentType)
+	if abort {
+		c.AbortWithStatusJSON(p.Status, p)
+		return
+	}
+	c.JSON(p.Status, p)
+}
+
+// CodeFor is the generic code of an HTTP status, for errors that have no
+// more specific one.
+func CodeFor(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return TooManyRequests
+	case http.StatusServiceUnavailable:
+		return ServiceUnavailable
+	default:
+		return Internal
+	}
+}
+
+// Decode reads the problem a backend answered with. Bodies that are not
+// problem details still yield a problem built from the status code, so
+// callers always get a typed error.
+func Decode(resp *http.Response) *Problem {
+	body, _ := io.ReadAll(resp.Body)
+	return DecodeBody(resp, body)
+}
+
+// DecodeBody is Decode for a response whose body has been read already, as
+// the generated clients do.
+func DecodeBody(resp *http.Response, body []byte) *Problem {
+	var p Problem
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), ContentType) &&
+		json.Unmarshal(body, &p) == nil && p.Code != "" {
+		p.Status = resp.StatusCode
+		return &p
+	}
+	return New(resp.StatusCode, CodeFor(resp.StatusCode), fmt.Sprintf("unexpected status: %d", resp.StatusCode))
+}
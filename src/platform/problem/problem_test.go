@@ -0,0 +1,106 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"platform/problem"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func response(status int, contentType, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", contentType)
+	rec.WriteHeader(status)
+	rec.WriteString(body)
+	return rec.Result()
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   *http.Response
+		code   problem.Code
+		status int
+		detail string
+	}{
+		{"problem", response(http.StatusConflict, problem.ContentType,
+			`{"status":409,"code":"RESERVATION_LIMIT_REACHED","detail":"reservation limit reached"}`),
+			problem.ReservationLimitReached, http.StatusConflict, "reservation limit reached"},
+		{"status of the response wins", response(http.StatusNotFound, problem.ContentType+"; charset=utf-8",
+			`{"status":500,"code":"NOT_FOUND"}`),
+			problem.NotFound, http.StatusNotFound, ""},
+		{"plain json", response(http.StatusConflict, "application/json", `{"code":"BOOK_NOT_AVAILABLE"}`),
+			problem.Conflict, http.StatusConflict, "unexpected status: 409"},
+		{"problem without code", response(http.StatusServiceUnavailable, problem.ContentType, `{"title":"down"}`),
+			problem.ServiceUnavailable, http.StatusServiceUnavailable, "unexpected status: 503"},
+		{"not json", response(http.StatusBadGateway, problem.ContentType, `<html>`),
+			problem.Internal, http.StatusBadGateway, "unexpected status: 502"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := problem.Decode(tt.resp)
+			assert.Equal(t, tt.code, p.Code)
+			assert.Equal(t, tt.status, p.Status)
+			assert.Equal(t, tt.detail, p.Detail)
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	// the generated clients hand over the body they have read
+	resp := response(http.StatusConflict, problem.ContentType, "")
+	p := problem.DecodeBody(resp, []byte(`{"code":"BOOK_NOT_AVAILABLE","detail":"no copies left"}`))
+	assert.Equal(t, problem.BookNotAvailable, p.Code)
+	assert.Equal(t, http.StatusConflict, p.Status)
+	assert.Equal(t, "no copies left", p.Detail)
+}
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/books/:uid", func(c *gin.Context) {
+		problem.Abort(c, http.StatusNotFound, problem.NotFound, "book not found")
+	}, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), problem.ContentType))
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, problem.Problem{
+		Type:     "urn:problem-type:not-found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "book not found",
+		Instance: "/books/42",
+		Code:     problem.NotFound,
+	}, p)
+}
+
+func TestWriteInternal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/books", func(c *gin.Context) {
+		problem.WriteInternal(c, errors.New(`failed to execute query: ERROR: column "event_seq" does not exist`))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, problem.Internal, p.Code)
+	assert.NotContains(t, p.Detail, "event_seq")
+}
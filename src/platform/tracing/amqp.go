@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// amqpCarrier lets the propagator read and write message headers.
+type amqpCarrier amqp.Table
+
+func (c amqpCarrier) Get(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c amqpCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c amqpCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// StartPublish starts a producer span for a message sent to destination and
+// writes its context into headers, which it creates when nil.
+func StartPublish(ctx context.Context, destination string, headers amqp.Table) (context.Context, trace.Span, amqp.Table) {
+	ctx, span := Tracer().Start(ctx, "send "+destination,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(destination),
+		),
+	)
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, amqpCarrier(headers))
+	return ctx, span, headers
+}
+
+// StartConsume starts a consumer span for a message taken from queue,
+// continuing the trace its publisher wrote into the headers.
+func StartConsume(ctx context.Context, queue string, msg amqp.Delivery) (context.Context, trace.Span) {
+	if msg.Headers != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, amqpCarrier(msg.Headers))
+	}
+	return Tracer().Start(ctx, "process "+queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingMessageID(msg.MessageId),
+		),
+	)
+}
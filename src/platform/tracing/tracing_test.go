@@ -0,0 +1,60 @@
+package tracing_test
+
+import (
+	"context"
+	"platform/tracing"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setup(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), "test", tracing.Config{})
+	assert.NoError(t, err)
+	provider := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+}
+
+func TestAMQP_ContinuesTrace(t *testing.T) {
+	setup(t)
+
+	ctx, span, headers := tracing.StartPublish(context.Background(), "queue", nil)
+	span.End()
+	assert.Contains(t, headers, "traceparent")
+
+	_, consumed := tracing.StartConsume(context.Background(), "queue", amqp.Delivery{Headers: headers})
+	consumed.End()
+
+	published := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, published.TraceID(), consumed.SpanContext().TraceID())
+	assert.NotEqual(t, published.SpanID(), consumed.SpanContext().SpanID())
+}
+
+func TestAMQP_StartsTraceWithoutHeaders(t *testing.T) {
+	setup(t)
+
+	_, span := tracing.StartConsume(context.Background(), "queue", amqp.Delivery{})
+	span.End()
+
+	assert.True(t, span.SpanContext().IsValid())
+}
+
+func TestInjectExtract(t *testing.T) {
+	setup(t)
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "outbox")
+	defer span.End()
+
+	restored := tracing.Extract(context.Background(), tracing.Inject(ctx))
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(restored).TraceID())
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), "test", tracing.Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
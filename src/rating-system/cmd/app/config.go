@@ -1,11 +1,19 @@
 package main
 
 import (
+	"platform/auth"
+	"platform/logging"
+	"platform/postgres"
+	"platform/tracing"
 	"rating-system/internal/server"
-	"rating-system/pkg/postgres"
 )
 
 type Config struct {
 	Server server.Server   `envconfig:"SERVER"`
 	DB     postgres.Config `envconfig:"DB"`
+	Auth   auth.Config     `envconfig:"AUTH"`
+	// RabbitMQ is where reservation events are consumed from.
+	RabbitMQ string         `envconfig:"RABBITMQ"`
+	Tracing  tracing.Config `envconfig:"TRACING"`
+	Log      logging.Config `envconfig:"LOG"`
 }
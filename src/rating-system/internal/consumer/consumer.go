@@ -0,0 +1,168 @@
+// Package consumer feeds reservation events from RabbitMQ to the rating
+// service.
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"platform/health"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
+	"rating-system/internal/repo"
+	"rating-system/internal/service"
+	"rating-system/pkg/events"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+// Queue is the durable queue rating-system binds to the events exchange.
+const Queue = "rating.reservation-events"
+
+var routingKeys = []string{
+	events.Envelope{Type: events.ReservationReturned, Version: 1}.RoutingKey(),
+	events.Envelope{Type: events.ReservationExpired, Version: 1}.RoutingKey(),
+}
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+	retryDelay = 5 * time.Second
+)
+
+var errNotConnected = errors.New("not connected to RabbitMQ")
+
+type Handler func(ctx context.Context, evt events.Envelope) error
+
+// Consumer keeps a subscription to the events exchange and reconnects with
+// exponential backoff when it drops.
+type Consumer struct {
+	url       string
+	handle    Handler
+	connected atomic.Bool
+}
+
+func New(url string, handle Handler) *Consumer {
+	return &Consumer{url: url, handle: handle}
+}
+
+func (c *Consumer) Check() health.Check {
+	return func(context.Context) error {
+		if !c.connected.Load() {
+			return errNotConnected
+		}
+		return nil
+	}
+}
+
+// Run consumes until ctx is cancelled. The delivery in hand is settled
+// before it returns.
+func (c *Consumer) Run(ctx context.Context) {
+	backoff := minBackoff
+	for {
+		err := c.consume(ctx, func() { backoff = minBackoff })
+		c.connected.Store(false)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithError(err).Warnf("event consumer stopped, reconnecting in %s", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// consume runs one connection until it is lost or ctx is cancelled.
+func (c *Consumer) consume(ctx context.Context, connected func()) error {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open a channel: %w", err)
+	}
+	if err := ch.ExchangeDeclare(events.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	if _, err := ch.QueueDeclare(Queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	for _, key := range routingKeys {
+		if err := ch.QueueBind(Queue, key, events.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", key, err)
+		}
+	}
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	msgs, err := ch.Consume(Queue, "rating-system", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	c.connected.Store(true)
+	connected()
+	log.Infof("consuming %s", Queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return errors.New("delivery channel closed")
+			}
+			c.deliver(ctx, d)
+		}
+	}
+}
+
+// deliver acks an event once it is applied or can never be, and requeues it
+// after a pause when the failure may pass. The event is handled within the
+// trace it was published in and logged under the id of the request that
+// caused it.
+func (c *Consumer) deliver(ctx context.Context, d amqp.Delivery) {
+	ctx, span := tracing.StartConsume(ctx, Queue, d)
+	var err error
+	defer func() { tracing.End(span, err) }()
+	ctx = logging.WithRequestID(ctx, d.CorrelationId)
+	entry := logging.FromContext(ctx)
+
+	var evt events.Envelope
+	if err = json.Unmarshal(d.Body, &evt); err != nil {
+		entry.WithError(err).Errorf("dropping undecodable message %s", d.MessageId)
+		_ = d.Nack(false, false)
+		metrics.Consumed(Queue, metrics.Dropped)
+		return
+	}
+
+	err = c.handle(ctx, evt)
+	switch {
+	case err == nil:
+		_ = d.Ack(false)
+		metrics.Consumed(Queue, metrics.Acked)
+	case errors.Is(err, service.ErrMalformedEvent), errors.Is(err, repo.ErrRatingNotFound):
+		entry.WithError(err).Errorf("dropping event %s", evt.ID)
+		_ = d.Nack(false, false)
+		metrics.Consumed(Queue, metrics.Dropped)
+	default:
+		entry.WithError(err).Warnf("failed to apply event %s, retrying in %s", evt.ID, retryDelay)
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+		}
+		_ = d.Nack(false, true)
+		metrics.Consumed(Queue, metrics.Requeued)
+	}
+}
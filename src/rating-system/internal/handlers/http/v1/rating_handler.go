@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"contract/rating"
+	"errors"
 	"net/http"
-	"rating-system/internal/dto"
+	"platform/auth"
+	"platform/problem"
+	"rating-system/internal/repo"
 	"rating-system/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -12,8 +16,12 @@ type RatingHandler struct {
 	service service.RatingServiceIFace
 }
 
-type GetBookLibRequest struct {
-	UID string `uri:"uid" binding:"required"`
+func writeRatingError(c *gin.Context, err error) {
+	if errors.Is(err, repo.ErrRatingNotFound) {
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+		return
+	}
+	problem.WriteInternal(c, err)
 }
 
 func New(service service.RatingServiceIFace) *RatingHandler {
@@ -23,46 +31,45 @@ func (h *RatingHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	ratingRoutes := rg.Group("/rating")
 	{
 		ratingRoutes.GET("/", h.GetRatingHandler)
-		ratingRoutes.PUT("/stars/:stars_diff", h.UpdateRatingHandler)
+		ratingRoutes.PUT("/stars/:stars_diff", auth.Require(auth.Librarian, auth.Service), h.UpdateRatingHandler)
 	}
 }
 
 // GET /api/v1/rating/
-// Header: X-User-Name: {{username}}
 func (h *RatingHandler) GetRatingHandler(c *gin.Context) {
-	username := c.GetHeader("X-User-Name")
-	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header is required"})
-		return
-	}
+	username := auth.Username(c)
 
 	resp, err := h.service.GetRating(c, username)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeRatingError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// PUT /api/v1/rating/stars/:stars_diff
+// Staff change the rating of the patron named in the query; services may
+// act for them through X-User-Name as everywhere else.
 func (h *RatingHandler) UpdateRatingHandler(c *gin.Context) {
-	username := c.GetHeader("X-User-Name")
-	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header is required"})
-		return
+	username := auth.Username(c)
+	if target := c.Query("username"); target != "" {
+		username = target
 	}
 
-	var uriReq dto.UpdateRatingRequest
+	var uriReq struct {
+		StarsDiff int `uri:"stars_diff" binding:"required,ne=0"`
+	}
 
 	if err := c.ShouldBindUri(&uriReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
 	if err := h.service.UpdateRating(c, username, uriReq.StarsDiff); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeRatingError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "rating updated successfully"})
+	c.JSON(http.StatusOK, rating.Message{Message: "rating updated successfully"})
 }
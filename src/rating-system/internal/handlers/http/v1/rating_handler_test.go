@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"context"
+	"contract/contracttest"
+	"contract/rating"
+	"errors"
+	"fmt"
+	"net/http"
+	"platform/auth/authtest"
+	"platform/problem"
+	handlers "rating-system/internal/handlers/http/v1"
+	"rating-system/internal/repo"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingService struct {
+	err error
+}
+
+func (s failingService) GetRating(context.Context, string) (*rating.RatingResponse, error) {
+	return nil, s.err
+}
+
+func (s failingService) UpdateRating(context.Context, string, int) error {
+	return s.err
+}
+
+func TestRatingErrorStatus(t *testing.T) {
+	minter := authtest.NewMinter()
+	token := minter.Token(jwt.MapClaims{"sub": "user", "roles": []string{"librarian"}})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no rating", repo.ErrRatingNotFound, http.StatusNotFound},
+		{"wrapped", fmt.Errorf("failed to get current rating: %w", repo.ErrRatingNotFound), http.StatusNotFound},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, v1 := minter.Router(t, "/api/v1")
+			handlers.New(failingService{err: tt.err}).RegisterRoutes(v1)
+
+			for _, call := range []struct{ method, path string }{
+				{http.MethodGet, "/api/v1/rating/"},
+				{http.MethodPut, "/api/v1/rating/stars/1"},
+			} {
+				w := authtest.Do(r, call.method, call.path, token, "")
+
+				assert.Equal(t, tt.want, w.Code, call.method)
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), call.method)
+			}
+		})
+	}
+}
+
+type ratedService struct {
+	failingService
+}
+
+func (ratedService) GetRating(_ context.Context, username string) (*rating.RatingResponse, error) {
+	return &rating.RatingResponse{Id: 1, Username: username, Stars: 75}, nil
+}
+
+// The gateway hands the rating on as it is, so it must keep to the shape of
+// the contract.
+func TestRating_Contract(t *testing.T) {
+	minter := authtest.NewMinter()
+	r, v1 := minter.Router(t, "/api/v1")
+	handlers.New(ratedService{}).RegisterRoutes(v1)
+
+	token := minter.Token(jwt.MapClaims{"sub": "user", "roles": []string{"patron"}})
+	w := authtest.Do(r, http.MethodGet, "/api/v1/rating/", token, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	contracttest.Schema(t, "UserRatingResponse", w.Body.Bytes())
+}
+
+// recordingService remembers whose rating was changed.
+type recordingService struct {
+	failingService
+	updated string
+}
+
+func (s *recordingService) UpdateRating(_ context.Context, username string, _ int) error {
+	s.updated = username
+	return nil
+}
+
+func TestUpdateRating_Target(t *testing.T) {
+	minter := authtest.NewMinter()
+	librarian := minter.Token(jwt.MapClaims{"sub": "staff", "roles": []string{"librarian"}})
+	patron := minter.Token(jwt.MapClaims{"sub": "alice"})
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		code  int
+		want  string
+	}{
+		{"librarian names a patron", librarian, "/api/v1/rating/stars/-5?username=alice", http.StatusOK, "alice"},
+		{"librarian names nobody", librarian, "/api/v1/rating/stars/-5", http.StatusOK, "staff"},
+		{"patron", patron, "/api/v1/rating/stars/5?username=alice", http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &recordingService{}
+			r, v1 := minter.Router(t, "/api/v1")
+			handlers.New(svc).RegisterRoutes(v1)
+
+			w := authtest.Do(r, http.MethodPut, tt.path, tt.token, "")
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.want, svc.updated)
+		})
+	}
+}
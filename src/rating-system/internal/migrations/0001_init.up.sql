@@ -1,5 +1,3 @@
-\c ratings
-
 CREATE TABLE IF NOT EXISTS rating
 (
     id       SERIAL PRIMARY KEY,
@@ -7,6 +5,3 @@ CREATE TABLE IF NOT EXISTS rating
     stars    INT NOT NULL
     CHECK (stars BETWEEN 0 AND 100)
     );
-
-INSERT INTO rating (username, stars)
-VALUES ('Test Max', 75);
@@ -0,0 +1 @@
+DELETE FROM rating WHERE username IN ('Test Max', 'auth0|694550e3427eb2c33e5671d4');
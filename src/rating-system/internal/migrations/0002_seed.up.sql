@@ -0,0 +1,5 @@
+INSERT INTO rating (username, stars)
+SELECT seed.username, seed.stars
+FROM (VALUES ('Test Max', 75),
+             ('auth0|694550e3427eb2c33e5671d4', 75)) AS seed (username, stars)
+WHERE NOT EXISTS (SELECT 1 FROM rating r WHERE r.username = seed.username);
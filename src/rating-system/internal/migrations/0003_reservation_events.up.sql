@@ -0,0 +1,27 @@
+-- How many stars each reservation event is worth. A rule for a specific
+-- condition of the returned copy wins over the rule without one.
+CREATE TABLE IF NOT EXISTS rating_rules
+(
+    id         SERIAL PRIMARY KEY,
+    event_type VARCHAR(80) NOT NULL,
+    condition  VARCHAR(20),
+    stars_diff INT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS rating_rules_event_condition_idx
+    ON rating_rules (event_type, COALESCE(condition, ''));
+
+INSERT INTO rating_rules (event_type, condition, stars_diff)
+VALUES ('reservation.returned', NULL, 1),
+       ('reservation.expired', NULL, -10)
+ON CONFLICT DO NOTHING;
+
+-- Events already applied, so that redeliveries are not counted twice.
+CREATE TABLE IF NOT EXISTS processed_events
+(
+    event_id     UUID PRIMARY KEY,
+    event_type   VARCHAR(80) NOT NULL,
+    username     VARCHAR(80) NOT NULL,
+    stars_diff   INT NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -2,16 +2,25 @@ package repo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"platform/postgres"
 	"rating-system/internal/models"
-	"rating-system/pkg/postgres"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
+var ErrRatingNotFound = errors.New("rating not found")
+
 type RatingRepository interface {
 	GetRatingRepo(ctx context.Context, username string) (*models.Rating, error)
 	UpdateRatingRepo(ctx context.Context, username string, stars int) error
+	// ApplyEvent moves the user's rating by the rule for eventType and
+	// condition, once per event id. It reports the change and whether the
+	// event was new.
+	ApplyEvent(ctx context.Context, eventID uuid.UUID, eventType, username, condition string) (int, bool, error)
 }
 
 type ratingRepo struct {
@@ -41,6 +50,9 @@ func (r *ratingRepo) GetRatingRepo(ctx context.Context, username string) (*model
 	defer rows.Close()
 
 	rate, err := pgx.CollectOneRow[models.Rating](rows, pgx.RowToStructByName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRatingNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -55,9 +67,63 @@ func (r *ratingRepo) UpdateRatingRepo(ctx context.Context, username string, star
 	if err != nil {
 		return err
 	}
-	_, err = r.conn.Exec(ctx, sql, args...)
+	tag, err := r.conn.Exec(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrRatingNotFound
+	}
 	return nil
 }
+
+func (r *ratingRepo) ApplyEvent(ctx context.Context, eventID uuid.UUID, eventType, username, condition string) (int, bool, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var diff int
+	err = tx.QueryRow(ctx, `
+        SELECT stars_diff FROM rating_rules
+        WHERE event_type = $1 AND (condition = $2 OR condition IS NULL)
+        ORDER BY condition IS NULL
+        LIMIT 1;
+    `, eventType, condition).Scan(&diff)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, fmt.Errorf("failed to look up rating rule: %w", err)
+	}
+
+	// A concurrent redelivery waits here for the first one to commit and
+	// then finds the id taken.
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO processed_events (event_id, event_type, username, stars_diff)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (event_id) DO NOTHING;
+    `, eventID, eventType, username, diff)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to record event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, false, nil
+	}
+
+	if diff != 0 {
+		tag, err = tx.Exec(ctx, `
+            UPDATE rating SET stars = LEAST(100, GREATEST(0, stars + $2))
+            WHERE username = $1;
+        `, username, diff)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to update rating: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return 0, false, ErrRatingNotFound
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return diff, true, nil
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ruleSQL      = regexp.QuoteMeta("SELECT stars_diff FROM rating_rules")
+	processedSQL = regexp.QuoteMeta("INSERT INTO processed_events (event_id, event_type, username, stars_diff)")
+	ratingSQL    = regexp.QuoteMeta("UPDATE rating SET stars = LEAST(100, GREATEST(0, stars + $2))")
+)
+
+func newRatingMock(t *testing.T) (*ratingRepo, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	return &ratingRepo{conn: mock}, mock
+}
+
+func expectRule(mock pgxmock.PgxPoolIface, eventType, condition string, diff int) {
+	mock.ExpectQuery(ruleSQL).
+		WithArgs(eventType, condition).
+		WillReturnRows(pgxmock.NewRows([]string{"stars_diff"}).AddRow(diff))
+}
+
+func TestApplyEvent(t *testing.T) {
+	r, mock := newRatingMock(t)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	expectRule(mock, "reservation.expired", "BAD", -10)
+	mock.ExpectExec(processedSQL).
+		WithArgs(id, "reservation.expired", "alice", -10).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(ratingSQL).
+		WithArgs("alice", -10).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	diff, applied, err := r.ApplyEvent(context.Background(), id, "reservation.expired", "alice", "BAD")
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, -10, diff)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyEvent_AlreadyProcessed(t *testing.T) {
+	r, mock := newRatingMock(t)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	expectRule(mock, "reservation.returned", "EXCELLENT", 1)
+	mock.ExpectExec(processedSQL).
+		WithArgs(id, "reservation.returned", "alice", 1).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectRollback()
+
+	diff, applied, err := r.ApplyEvent(context.Background(), id, "reservation.returned", "alice", "EXCELLENT")
+	require.NoError(t, err)
+	assert.False(t, applied, "the rating is left alone")
+	assert.Zero(t, diff)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyEvent_NoRule(t *testing.T) {
+	r, mock := newRatingMock(t)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(ruleSQL).
+		WithArgs("reservation.lost", "").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectExec(processedSQL).
+		WithArgs(id, "reservation.lost", "alice", 0).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	diff, applied, err := r.ApplyEvent(context.Background(), id, "reservation.lost", "alice", "")
+	require.NoError(t, err)
+	assert.True(t, applied, "the event is recorded so it isn't looked at again")
+	assert.Zero(t, diff)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplyEvent_UnknownUser(t *testing.T) {
+	r, mock := newRatingMock(t)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	expectRule(mock, "reservation.returned", "", 1)
+	mock.ExpectExec(processedSQL).
+		WithArgs(id, "reservation.returned", "bob", 1).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(ratingSQL).
+		WithArgs("bob", 1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectRollback()
+
+	_, _, err := r.ApplyEvent(context.Background(), id, "reservation.returned", "bob", "")
+	assert.ErrorIs(t, err, ErrRatingNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet(), "the processed event is rolled back with it")
+}
@@ -1,34 +1,51 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"rating-system/internal/auth"
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"sync/atomic"
 
+	"platform/postgres"
 	"rating-system/internal/handlers/http/v1"
 	"rating-system/internal/repo"
 	"rating-system/internal/service"
-	"rating-system/pkg/postgres"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	//log "github.com/sirupsen/logrus"
 	//log "github.com/sirupsen/logrus"
 )
 
 type Server struct {
-	Host      string `envconfig:"HOST"`
-	Port      int    `envconfig:"PORT" required:"true"`
+	Host      string             `envconfig:"HOST"`
+	Port      int                `envconfig:"PORT" required:"true"`
+	Shutdown  lifecycle.Shutdown `envconfig:"SHUTDOWN"`
 	DB        postgres.Client
+	Verifier  *auth.Verifier `ignored:"true"`
 	GinRouter *gin.Engine
+	ready     atomic.Bool
+	health    *health.Checker
 }
 
-func New(dbc postgres.Client, host string, port int) (*Server, error) {
+func New(dbc postgres.Client, verifier *auth.Verifier, host string, port int, shutdown lifecycle.Shutdown) (*Server, error) {
 	s := &Server{
 		Host:      host,
 		Port:      port,
+		Shutdown:  shutdown,
 		DB:        dbc,
-		GinRouter: gin.Default(),
+		Verifier:  verifier,
+		GinRouter: gin.New(),
 	}
+	// Handlers pass the gin context on as a context.Context; fall back to
+	// the request context so the trace span reaches the repositories.
+	s.GinRouter.ContextWithFallback = true
+	s.GinRouter.Use(logging.Middleware(), metrics.Middleware(), gin.Recovery(), otelgin.Middleware("rating-system"))
 
 	if err := s.initRoutes(); err != nil {
 		return nil, err
@@ -41,13 +58,15 @@ func (s *Server) initRoutes() error {
 	s.GinRouter.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"msg": "pong"})
 	})
-	s.GinRouter.GET("/manage/health", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
+	s.initHealthRoutes()
+	s.GinRouter.GET(metrics.Path, metrics.Handler())
+	if err := s.initDocsRoutes(); err != nil {
+		return err
+	}
 
-	authMiddleware := auth.AuthMiddleware()
+	authMiddleware := auth.AuthMiddleware(s.Verifier)
 	v1 := s.GinRouter.Group("/api/v1")
-	v1.Use(authMiddleware)
+	v1.Use(authMiddleware, auth.Require(auth.Patron, auth.Service), auth.Identity())
 
 	rateRepo := repo.NewRatingRepo(s.DB)
 	rateService := service.NewRatingService(rateRepo)
@@ -57,6 +76,11 @@ func (s *Server) initRoutes() error {
 	return nil
 }
 
-func (s *Server) Run() error {
-	return s.GinRouter.Run(fmt.Sprintf("%s:%d", s.Host, s.Port))
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
+// Run serves until ctx is cancelled, then drains as lifecycle.Run does.
+func (s *Server) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, fmt.Sprintf("%s:%d", s.Host, s.Port), s.GinRouter, s.Shutdown, &s.ready)
 }
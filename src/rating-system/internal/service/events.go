@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"platform/logging"
+	"rating-system/internal/repo"
+	"rating-system/pkg/events"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrMalformedEvent marks an event that will never apply, however often it
+// is redelivered.
+var ErrMalformedEvent = errors.New("malformed event")
+
+// EventHandler rates users by what happens to their reservations.
+type EventHandler struct {
+	repo repo.RatingRepository
+}
+
+func NewEventHandler(repo repo.RatingRepository) *EventHandler {
+	return &EventHandler{repo: repo}
+}
+
+// Handle applies a reservation event. An event seen before is skipped.
+func (h *EventHandler) Handle(ctx context.Context, evt events.Envelope) error {
+	if evt.Version != 1 {
+		return fmt.Errorf("%w: %s has unsupported version %d", ErrMalformedEvent, evt.Type, evt.Version)
+	}
+	var data events.ReservationV1
+	if err := json.Unmarshal(evt.Data, &data); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrMalformedEvent, evt.ID, err)
+	}
+	if data.Username == "" {
+		return fmt.Errorf("%w: %s has no username", ErrMalformedEvent, evt.ID)
+	}
+
+	diff, applied, err := h.repo.ApplyEvent(ctx, evt.ID, string(evt.Type), data.Username, data.Condition)
+	if err != nil {
+		return err
+	}
+	entry := logging.FromContext(ctx).WithFields(log.Fields{
+		"event":    evt.ID,
+		"type":     evt.Type,
+		"username": data.Username,
+	})
+	if !applied {
+		entry.Info("event already applied, skipping")
+		return nil
+	}
+	entry.Infof("rating changed by %d", diff)
+	return nil
+}
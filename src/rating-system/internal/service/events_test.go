@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	platformevents "platform/events"
+	"rating-system/internal/service"
+	"rating-system/pkg/events"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func returned(t *testing.T, data events.ReservationV1) events.Envelope {
+	evt, err := platformevents.New(context.Background(), "reservation-system", events.ReservationReturned, data.ReservationUID, data)
+	assert.NoError(t, err)
+	return evt
+}
+
+func TestHandleEvent_AppliesOnce(t *testing.T) {
+	mockRepo := new(MockRatingRepo)
+	h := service.NewEventHandler(mockRepo)
+
+	evt := returned(t, events.ReservationV1{Username: "alice", Condition: "BAD"})
+	mockRepo.On("ApplyEvent", mock.Anything, evt.ID, "reservation.returned", "alice", "BAD").
+		Return(1, true, nil).Once()
+	mockRepo.On("ApplyEvent", mock.Anything, evt.ID, "reservation.returned", "alice", "BAD").
+		Return(0, false, nil).Once()
+
+	assert.NoError(t, h.Handle(context.Background(), evt))
+	assert.NoError(t, h.Handle(context.Background(), evt), "a redelivery is not an error")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_Malformed(t *testing.T) {
+	valid := returned(t, events.ReservationV1{Username: "alice"})
+
+	tests := []struct {
+		name   string
+		mutate func(evt *events.Envelope)
+	}{
+		{"unknown version", func(evt *events.Envelope) { evt.Version = 2 }},
+		{"bad payload", func(evt *events.Envelope) { evt.Data = json.RawMessage(`"nope"`) }},
+		{"no username", func(evt *events.Envelope) { evt.Data = json.RawMessage(`{}`) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRatingRepo)
+			evt := valid
+			tt.mutate(&evt)
+
+			err := service.NewEventHandler(mockRepo).Handle(context.Background(), evt)
+			assert.ErrorIs(t, err, service.ErrMalformedEvent)
+			mockRepo.AssertNotCalled(t, "ApplyEvent")
+		})
+	}
+}
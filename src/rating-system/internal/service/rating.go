@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
+	"contract/rating"
 	"fmt"
-	"rating-system/internal/dto"
 	"rating-system/internal/repo"
 )
 
 type RatingServiceIFace interface {
-	GetRating(ctx context.Context, username string) (*dto.RatingResponse, error)
+	GetRating(ctx context.Context, username string) (*rating.RatingResponse, error)
 	UpdateRating(ctx context.Context, username string, delta int) error
 }
 
@@ -20,12 +20,12 @@ func NewRatingService(repo repo.RatingRepository) RatingServiceIFace {
 	return &ratingService{repo: repo}
 }
 
-func (r *ratingService) GetRating(ctx context.Context, username string) (*dto.RatingResponse, error) {
-	rating, err := r.repo.GetRatingRepo(ctx, username)
+func (r *ratingService) GetRating(ctx context.Context, username string) (*rating.RatingResponse, error) {
+	current, err := r.repo.GetRatingRepo(ctx, username)
 	if err != nil {
 		return nil, err
 	}
-	return &dto.RatingResponse{Stars: rating.Stars}, nil
+	return &rating.RatingResponse{Stars: current.Stars}, nil
 }
 
 func (r *ratingService) UpdateRating(ctx context.Context, username string, delta int) error {
@@ -41,7 +41,6 @@ func (r *ratingService) UpdateRating(ctx context.Context, username string, delta
 	if newStars > 100 {
 		newStars = 100
 	}
-	fmt.Println(newStars)
 
 	if err := r.repo.UpdateRatingRepo(ctx, username, newStars); err != nil {
 		return fmt.Errorf("failed to update rating: %w", err)
@@ -7,6 +7,7 @@ import (
 	"rating-system/internal/service"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,6 +29,11 @@ func (m *MockRatingRepo) UpdateRatingRepo(ctx context.Context, username string,
 	return args.Error(0)
 }
 
+func (m *MockRatingRepo) ApplyEvent(ctx context.Context, eventID uuid.UUID, eventType, username, condition string) (int, bool, error) {
+	args := m.Called(ctx, eventID, eventType, username, condition)
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+
 // --- Тесты ---
 
 func TestGetRating_Success(t *testing.T) {
@@ -0,0 +1,33 @@
+// Package events describes the domain events rating-system consumes: the
+// returns reservation-system publishes to the Exchange topic exchange with
+// the routing key <type>.v<version>.
+package events
+
+import "platform/events"
+
+const Exchange = events.Exchange
+
+type (
+	Type     = events.Type
+	Envelope = events.Envelope
+)
+
+const (
+	ReservationReturned Type = "reservation.returned"
+	ReservationExpired  Type = "reservation.expired"
+)
+
+// ReservationV1 is the payload of every reservation event at version 1.
+// ReturnDate and Condition are only set on returned and expired events.
+type ReservationV1 struct {
+	ReservationUID string `json:"reservationUid"`
+	Username       string `json:"username"`
+	BookUID        string `json:"bookUid"`
+	LibraryUID     string `json:"libraryUid"`
+	CopyUID        string `json:"copyUid,omitempty"`
+	Status         string `json:"status"`
+	StartDate      string `json:"startDate"`
+	TillDate       string `json:"tillDate"`
+	ReturnDate     string `json:"returnDate,omitempty"`
+	Condition      string `json:"condition,omitempty"`
+}
@@ -1,11 +1,26 @@
 package main
 
 import (
+	"platform/auth"
+	"platform/logging"
+	"platform/outbox"
+	"platform/postgres"
+	"platform/tracing"
 	"reservation-system/internal/server"
-	"reservation-system/pkg/postgres"
+	"time"
 )
 
 type Config struct {
 	Server server.Server   `envconfig:"SERVER"`
 	DB     postgres.Config `envconfig:"DB"`
+	Auth   auth.Config     `envconfig:"AUTH"`
+	// RabbitMQ is where domain events are published. Without it they stay
+	// in the outbox.
+	RabbitMQ string        `envconfig:"RABBITMQ"`
+	Outbox   outbox.Config `envconfig:"OUTBOX"`
+	// RemindersInterval is how often due-soon and overdue reservations are
+	// looked for.
+	RemindersInterval time.Duration  `envconfig:"REMINDERS_INTERVAL" default:"1h"`
+	Tracing           tracing.Config `envconfig:"TRACING"`
+	Log               logging.Config `envconfig:"LOG"`
 }
@@ -2,23 +2,46 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"os"
+	"os/signal"
+	"platform/auth"
+	"platform/logging"
+	"platform/migrate"
+	"platform/outbox"
+	"platform/postgres"
+	"platform/tracing"
+	"reservation-system/internal/migrations"
+	"reservation-system/internal/repo"
 	"reservation-system/internal/server"
-	"reservation-system/pkg/postgres"
+	"reservation-system/internal/service"
+	"syscall"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	var cfg Config
 	if err := envconfig.Process("", &cfg); err != nil {
 		panic(err)
 	}
-	fmt.Println(cfg.DB)
+	if err := logging.Setup(cfg.Log); err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, "reservation-system", cfg.Tracing)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Warn("failed to flush traces")
+		}
+	}()
 
 	db, err := postgres.Connect(ctx, cfg.DB)
 	if err != nil {
@@ -26,20 +49,56 @@ func main() {
 		panic(err)
 	}
 	if err := db.Ping(ctx); err != nil {
-		log.WithError(err).Error("Failed to ping database")
+		log.WithError(err).Fatal("Failed to ping database")
 	}
 	log.Info("Successfully connected to database reservations")
 	defer db.Close()
+	prometheus.MustRegister(db.Collector())
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Command(ctx, db.Conn(), migrations.FS, os.Args[2:], os.Stdout); err != nil {
+			log.WithError(err).Fatal("migration failed")
+		}
+		return
+	}
+	if err := migrate.OnStart(ctx, db.Conn(), migrations.FS); err != nil {
+		log.WithError(err).Fatal("failed to apply migrations")
+	}
 
-	srv, err := server.New(db, cfg.Server.Host, cfg.Server.Port)
+	verifier, err := auth.NewVerifier(cfg.Auth)
 	if err != nil {
-		log.WithError(err).Error("failed to initialize server")
+		log.WithError(err).Fatal("failed to set up token verification")
 	}
 
-	err = srv.Run()
+	srv, err := server.New(db, verifier, cfg.Server.Host, cfg.Server.Port, cfg.Server.Shutdown)
 	if err != nil {
-		log.WithError(err).Error("server shutdown")
-		return
+		log.WithError(err).Fatal("failed to initialize server")
+	}
+
+	relayDone := make(chan struct{})
+	if cfg.RabbitMQ != "" {
+		relay := outbox.NewRelay(cfg.RabbitMQ, cfg.Outbox, db.Conn())
+		srv.Health().Optional("outbox", relay.Check())
+		go func() {
+			defer close(relayDone)
+			relay.Run(ctx)
+		}()
+	} else {
+		log.Warn("RABBITMQ is not set, domain events stay in the outbox")
+		close(relayDone)
 	}
 
+	remindersDone := make(chan struct{})
+	go func() {
+		defer close(remindersDone)
+		service.NewReminders(repo.NewReservationRepo(db)).Run(ctx, cfg.RemindersInterval)
+	}()
+
+	// The deferred pool close runs only after Run has drained the requests
+	// that may still be using it.
+	if err := srv.Run(ctx); err != nil {
+		log.WithError(err).Error("server shutdown")
+	}
+	<-relayDone
+	<-remindersDone
 }
@@ -1,14 +1,24 @@
 package handlers
 
 import (
+	"contract/reservation"
+	"errors"
 	"net/http"
-	"reservation-system/internal/dto"
+	"platform/auth"
+	"platform/problem"
+	"reservation-system/internal/models"
 	"reservation-system/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// staffOnly guards the routes that open, close or drop a reservation: they
+// move stock and rating along with it, so only the gateway or a librarian
+// may call them.
+var staffOnly = auth.Require(auth.Librarian, auth.Service)
+
 type ReservationHandler struct {
 	service service.ReservationServiceIFace
 }
@@ -20,110 +30,142 @@ func New(service service.ReservationServiceIFace) *ReservationHandler {
 func (h *ReservationHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	resRoutes := rg.Group("/reservation")
 	{
-		resRoutes.POST("/", h.CreateReservation)
+		resRoutes.POST("/", staffOnly, h.CreateReservation)
 		resRoutes.GET("/:uid", h.GetReservation)
 		resRoutes.GET("/", h.GetReservations)
-		resRoutes.PUT("/:uid", h.UpdateStatus)
+		resRoutes.PUT("/:uid", staffOnly, h.UpdateStatus)
 		resRoutes.GET("/amount", h.GetCurrentAmount)
-		resRoutes.DELETE("/:uid", h.DeleteReservation)
+		resRoutes.DELETE("/:uid", staffOnly, h.DeleteReservation)
 
 	}
 }
 
 type GetUIDRequest struct {
-	UID string `uri:"uid" binding:"required"`
+	UID string `uri:"uid" binding:"required,uuid"`
+}
+
+func caller(c *gin.Context) service.Caller {
+	return service.Caller{
+		Username: auth.Username(c),
+		Staff:    auth.HasRole(c, auth.Librarian),
+	}
+}
+
+func toReservationResponse(m *models.Reservation) reservation.ReservationResponse {
+	resp := reservation.ReservationResponse{
+		ReservationUid: m.ReservationUID,
+		Username:       m.Username,
+		BookUid:        m.BookUID,
+		LibraryUid:     m.LibraryUID,
+		Status:         reservation.ReservationStatus(m.Status),
+		StartDate:      openapi_types.Date{Time: m.StartDate},
+		TillDate:       openapi_types.Date{Time: m.TillDate},
+	}
+	if m.CopyUID != nil {
+		resp.CopyUid = *m.CopyUID
+	}
+	return resp
+}
+
+func toReservationsResponse(list []models.Reservation) []reservation.ReservationResponse {
+	out := make([]reservation.ReservationResponse, 0, len(list))
+	for _, r := range list {
+		out = append(out, toReservationResponse(&r))
+	}
+	return out
+}
+
+func writeReservationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTillDate), errors.Is(err, service.ErrAlreadyReturned):
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+	case errors.Is(err, service.ErrReservationNotFound):
+		problem.Write(c, http.StatusNotFound, problem.NotFound, err.Error())
+	case errors.Is(err, service.ErrNotOwner):
+		problem.Write(c, http.StatusForbidden, problem.Forbidden, err.Error())
+	default:
+		problem.WriteInternal(c, err)
+	}
 }
 
 func (h *ReservationHandler) GetReservation(c *gin.Context) {
 	var GetUIDRequest GetUIDRequest
 	if err := c.ShouldBindUri(&GetUIDRequest); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
 	}
 
-	res, err := h.service.GetReservation(c, GetUIDRequest.UID)
+	res, err := h.service.GetReservation(c, GetUIDRequest.UID, caller(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		writeReservationError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ToReservationDTO(res))
+	c.JSON(http.StatusOK, toReservationResponse(res))
 }
 
 func (h *ReservationHandler) CreateReservation(c *gin.Context) {
-	username := c.GetHeader("X-User-Name")
-	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header is required"})
-		return
-	}
-	var req dto.CreateReservationRequest
+	username := auth.Username(c)
+	var req reservation.CreateReservationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.CreateReservation(c, req, username)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeReservationError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.ToReservationDTO(res))
+	c.JSON(http.StatusCreated, toReservationResponse(res))
 }
 
 func (h *ReservationHandler) GetReservations(c *gin.Context) {
-	username := c.GetHeader("X-User-Name")
-	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header is required"})
-		return
-	}
+	username := auth.Username(c)
 
 	res, err := h.service.GetReservations(c, username)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		problem.WriteInternal(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ToReservationsDTO(res))
+	c.JSON(http.StatusOK, toReservationsResponse(res))
 }
 
 func (h *ReservationHandler) GetCurrentAmount(c *gin.Context) {
-	username := c.GetHeader("X-User-Name")
-	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-User-Name header is required"})
-		return
-	}
+	username := auth.Username(c)
 
 	amount, err := h.service.GetCurrentAmount(c, username)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.WriteInternal(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"amount": amount})
+	c.JSON(http.StatusOK, reservation.AmountResponse{Amount: int(amount)})
 }
 
 func (h *ReservationHandler) UpdateStatus(c *gin.Context) {
 	var uri GetUIDRequest
 	if err := c.ShouldBindUri(&uri); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
-	var req struct {
-		Date string `json:"date" binding:"required,datetime=2006-01-02"`
-	}
+	var req reservation.UpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
 		return
 	}
 
 	uid, err := uuid.Parse(uri.UID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UID"})
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, "invalid UID")
 		return
 	}
 
-	if err := h.service.UpdateStatus(c, uid, req.Date); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.UpdateStatus(c, uid, req, caller(c)); err != nil {
+		writeReservationError(c, err)
 		return
 	}
 
@@ -133,11 +175,13 @@ func (h *ReservationHandler) UpdateStatus(c *gin.Context) {
 func (h *ReservationHandler) DeleteReservation(c *gin.Context) {
 	var GetUIDRequest GetUIDRequest
 	if err := c.ShouldBindUri(&GetUIDRequest); err != nil {
+		problem.Write(c, http.StatusBadRequest, problem.BadRequest, err.Error())
+		return
 	}
 
-	err := h.service.DeleteReservation(c, GetUIDRequest.UID)
+	err := h.service.DeleteReservation(c, GetUIDRequest.UID, caller(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeReservationError(c, err)
 		return
 	}
 
@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"context"
+	"contract/reservation"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"platform/auth/authtest"
+	"platform/problem"
+	handlers "reservation-system/internal/handlers/http/v1"
+	"reservation-system/internal/models"
+	"reservation-system/internal/repo"
+	"reservation-system/internal/service"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingService answers every single-reservation call with err.
+type failingService struct {
+	service.ReservationServiceIFace
+	err error
+}
+
+func (s failingService) GetReservation(context.Context, string, service.Caller) (*models.Reservation, error) {
+	return nil, s.err
+}
+
+func (s failingService) DeleteReservation(context.Context, string, service.Caller) error {
+	return s.err
+}
+
+func (s failingService) CreateReservation(context.Context, reservation.CreateReservationRequest, string) (*models.Reservation, error) {
+	return nil, s.err
+}
+
+func TestReservationErrorStatus(t *testing.T) {
+	minter := authtest.NewMinter()
+	token := minter.Token(jwt.MapClaims{"sub": "user", "roles": []string{"librarian"}})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", repo.ErrReservationNotFound, http.StatusNotFound},
+		{"wrapped not found", errors.Join(errors.New("lookup"), repo.ErrReservationNotFound), http.StatusNotFound},
+		{"other user", service.ErrNotOwner, http.StatusForbidden},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, v1 := minter.Router(t, "/api/v1")
+			handlers.New(failingService{err: tt.err}).RegisterRoutes(v1)
+
+			for _, method := range []string{http.MethodGet, http.MethodDelete} {
+				w := authtest.Do(r, method, "/api/v1/reservation/"+uuid.NewString(), token, "")
+
+				assert.Equal(t, tt.want, w.Code, method)
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), method)
+			}
+		})
+	}
+}
+
+func TestCreateReservationErrorStatus(t *testing.T) {
+	minter := authtest.NewMinter()
+	token := minter.Token(jwt.MapClaims{"sub": "user", "roles": []string{"librarian"}})
+	body := `{"bookUid":"` + uuid.NewString() + `","libraryUid":"` + uuid.NewString() + `","tillDate":"2021-10-11"}`
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"till date passed", service.ErrTillDate, http.StatusBadRequest},
+		{"database down", errors.New(`pq: relation "reservation" does not exist`), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, v1 := minter.Router(t, "/api/v1")
+			handlers.New(failingService{err: tt.err}).RegisterRoutes(v1)
+
+			w := authtest.Do(r, http.MethodPost, "/api/v1/reservation/", token, body)
+			assert.Equal(t, tt.want, w.Code)
+
+			// what went wrong inside stays in the log
+			var p problem.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			if tt.want == http.StatusInternalServerError {
+				assert.NotContains(t, p.Detail, "relation")
+			}
+		})
+	}
+}
@@ -1,5 +1,3 @@
-\c reservations
-
 CREATE TABLE IF NOT EXISTS reservation
 (
     id              SERIAL PRIMARY KEY,
@@ -0,0 +1,2 @@
+ALTER TABLE reservation DROP COLUMN IF EXISTS event_seq;
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,2 @@
+ALTER TABLE reservation DROP COLUMN IF EXISTS overdue_noticed_at;
+ALTER TABLE reservation DROP COLUMN IF EXISTS due_soon_noticed_at;
@@ -0,0 +1,4 @@
+-- When the due-soon and overdue events went out for a reservation, so that
+-- every scan only picks up reservations it hasn't reported yet.
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS due_soon_noticed_at TIMESTAMP;
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS overdue_noticed_at TIMESTAMP;
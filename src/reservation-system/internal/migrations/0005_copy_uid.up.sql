@@ -0,0 +1,3 @@
+-- The copy a reservation holds, once copies are tracked one by one.
+-- Reservations made before that have none.
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS copy_uid UUID;
@@ -0,0 +1,7 @@
+// Package migrations embeds the reservation database schema history.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
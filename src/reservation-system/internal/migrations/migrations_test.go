@@ -0,0 +1,15 @@
+package migrations_test
+
+import (
+	"platform/migrate"
+	"reservation-system/internal/migrations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+}
@@ -2,9 +2,15 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"platform/postgres"
+	"platform/tracing"
 	"reservation-system/internal/models"
-	"reservation-system/pkg/postgres"
+	"reservation-system/pkg/events"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -12,13 +18,37 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrStatusConflict      = errors.New("reservation is not in the expected status")
+)
+
+// Notice is a reminder sent at most once per reservation.
+type Notice string
+
+const (
+	NoticeDueSoon Notice = "due_soon_noticed_at"
+	NoticeOverdue Notice = "overdue_noticed_at"
+)
+
 type ReservationRepo interface {
 	CreateReservation(ctx context.Context, res models.Reservation) (*models.Reservation, error)
 	GetReservationByUID(ctx context.Context, uid string) (*models.Reservation, error)
 	GetCurrentReservationsAmount(ctx context.Context, username string) (uint64, error)
 	GetReservations(ctx context.Context, username string) ([]models.Reservation, error)
-	UpdateReservationStatus(ctx context.Context, reservationUID uuid.UUID, status string) error
+	// UpdateReservationStatus moves a reservation from one status to
+	// another, failing with ErrStatusConflict when it isn't in from.
+	UpdateReservationStatus(ctx context.Context, reservationUID uuid.UUID, from, to string) error
 	Delete(ctx context.Context, reservationUID string) error
+	// ClaimNotices marks the rented reservations due in [from, to) that
+	// haven't had notice yet and returns them.
+	ClaimNotices(ctx context.Context, notice Notice, from, to time.Time) ([]models.Reservation, error)
+	// AddEvent stores an event in the outbox. Call it inside InTx together
+	// with the change the event describes.
+	AddEvent(ctx context.Context, evt events.Envelope) error
+	// InTx runs fn against a repo bound to one transaction and commits
+	// when fn succeeds.
+	InTx(ctx context.Context, fn func(tx ReservationRepo) error) error
 }
 
 type reservationRepo struct {
@@ -27,23 +57,33 @@ type reservationRepo struct {
 
 var qb = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
+// reservationColumns are the columns of models.Reservation. The table has
+// more, for the outbox and the notices, so they are always named.
+var reservationColumns = []string{
+	"id", "reservation_uid", "username", "book_uid", "library_uid", "copy_uid", "start_date", "till_date", "status",
+}
+
+// returningReservation is the suffix that reads a changed row back.
+var returningReservation = "RETURNING " + strings.Join(reservationColumns, ", ")
+
 func NewReservationRepo(conn postgres.Client) ReservationRepo {
 	return &reservationRepo{conn: conn.Conn()}
 }
 
 func (r *reservationRepo) CreateReservation(ctx context.Context, res models.Reservation) (*models.Reservation, error) {
 	query := qb.Insert("reservation").
-		Columns("username", "library_uid", "book_uid", "start_date", "till_date", "status", "reservation_uid").
+		Columns("username", "library_uid", "book_uid", "copy_uid", "start_date", "till_date", "status", "reservation_uid").
 		Values(
 			res.Username,
 			res.LibraryUID,
 			res.BookUID,
+			res.CopyUID,
 			res.StartDate,
 			res.TillDate,
 			res.Status,
 			res.ReservationUID,
 		).
-		Suffix("RETURNING *")
+		Suffix(returningReservation)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
@@ -60,12 +100,10 @@ func (r *reservationRepo) CreateReservation(ctx context.Context, res models.Rese
 }
 
 func (r *reservationRepo) GetReservationByUID(ctx context.Context, uid string) (*models.Reservation, error) {
-	query := qb.Select("id", "reservation_uid", "username",
-		"book_uid", "library_uid", "start_date", "till_date", "status").
+	query := qb.Select(reservationColumns...).
 		From("reservation").
 		Where(squirrel.Eq{"reservation_uid": uid})
 	sql, args, err := query.ToSql()
-	fmt.Println(sql)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +113,9 @@ func (r *reservationRepo) GetReservationByUID(ctx context.Context, uid string) (
 	}
 	defer rows.Close()
 	model, err := pgx.CollectOneRow[models.Reservation](rows, pgx.RowToStructByNameLax)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReservationNotFound
+	}
 	if err != nil {
 		log.WithError(err).Errorf("get reservation by uid: %s", uid)
 		return nil, err
@@ -84,8 +125,7 @@ func (r *reservationRepo) GetReservationByUID(ctx context.Context, uid string) (
 }
 
 func (r *reservationRepo) GetReservations(ctx context.Context, username string) ([]models.Reservation, error) {
-	query := qb.Select("id", "reservation_uid", "username",
-		"book_uid", "library_uid", "start_date", "till_date", "status").
+	query := qb.Select(reservationColumns...).
 		From("reservation").
 		Where(squirrel.Eq{"username": username})
 	sql, args, err := query.ToSql()
@@ -119,18 +159,21 @@ func (r *reservationRepo) GetCurrentReservationsAmount(ctx context.Context, user
 	return pgx.CollectOneRow(rows, pgx.RowTo[uint64])
 }
 
-func (r *reservationRepo) UpdateReservationStatus(ctx context.Context, reservationUID uuid.UUID, status string) error {
+func (r *reservationRepo) UpdateReservationStatus(ctx context.Context, reservationUID uuid.UUID, from, to string) error {
 	query := qb.Update("reservation").
-		Set("status", status).
-		Where(squirrel.Eq{"reservation_uid": reservationUID})
+		Set("status", to).
+		Where(squirrel.Eq{"reservation_uid": reservationUID, "status": from})
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
 	}
-	_, err = r.conn.Exec(ctx, sql, args...)
+	tag, err := r.conn.Exec(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrStatusConflict
+	}
 	return nil
 }
 
@@ -139,10 +182,79 @@ func (r *reservationRepo) Delete(ctx context.Context, reservationUID string) err
 	if err != nil {
 		return err
 	}
-	_, err = r.conn.Exec(ctx, sql, args...)
+	tag, err := r.conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReservationNotFound
+	}
+	return nil
+}
+
+func (r *reservationRepo) ClaimNotices(ctx context.Context, notice Notice, from, to time.Time) ([]models.Reservation, error) {
+	query := qb.Update("reservation").
+		Set(string(notice), squirrel.Expr("now()")).
+		Where(squirrel.Eq{"status": "RENTED", string(notice): nil}).
+		Where(squirrel.GtOrEq{"till_date": from}).
+		Where(squirrel.Lt{"till_date": to}).
+		Suffix(returningReservation)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows[models.Reservation](rows, pgx.RowToStructByName)
+}
+
+// AddEvent numbers evt after the previous events of its reservation and
+// queues it for the relay. The counter lives on the reservation row, so
+// concurrent changes to one reservation are serialized and the numbering
+// survives outbox pruning. The trace of ctx is kept with the event for the
+// relay to publish it in.
+func (r *reservationRepo) AddEvent(ctx context.Context, evt events.Envelope) error {
+	err := r.conn.QueryRow(ctx, `
+        UPDATE reservation SET event_seq = event_seq + 1
+        WHERE reservation_uid = $1
+        RETURNING event_seq;
+    `, evt.OrderingKey).Scan(&evt.Sequence)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrReservationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to number event: %w", err)
+	}
+
+	payload, err := json.Marshal(evt)
 	if err != nil {
 		return err
 	}
+	_, err = r.conn.Exec(ctx, `
+        INSERT INTO outbox (event_id, routing_key, ordering_key, sequence, correlation_id, payload, trace_context)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7);
+    `, evt.ID, evt.RoutingKey(), evt.OrderingKey, evt.Sequence, evt.CorrelationID, payload, tracing.Inject(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
 	return nil
+}
 
+func (r *reservationRepo) InTx(ctx context.Context, fn func(tx ReservationRepo) error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&reservationRepo{conn: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
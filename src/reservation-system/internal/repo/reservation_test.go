@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"reservation-system/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReservationMock(t *testing.T) (*reservationRepo, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+	return &reservationRepo{conn: mock}, mock
+}
+
+func TestUpdateReservationStatus(t *testing.T) {
+	r, mock := newReservationMock(t)
+	uid := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reservation SET status = $1 WHERE reservation_uid = $2 AND status = $3")).
+		WithArgs("RETURNED", uid.String(), "RENTED").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	assert.NoError(t, r.UpdateReservationStatus(context.Background(), uid, "RENTED", "RETURNED"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateReservationStatus_NotInStatus(t *testing.T) {
+	r, mock := newReservationMock(t)
+	uid := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reservation SET status = $1 WHERE reservation_uid = $2 AND status = $3")).
+		WithArgs("EXPIRED", uid.String(), "RENTED").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	err := r.UpdateReservationStatus(context.Background(), uid, "RENTED", "EXPIRED")
+	assert.ErrorIs(t, err, ErrStatusConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateReservation(t *testing.T) {
+	r, mock := newReservationMock(t)
+	copyUID := uuid.New()
+	res := models.Reservation{
+		ReservationUID: uuid.New(),
+		Username:       "alice",
+		BookUID:        uuid.New(),
+		LibraryUID:     uuid.New(),
+		CopyUID:        &copyUID,
+		Status:         "RENTED",
+		StartDate:      time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		TillDate:       time.Date(2021, 10, 11, 0, 0, 0, 0, time.UTC),
+	}
+
+	// the table has columns the model doesn't, so the row read back is
+	// named column by column
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO reservation")+".*"+
+		regexp.QuoteMeta("RETURNING id, reservation_uid, username, book_uid, library_uid, copy_uid, start_date, till_date, status")).
+		WithArgs(res.Username, res.LibraryUID, res.BookUID, res.CopyUID, res.StartDate, res.TillDate, res.Status, res.ReservationUID).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "reservation_uid", "username", "book_uid", "library_uid", "copy_uid", "start_date", "till_date", "status",
+		}).AddRow(int64(1), res.ReservationUID, res.Username, res.BookUID, res.LibraryUID, res.CopyUID,
+			res.StartDate, res.TillDate, res.Status))
+
+	created, err := r.CreateReservation(context.Background(), res)
+	require.NoError(t, err)
+	res.ID = 1
+	assert.Equal(t, res, *created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
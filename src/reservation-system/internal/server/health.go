@@ -0,0 +1,17 @@
+package server
+
+import (
+	"platform/health"
+	"platform/lifecycle"
+	"time"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+func (s *Server) initHealthRoutes() {
+	s.health = health.New(healthCheckTimeout)
+	s.health.Critical("server", lifecycle.Serving(&s.ready))
+	s.health.Critical("postgres", s.DB.Ping)
+
+	s.health.Register(s.GinRouter)
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"contract/reservation"
+	"platform/docs"
+)
+
+func (s *Server) initDocsRoutes() error {
+	spec, err := docs.JSON(reservation.Spec)
+	if err != nil {
+		return err
+	}
+	docs.Register(s.GinRouter, docs.Static(spec))
+	return nil
+}
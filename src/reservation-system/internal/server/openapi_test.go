@@ -0,0 +1,31 @@
+package server
+
+import (
+	"contract/reservation"
+	"platform/docs"
+	"platform/postgres"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noDB lets the routes be built without a database; nothing is served.
+type noDB struct {
+	postgres.Client
+}
+
+func (noDB) Conn() postgres.Connection { return nil }
+
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{GinRouter: gin.New(), DB: noDB{}}
+	require.NoError(t, s.initRoutes())
+
+	spec, err := docs.JSON(reservation.Spec)
+	require.NoError(t, err)
+	missing, err := docs.Undocumented(spec, s.GinRouter.Routes(), "/api/")
+	require.NoError(t, err)
+	assert.Empty(t, missing, "routes missing from openapi.yml")
+}
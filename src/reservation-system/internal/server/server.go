@@ -1,33 +1,51 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"net/http"
-	"reservation-system/internal/auth"
+	"platform/auth"
+	"platform/events"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"platform/postgres"
 	handlers "reservation-system/internal/handlers/http/v1"
 	"reservation-system/internal/repo"
 	"reservation-system/internal/service"
-	"reservation-system/pkg/postgres"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	//log "github.com/sirupsen/logrus"
 	//log "github.com/sirupsen/logrus"
 )
 
 type Server struct {
-	Host      string `envconfig:"HOST"`
-	Port      int    `envconfig:"PORT" required:"true"`
+	Host      string             `envconfig:"HOST"`
+	Port      int                `envconfig:"PORT" required:"true"`
+	Shutdown  lifecycle.Shutdown `envconfig:"SHUTDOWN"`
 	DB        postgres.Client
+	Verifier  *auth.Verifier `ignored:"true"`
 	GinRouter *gin.Engine
+	ready     atomic.Bool
+	health    *health.Checker
 }
 
-func New(dbc postgres.Client, host string, port int) (*Server, error) {
+func New(dbc postgres.Client, verifier *auth.Verifier, host string, port int, shutdown lifecycle.Shutdown) (*Server, error) {
 	s := &Server{
 		Host:      host,
 		Port:      port,
+		Shutdown:  shutdown,
 		DB:        dbc,
-		GinRouter: gin.Default(),
+		Verifier:  verifier,
+		GinRouter: gin.New(),
 	}
+	// Handlers pass the gin context on as a context.Context; fall back to
+	// the request context so values such as the correlation id reach the
+	// service.
+	s.GinRouter.ContextWithFallback = true
+	s.GinRouter.Use(logging.Middleware(), metrics.Middleware(), gin.Recovery(), otelgin.Middleware("reservation-system"))
 
 	if err := s.initRoutes(); err != nil {
 		return nil, err
@@ -41,15 +59,18 @@ func (s *Server) initRoutes() error {
 		c.JSON(200, gin.H{"msg": "pong"})
 	})
 
-	s.GinRouter.GET("/manage/health", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
+	s.initHealthRoutes()
+	s.GinRouter.GET(metrics.Path, metrics.Handler())
+	if err := s.initDocsRoutes(); err != nil {
+		return err
+	}
 
-	authMiddleware := auth.AuthMiddleware()
+	authMiddleware := auth.AuthMiddleware(s.Verifier)
 
 	v1 := s.GinRouter.Group("/api/v1")
+	v1.Use(correlation())
 
-	v1.Use(authMiddleware)
+	v1.Use(authMiddleware, auth.Require(auth.Patron, auth.Service), auth.Identity())
 
 	reservation := repo.NewReservationRepo(s.DB)
 	resService := service.NewReservationService(reservation)
@@ -60,6 +81,21 @@ func (s *Server) initRoutes() error {
 	return nil
 }
 
-func (s *Server) Run() error {
-	return s.GinRouter.Run(fmt.Sprintf("%s:%d", s.Host, s.Port))
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
+// correlation passes the request id on to the events the request produces
+// as their correlation id.
+func correlation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		c.Request = c.Request.WithContext(events.WithCorrelationID(ctx, logging.RequestID(ctx)))
+		c.Next()
+	}
+}
+
+// Run serves until ctx is cancelled, then drains as lifecycle.Run does.
+func (s *Server) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, fmt.Sprintf("%s:%d", s.Host, s.Port), s.GinRouter, s.Shutdown, &s.ready)
 }
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	reservationsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reservations_created_total",
+		Help: "Reservations made, by the status they started in.",
+	}, []string{"status"})
+
+	reservationsReturned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reservations_returned_total",
+		Help: "Books returned, by whether they came back on time or late.",
+	}, []string{"timeliness"})
+)
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"reservation-system/internal/repo"
+	"reservation-system/pkg/events"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const day = 24 * time.Hour
+
+// Reminders scans for reservations due today or tomorrow and for overdue
+// ones, and publishes an event for each the first time it is found.
+type Reminders struct {
+	repo repo.ReservationRepo
+}
+
+func NewReminders(r repo.ReservationRepo) *Reminders {
+	return &Reminders{repo: r}
+}
+
+// Run scans right away and then every interval until ctx is cancelled. A
+// reservation is only reported once, so scanning more often than daily
+// just shortens the delay after a restart.
+func (m *Reminders) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := m.Scan(ctx, time.Now()); err != nil {
+			log.WithError(err).Error("reminder scan failed")
+		} else if n > 0 {
+			log.Infof("reminder scan reported %d reservations", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan reports what is due as of now and returns how many events it
+// queued.
+func (m *Reminders) Scan(ctx context.Context, now time.Time) (int, error) {
+	today := now.UTC().Truncate(day)
+	scans := []struct {
+		notice   repo.Notice
+		typ      events.Type
+		from, to time.Time
+	}{
+		{repo.NoticeDueSoon, events.ReservationDueSoon, today, today.Add(2 * day)},
+		{repo.NoticeOverdue, events.ReservationOverdue, time.Time{}, today},
+	}
+
+	var total int
+	err := m.repo.InTx(ctx, func(tx repo.ReservationRepo) error {
+		for _, scan := range scans {
+			due, err := tx.ClaimNotices(ctx, scan.notice, scan.from, scan.to)
+			if err != nil {
+				return err
+			}
+			for _, res := range due {
+				if err := record(ctx, tx, scan.typ, eventData(&res)); err != nil {
+					return err
+				}
+			}
+			total += len(due)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,38 @@
+package service_test
+
+import (
+	"context"
+	"reservation-system/internal/models"
+	"reservation-system/internal/repo"
+	"reservation-system/internal/service"
+	"reservation-system/pkg/events"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReminders_Scan(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	now := time.Date(2025, 3, 10, 15, 4, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	dueSoon := models.Reservation{ReservationUID: uuid.New(), Username: "alice", TillDate: today.Add(24 * time.Hour)}
+	overdue := models.Reservation{ReservationUID: uuid.New(), Username: "bob", TillDate: today.Add(-72 * time.Hour)}
+	mockRepo.On("ClaimNotices", mock.Anything, repo.NoticeDueSoon, today, today.Add(48*time.Hour)).
+		Return([]models.Reservation{dueSoon}, nil)
+	mockRepo.On("ClaimNotices", mock.Anything, repo.NoticeOverdue, time.Time{}, today).
+		Return([]models.Reservation{overdue}, nil)
+
+	n, err := service.NewReminders(mockRepo).Scan(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	if assert.Len(t, mockRepo.events, 2) {
+		assert.Equal(t, events.ReservationDueSoon, mockRepo.events[0].Type)
+		assert.Equal(t, dueSoon.ReservationUID.String(), mockRepo.events[0].OrderingKey)
+		assert.Equal(t, events.ReservationOverdue, mockRepo.events[1].Type)
+		assert.Equal(t, overdue.ReservationUID.String(), mockRepo.events[1].OrderingKey)
+	}
+}
@@ -2,27 +2,39 @@ package service
 
 import (
 	"context"
+	"contract/reservation"
 	"errors"
-	"fmt"
-	"reservation-system/internal/dto"
 	"reservation-system/internal/models"
 	"reservation-system/internal/repo"
+	"reservation-system/pkg/events"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-const (
-	tillTimeError = "tillDate must be greater than startDate"
+const StatusRented = "RENTED"
+
+var (
+	ErrTillDate            = errors.New("tillDate must be greater than startDate")
+	ErrReservationNotFound = repo.ErrReservationNotFound
+	ErrNotOwner            = errors.New("reservation belongs to another user")
+	ErrAlreadyReturned     = errors.New("book has already been returned")
 )
 
+// Caller is the user a request acts for. Staff may act on reservations of
+// any user.
+type Caller struct {
+	Username string
+	Staff    bool
+}
+
 type ReservationServiceIFace interface {
-	CreateReservation(ctx context.Context, req dto.CreateReservationRequest, username string) (*models.Reservation, error)
-	GetReservation(ctx context.Context, uid string) (*models.Reservation, error)
+	CreateReservation(ctx context.Context, req reservation.CreateReservationRequest, username string) (*models.Reservation, error)
+	GetReservation(ctx context.Context, uid string, caller Caller) (*models.Reservation, error)
 	GetReservations(ctx context.Context, username string) ([]models.Reservation, error)
 	GetCurrentAmount(ctx context.Context, username string) (uint64, error)
-	UpdateStatus(ctx context.Context, reservationUID uuid.UUID, status string) error
-	DeleteReservation(ctx context.Context, reservationUID string) error
+	UpdateStatus(ctx context.Context, reservationUID uuid.UUID, req reservation.UpdateStatusRequest, caller Caller) error
+	DeleteReservation(ctx context.Context, reservationUID string, caller Caller) error
 }
 
 type reservationService struct {
@@ -33,38 +45,86 @@ func NewReservationService(r repo.ReservationRepo) ReservationServiceIFace {
 	return &reservationService{repo: r}
 }
 
-func (r *reservationService) CreateReservation(ctx context.Context, req dto.CreateReservationRequest, username string) (*models.Reservation, error) {
-	bookUID, err := uuid.Parse(req.BookUID)
-	if err != nil {
-		return nil, err
-	}
-	libraryUID, err := uuid.Parse(req.LibraryUID)
-	if err != nil {
-		return nil, err
-	}
-	tillDate, err := time.Parse("2006-01-02", req.TillDate)
-	if err != nil {
-		return nil, err
+func (r *reservationService) CreateReservation(ctx context.Context, req reservation.CreateReservationRequest, username string) (*models.Reservation, error) {
+	var copyUID *uuid.UUID
+	if req.CopyUid != uuid.Nil {
+		copyUID = &req.CopyUid
 	}
+	tillDate := req.TillDate.Time
 	startDate := time.Now().UTC().Truncate(24 * time.Hour)
 
 	if tillDate.Equal(startDate) || tillDate.Before(startDate) {
-		return nil, fmt.Errorf(tillTimeError)
+		return nil, ErrTillDate
 	}
 	res := models.Reservation{
 		ReservationUID: uuid.New(),
 		Username:       username,
-		BookUID:        bookUID,
-		LibraryUID:     libraryUID,
-		Status:         "RENTED",
+		BookUID:        req.BookUid,
+		LibraryUID:     req.LibraryUid,
+		CopyUID:        copyUID,
+		Status:         StatusRented,
 		StartDate:      startDate,
 		TillDate:       tillDate,
 	}
-	return r.repo.CreateReservation(ctx, res)
+
+	var created *models.Reservation
+	err := r.repo.InTx(ctx, func(tx repo.ReservationRepo) error {
+		var err error
+		if created, err = tx.CreateReservation(ctx, res); err != nil {
+			return err
+		}
+		return record(ctx, tx, events.ReservationCreated, eventData(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+	reservationsCreated.WithLabelValues(res.Status).Inc()
+	return created, nil
 }
 
-func (r *reservationService) GetReservation(ctx context.Context, uid string) (*models.Reservation, error) {
-	return r.repo.GetReservationByUID(ctx, uid)
+// record queues an event about a reservation in the same transaction as the
+// change it reports.
+func record(ctx context.Context, tx repo.ReservationRepo, typ events.Type, data events.ReservationV1) error {
+	evt, err := events.New(ctx, typ, data.ReservationUID, data)
+	if err != nil {
+		return err
+	}
+	return tx.AddEvent(ctx, evt)
+}
+
+func eventData(res *models.Reservation) events.ReservationV1 {
+	var copyUID string
+	if res.CopyUID != nil {
+		copyUID = res.CopyUID.String()
+	}
+	return events.ReservationV1{
+		ReservationUID: res.ReservationUID.String(),
+		Username:       res.Username,
+		BookUID:        res.BookUID.String(),
+		LibraryUID:     res.LibraryUID.String(),
+		CopyUID:        copyUID,
+		Status:         res.Status,
+		StartDate:      res.StartDate.Format("2006-01-02"),
+		TillDate:       res.TillDate.Format("2006-01-02"),
+	}
+}
+
+// owned loads a reservation the caller is allowed to act on. A missing
+// reservation is reported before ownership so that 404 and 403 keep their
+// meaning.
+func (r *reservationService) owned(ctx context.Context, uid string, caller Caller) (*models.Reservation, error) {
+	res, err := r.repo.GetReservationByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !caller.Staff && res.Username != caller.Username {
+		return nil, ErrNotOwner
+	}
+	return res, nil
+}
+
+func (r *reservationService) GetReservation(ctx context.Context, uid string, caller Caller) (*models.Reservation, error) {
+	return r.owned(ctx, uid, caller)
 }
 
 func (r *reservationService) GetReservations(ctx context.Context, username string) ([]models.Reservation, error) {
@@ -75,26 +135,49 @@ func (r *reservationService) GetCurrentAmount(ctx context.Context, username stri
 	return r.repo.GetCurrentReservationsAmount(ctx, username)
 }
 
-func (r *reservationService) UpdateStatus(ctx context.Context, reservationUID uuid.UUID, date string) error {
-	res, err := r.repo.GetReservationByUID(ctx, reservationUID.String())
+func (r *reservationService) UpdateStatus(ctx context.Context, reservationUID uuid.UUID, req reservation.UpdateStatusRequest, caller Caller) error {
+	res, err := r.owned(ctx, reservationUID.String(), caller)
 	if err != nil {
 		return err
 	}
-	if res.Status != "RENTED" {
-		return errors.New("book has already been returned")
+	returnDate := req.Date.Time
+	status, typ, timeliness := "RETURNED", events.ReservationReturned, "on_time"
+	if returnDate.After(res.TillDate) {
+		status, typ, timeliness = "EXPIRED", events.ReservationExpired, "late"
 	}
-	returnDate, err := time.Parse("2006-01-02", date)
+
+	err = r.repo.InTx(ctx, func(tx repo.ReservationRepo) error {
+		// a concurrent return may have closed it since it was read
+		err := tx.UpdateReservationStatus(ctx, reservationUID, StatusRented, status)
+		if errors.Is(err, repo.ErrStatusConflict) {
+			return ErrAlreadyReturned
+		}
+		if err != nil {
+			return err
+		}
+		data := eventData(res)
+		data.Status = status
+		data.ReturnDate = req.Date.String()
+		data.Condition = string(req.Condition)
+		return record(ctx, tx, typ, data)
+	})
 	if err != nil {
 		return err
 	}
-	status := "RETURNED"
-	fmt.Println(returnDate)
-	if returnDate.After(res.TillDate) {
-		status = "EXPIRED"
-	}
-	return r.repo.UpdateReservationStatus(ctx, reservationUID, status)
+	reservationsReturned.WithLabelValues(timeliness).Inc()
+	return nil
 }
 
-func (r *reservationService) DeleteReservation(ctx context.Context, reservationUID string) error {
-	return r.repo.Delete(ctx, reservationUID)
+func (r *reservationService) DeleteReservation(ctx context.Context, reservationUID string, caller Caller) error {
+	res, err := r.owned(ctx, reservationUID, caller)
+	if err != nil {
+		return err
+	}
+	return r.repo.InTx(ctx, func(tx repo.ReservationRepo) error {
+		// The event is numbered on the reservation row, so it goes first.
+		if err := record(ctx, tx, events.ReservationCancelled, eventData(res)); err != nil {
+			return err
+		}
+		return tx.Delete(ctx, reservationUID)
+	})
 }
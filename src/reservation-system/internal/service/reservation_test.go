@@ -2,19 +2,31 @@ package service_test
 
 import (
 	"context"
-	"reservation-system/internal/dto"
+	"contract/reservation"
+	"encoding/json"
+	platformevents "platform/events"
 	"reservation-system/internal/models"
+	"reservation-system/internal/repo"
 	"reservation-system/internal/service"
+	"reservation-system/pkg/events"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// date is the day of t as the contract carries it.
+func date(t time.Time) openapi_types.Date {
+	return openapi_types.Date{Time: t.UTC().Truncate(24 * time.Hour)}
+}
+
 type MockReservationRepo struct {
 	mock.Mock
+	events []events.Envelope
 }
 
 func (m *MockReservationRepo) CreateReservation(ctx context.Context, r models.Reservation) (*models.Reservation, error) {
@@ -37,8 +49,8 @@ func (m *MockReservationRepo) GetCurrentReservationsAmount(ctx context.Context,
 	return args.Get(0).(uint64), args.Error(1)
 }
 
-func (m *MockReservationRepo) UpdateReservationStatus(ctx context.Context, uid uuid.UUID, status string) error {
-	args := m.Called(ctx, uid, status)
+func (m *MockReservationRepo) UpdateReservationStatus(ctx context.Context, uid uuid.UUID, from, to string) error {
+	args := m.Called(ctx, uid, from, to)
 	return args.Error(0)
 }
 
@@ -47,22 +59,36 @@ func (m *MockReservationRepo) Delete(ctx context.Context, reservationUID string)
 	return args.Error(0)
 }
 
+func (m *MockReservationRepo) ClaimNotices(ctx context.Context, notice repo.Notice, from, to time.Time) ([]models.Reservation, error) {
+	args := m.Called(ctx, notice, from, to)
+	return args.Get(0).([]models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepo) AddEvent(_ context.Context, evt events.Envelope) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
+func (m *MockReservationRepo) InTx(_ context.Context, fn func(tx repo.ReservationRepo) error) error {
+	return fn(m)
+}
+
 func TestCreateReservation_Success(t *testing.T) {
 	mockRepo := new(MockReservationRepo)
 	svc := service.NewReservationService(mockRepo)
 
-	req := dto.CreateReservationRequest{
-		BookUID:    uuid.New().String(),
-		LibraryUID: uuid.New().String(),
-		TillDate:   time.Now().Add(24 * time.Hour).Format("2006-01-02"),
+	req := reservation.CreateReservationRequest{
+		BookUid:    uuid.New(),
+		LibraryUid: uuid.New(),
+		TillDate:   date(time.Now().Add(24 * time.Hour)),
 	}
 	username := "user"
 
 	expectedRes := &models.Reservation{
 		ReservationUID: uuid.New(),
 		Username:       username,
-		BookUID:        uuid.MustParse(req.BookUID),
-		LibraryUID:     uuid.MustParse(req.LibraryUID),
+		BookUID:        req.BookUid,
+		LibraryUID:     req.LibraryUid,
 		Status:         "RENTED",
 		StartDate:      time.Now().UTC().Truncate(24 * time.Hour),
 		TillDate:       time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour),
@@ -79,14 +105,36 @@ func TestCreateReservation_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateReservation_StoresCopy(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	copyUID := uuid.New()
+	req := reservation.CreateReservationRequest{
+		BookUid:    uuid.New(),
+		LibraryUid: uuid.New(),
+		CopyUid:    copyUID,
+		TillDate:   date(time.Now().Add(48 * time.Hour)),
+	}
+
+	mockRepo.On("CreateReservation", mock.Anything, mock.MatchedBy(func(r models.Reservation) bool {
+		return r.CopyUID != nil && *r.CopyUID == copyUID
+	})).Return(&models.Reservation{CopyUID: &copyUID, Status: "RENTED"}, nil)
+
+	res, err := svc.CreateReservation(context.Background(), req, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, copyUID, *res.CopyUID)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestCreateReservation_InvalidTillDate(t *testing.T) {
 	mockRepo := new(MockReservationRepo)
 	svc := service.NewReservationService(mockRepo)
 
-	req := dto.CreateReservationRequest{
-		BookUID:    uuid.New().String(),
-		LibraryUID: uuid.New().String(),
-		TillDate:   time.Now().Add(-24 * time.Hour).Format("2006-01-02"), // раньше текущей даты
+	req := reservation.CreateReservationRequest{
+		BookUid:    uuid.New(),
+		LibraryUid: uuid.New(),
+		TillDate:   date(time.Now().Add(-24 * time.Hour)), // раньше текущей даты
 	}
 	username := "user"
 
@@ -111,12 +159,33 @@ func TestUpdateStatus_ReturnedAndExpired(t *testing.T) {
 			TillDate:       tillDate,
 		}, nil)
 
-	mockRepo.On("UpdateReservationStatus", mock.Anything, reservationUID, "EXPIRED").Return(nil)
+	mockRepo.On("UpdateReservationStatus", mock.Anything, reservationUID, "RENTED", "EXPIRED").Return(nil)
+	lateBefore := counterValue(t, "reservations_returned_total", "late")
 
-	err := svc.UpdateStatus(context.Background(), reservationUID, time.Now().Format("2006-01-02"))
+	err := svc.UpdateStatus(context.Background(), reservationUID, reservation.UpdateStatusRequest{Date: date(time.Now())}, service.Caller{Staff: true})
 	assert.NoError(t, err)
 
-	mockRepo.AssertCalled(t, "UpdateReservationStatus", mock.Anything, reservationUID, "EXPIRED")
+	mockRepo.AssertCalled(t, "UpdateReservationStatus", mock.Anything, reservationUID, "RENTED", "EXPIRED")
+	assert.Equal(t, lateBefore+1, counterValue(t, "reservations_returned_total", "late"))
+}
+
+// counterValue reads the series of counter name whose only label is set to
+// label, zero while it hasn't been counted yet.
+func counterValue(t *testing.T, name, label string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if m.GetLabel()[0].GetValue() == label {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
 }
 
 func TestUpdateStatus_AlreadyReturned(t *testing.T) {
@@ -129,8 +198,10 @@ func TestUpdateStatus_AlreadyReturned(t *testing.T) {
 			ReservationUID: reservationUID,
 			Status:         "RETURNED",
 		}, nil)
+	mockRepo.On("UpdateReservationStatus", mock.Anything, reservationUID, "RENTED", mock.Anything).
+		Return(repo.ErrStatusConflict)
 
-	err := svc.UpdateStatus(context.Background(), reservationUID, time.Now().Format("2006-01-02"))
+	err := svc.UpdateStatus(context.Background(), reservationUID, reservation.UpdateStatusRequest{Date: date(time.Now())}, service.Caller{Staff: true})
 	assert.ErrorContains(t, err, "book has already been returned")
 }
 
@@ -148,3 +219,146 @@ func TestGetReservations(t *testing.T) {
 	assert.Len(t, res, 1)
 	assert.Equal(t, username, res[0].Username)
 }
+
+func TestGetReservation_Ownership(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	uid := uuid.New().String()
+	mockRepo.On("GetReservationByUID", mock.Anything, uid).
+		Return(&models.Reservation{Username: "alice", Status: "RENTED"}, nil)
+
+	res, err := svc.GetReservation(context.Background(), uid, service.Caller{Username: "alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", res.Username)
+
+	res, err = svc.GetReservation(context.Background(), uid, service.Caller{Username: "bob"})
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, service.ErrNotOwner)
+
+	res, err = svc.GetReservation(context.Background(), uid, service.Caller{Username: "librarian", Staff: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", res.Username)
+}
+
+func TestReservation_NotFound(t *testing.T) {
+	uid := uuid.New()
+	caller := service.Caller{Username: "bob"}
+
+	tests := []struct {
+		name string
+		call func(svc service.ReservationServiceIFace) error
+	}{
+		{"get", func(svc service.ReservationServiceIFace) error {
+			_, err := svc.GetReservation(context.Background(), uid.String(), caller)
+			return err
+		}},
+		{"return", func(svc service.ReservationServiceIFace) error {
+			return svc.UpdateStatus(context.Background(), uid, reservation.UpdateStatusRequest{Date: date(time.Now())}, caller)
+		}},
+		{"delete", func(svc service.ReservationServiceIFace) error {
+			return svc.DeleteReservation(context.Background(), uid.String(), caller)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockReservationRepo)
+			mockRepo.On("GetReservationByUID", mock.Anything, uid.String()).
+				Return((*models.Reservation)(nil), repo.ErrReservationNotFound)
+
+			err := tt.call(service.NewReservationService(mockRepo))
+			assert.ErrorIs(t, err, service.ErrReservationNotFound)
+			assert.NotErrorIs(t, err, service.ErrNotOwner)
+		})
+	}
+}
+
+func TestUpdateStatus_ConcurrentReturn(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	reservationUID := uuid.New()
+	// read as rented, closed by a concurrent return before the update
+	mockRepo.On("GetReservationByUID", mock.Anything, reservationUID.String()).
+		Return(&models.Reservation{ReservationUID: reservationUID, Username: "alice", Status: "RENTED",
+			TillDate: time.Now().Add(48 * time.Hour)}, nil)
+	mockRepo.On("UpdateReservationStatus", mock.Anything, reservationUID, "RENTED", "RETURNED").
+		Return(repo.ErrStatusConflict)
+
+	err := svc.UpdateStatus(context.Background(), reservationUID, reservation.UpdateStatusRequest{Date: date(time.Now())}, service.Caller{Username: "alice"})
+	assert.ErrorIs(t, err, service.ErrAlreadyReturned)
+	assert.Empty(t, mockRepo.events, "no event for a return that didn't happen")
+}
+
+func TestUpdateStatus_OtherUser(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	reservationUID := uuid.New()
+	mockRepo.On("GetReservationByUID", mock.Anything, reservationUID.String()).
+		Return(&models.Reservation{ReservationUID: reservationUID, Username: "alice", Status: "RENTED"}, nil)
+
+	err := svc.UpdateStatus(context.Background(), reservationUID, reservation.UpdateStatusRequest{Date: date(time.Now())}, service.Caller{Username: "bob"})
+	assert.ErrorIs(t, err, service.ErrNotOwner)
+	mockRepo.AssertNotCalled(t, "UpdateReservationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteReservation_OtherUser(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	uid := uuid.New().String()
+	mockRepo.On("GetReservationByUID", mock.Anything, uid).
+		Return(&models.Reservation{Username: "alice"}, nil)
+
+	err := svc.DeleteReservation(context.Background(), uid, service.Caller{Username: "bob"})
+	assert.ErrorIs(t, err, service.ErrNotOwner)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestReservationEvents(t *testing.T) {
+	mockRepo := new(MockReservationRepo)
+	svc := service.NewReservationService(mockRepo)
+
+	uid := uuid.New()
+	created := &models.Reservation{ReservationUID: uid, Username: "alice", Status: service.StatusRented,
+		TillDate: time.Now().Add(48 * time.Hour).UTC().Truncate(24 * time.Hour)}
+	mockRepo.On("CreateReservation", mock.Anything, mock.Anything).Return(created, nil)
+	mockRepo.On("GetReservationByUID", mock.Anything, uid.String()).Return(created, nil)
+	mockRepo.On("UpdateReservationStatus", mock.Anything, uid, "RENTED", "RETURNED").Return(nil)
+	mockRepo.On("Delete", mock.Anything, uid.String()).Return(nil).Run(func(mock.Arguments) {
+		assert.Equal(t, events.ReservationCancelled, mockRepo.events[len(mockRepo.events)-1].Type,
+			"the cancel event is stored before the row goes")
+	})
+
+	ctx := platformevents.WithCorrelationID(context.Background(), "corr-1")
+	_, err := svc.CreateReservation(ctx, reservation.CreateReservationRequest{
+		BookUid:    uuid.New(),
+		LibraryUid: uuid.New(),
+		TillDate:   date(created.TillDate),
+	}, "alice")
+	assert.NoError(t, err)
+	assert.NoError(t, svc.UpdateStatus(ctx, uid, reservation.UpdateStatusRequest{
+		Date:      date(time.Now()),
+		Condition: "BAD",
+	}, service.Caller{Staff: true}))
+	assert.NoError(t, svc.DeleteReservation(ctx, uid.String(), service.Caller{Staff: true}))
+
+	if assert.Len(t, mockRepo.events, 3) {
+		types := []events.Type{events.ReservationCreated, events.ReservationReturned, events.ReservationCancelled}
+		for i, evt := range mockRepo.events {
+			assert.Equal(t, types[i], evt.Type)
+			assert.Equal(t, uid.String(), evt.OrderingKey)
+			assert.Equal(t, "corr-1", evt.CorrelationID)
+			assert.Equal(t, 1, evt.Version)
+		}
+		assert.Equal(t, "reservation.returned.v1", mockRepo.events[1].RoutingKey())
+		assert.JSONEq(t, `"BAD"`, string(jsonField(t, mockRepo.events[1].Data, "condition")))
+	}
+}
+
+func jsonField(t *testing.T, data []byte, key string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	return fields[key]
+}
@@ -0,0 +1,50 @@
+// Package events defines the domain events reservation-system publishes. They
+// travel in the envelope of platform/events.
+package events
+
+import (
+	"context"
+	"platform/events"
+)
+
+const (
+	Exchange = events.Exchange
+	Source   = "reservation-system"
+)
+
+type (
+	Type     = events.Type
+	Envelope = events.Envelope
+)
+
+const (
+	ReservationCreated   Type = "reservation.created"
+	ReservationReturned  Type = "reservation.returned"
+	ReservationExpired   Type = "reservation.expired"
+	ReservationCancelled Type = "reservation.cancelled"
+	// ReservationDueSoon and ReservationOverdue come from the reminder scan,
+	// at most once per reservation each.
+	ReservationDueSoon Type = "reservation.due_soon"
+	ReservationOverdue Type = "reservation.overdue"
+)
+
+// ReservationV1 is the payload of every reservation event at version 1.
+// ReturnDate and Condition are only set on returned and expired events.
+type ReservationV1 struct {
+	ReservationUID string `json:"reservationUid"`
+	Username       string `json:"username"`
+	BookUID        string `json:"bookUid"`
+	LibraryUID     string `json:"libraryUid"`
+	CopyUID        string `json:"copyUid,omitempty"`
+	Status         string `json:"status"`
+	StartDate      string `json:"startDate"`
+	TillDate       string `json:"tillDate"`
+	ReturnDate     string `json:"returnDate,omitempty"`
+	Condition      string `json:"condition,omitempty"`
+}
+
+// New wraps data at version 1. The sequence is assigned when the event is
+// stored.
+func New(ctx context.Context, typ Type, orderingKey string, data any) (Envelope, error) {
+	return events.New(ctx, Source, typ, orderingKey, data)
+}